@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/importer"
+	"gerador-query-darm-go/processor"
+	"gerador-query-darm-go/validation"
+)
+
+// runImportCommand implementa o comando "import": gera os scripts SQL, as
+// exportações e o relatório a partir de DARMs em CSV, JSON ou JSONL (planilhas
+// do banco ou dados digitados de guias em papel), sem extração de PDF
+func runImportCommand(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Arquivo de configuração")
+	format := flags.String("format", "", "Formato da entrada: csv, json ou jsonl (padrão: extensão do arquivo)")
+	outputDir := flags.String("output", "", "Diretório dos scripts gerados (padrão: paths.output_dir)")
+	conflict := flags.String("conflict", "", "Tratamento de guias duplicadas: insert, ignore, update ou not_exists (padrão: config)")
+	dialect := flags.String("dialect", "", "Banco de destino dos scripts: mysql, postgres, sqlserver ou oracle (padrão: config)")
+	export := flags.String("export", "", "Formatos de exportação separados por vírgula: json, jsonl, csv (padrão: config)")
+	strict := flags.Bool("strict", false, "Rejeita registros com dados ausentes ou inválidos em vez de aplicar valores padrão")
+	overridesFile := flags.String("overrides", "", "Arquivo de correções manuais (.csv ou .json) por origem do registro (padrão: config)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Uso: darm-processor import [opções] arquivo.csv|arquivo.json|arquivo.jsonl ...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("informe ao menos um arquivo de entrada")
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if *conflict != "" {
+		cfg.SQL.ConflictStrategy = *conflict
+	}
+	if *dialect != "" {
+		cfg.SQL.Dialect = *dialect
+	}
+	if *export != "" {
+		cfg.Output.Formats = strings.Split(*export, ",")
+	}
+	if *strict {
+		cfg.Validation.Mode = validation.ModeStrict
+	}
+	if *overridesFile != "" {
+		cfg.Paths.OverridesFile = *overridesFile
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	registros := []importer.Registro{}
+	for _, path := range flags.Args() {
+		lidos, err := importer.ReadFile(path, *format)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("📥 %d registro(s) lido(s) de %s", len(lidos), path)
+		registros = append(registros, lidos...)
+	}
+	if len(registros) == 0 {
+		return fmt.Errorf("nenhum registro encontrado na entrada")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dp := processor.NewDarmProcessorWithConfig(cfg)
+	if *outputDir != "" {
+		dp.OutputDir = *outputDir
+	}
+	if err := dp.Init(); err != nil {
+		return err
+	}
+	if err := dp.ProcessDocumentos(ctx, importer.Documentos(registros)); err != nil {
+		return err
+	}
+
+	logrus.Info("✅ Importação concluída com sucesso!")
+	return nil
+}
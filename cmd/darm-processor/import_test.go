@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/config"
+	"gerador-query-darm-go/output"
+)
+
+func TestRunImportCommand(t *testing.T) {
+	// O processador cria o diretório darms no diretório de trabalho
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	configPath := filepath.Join(dir, "config.json")
+	cfg := config.Default()
+	content, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	input := filepath.Join(dir, "planilha.csv")
+	csv := "inscricao;codigoReceita;valorPrincipal;valorTotal;dataVencimento;exercicio;numeroGuia;competencia\n" +
+		"90002;2623;10,00;10,00;15/12/2024;2025;2;11/2024\n" +
+		"90001;2623;1.234,56;1.234,56;15/12/2024;2025;1;11/2024\n" +
+		"90003;2623;;;15/12/2024;2025;;11/2024\n"
+	if err := os.WriteFile(input, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outputDir := filepath.Join(dir, "inserts")
+	if err := runImportCommand([]string{"-config", configPath, "-output", outputDir, "-strict", input}); err != nil {
+		t.Fatalf("runImportCommand falhou: %v", err)
+	}
+
+	script, err := os.ReadFile(filepath.Join(outputDir, "INSERT_TODOS_DARMs.sql"))
+	if err != nil {
+		t.Fatalf("arquivo SQL único deveria ser gerado: %v", err)
+	}
+	if !strings.Contains(string(script), "90001") || !strings.Contains(string(script), "90002") {
+		t.Errorf("script sem as guias importadas:\n%s", script)
+	}
+
+	content, err = os.ReadFile(filepath.Join(outputDir, output.RelatorioBase+".json"))
+	if err != nil {
+		t.Fatalf("relatório deveria ser gerado: %v", err)
+	}
+	var relatorio output.Relatorio
+	if err := json.Unmarshal(content, &relatorio); err != nil {
+		t.Fatal(err)
+	}
+	if relatorio.Validos != 2 || relatorio.ComErro != 1 {
+		t.Errorf("relatório inesperado: %d válido(s), %d com erro", relatorio.Validos, relatorio.ComErro)
+	}
+
+	if err := runImportCommand([]string{"-config", configPath, "-output", outputDir}); err == nil {
+		t.Error("import sem arquivo de entrada deveria falhar")
+	}
+}
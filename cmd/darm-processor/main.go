@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/config"
+	"gerador-query-darm-go/processor"
+	"gerador-query-darm-go/server"
+	"gerador-query-darm-go/validation"
+)
+
+// version é definida no build com -ldflags "-X main.version=..."
+var version = "1.0.0"
+
+// commands são os subcomandos disponíveis além do processamento padrão
+var commands = map[string]func(args []string) error{
+	"import":    runImportCommand,
+	"reconcile": runReconcileCommand,
+	"retorno":   runRetornoCommand,
+	"rollback":  runRollbackCommand,
+	"serve":     runServeCommand,
+	"verify":    runVerifyCommand,
+}
+
+func main() {
+	// Configurar logging
+	logrus.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	logrus.SetLevel(logrus.InfoLevel)
+
+	logrus.Infof("🚀 Processador de DARMs - Versão Go %s", version)
+	logrus.Infof("💻 Sistema: %s/%s", runtime.GOOS, runtime.GOARCH)
+
+	// Subcomando explícito (ex.: darm-processor rollback -input ...)
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		command, ok := commands[os.Args[1]]
+		if !ok {
+			logrus.Fatalf("❌ Comando desconhecido: %s", os.Args[1])
+		}
+		if err := command(os.Args[2:]); err != nil {
+			logrus.Fatalf("❌ Erro no comando %s: %v", os.Args[1], err)
+		}
+		return
+	}
+
+	runProcess(os.Args[1:])
+}
+
+// loadConfig carrega a configuração e aplica as opções de logging
+func loadConfig(path string) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := configureLogging(cfg.Logging); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// configureLogging aplica o nível, o formato (text ou json) e o arquivo de
+// log da configuração; o arquivo recebe uma cópia do que vai para o terminal
+func configureLogging(logging config.LoggingConfig) error {
+	level, err := logrus.ParseLevel(logging.Level)
+	if err != nil {
+		return fmt.Errorf("logging.level inválido: %q", logging.Level)
+	}
+	logrus.SetLevel(level)
+
+	if logging.Format == "json" {
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true, ForceColors: logging.OutputFile == ""})
+	}
+
+	if logging.OutputFile != "" {
+		file, err := os.OpenFile(logging.OutputFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("erro ao abrir arquivo de log: %v", err)
+		}
+		logrus.SetOutput(io.MultiWriter(os.Stderr, file))
+	}
+	return nil
+}
+
+// timeoutSeconds converte o tempo limite de uma flag para os segundos da
+// configuração, rejeitando valores que não sejam segundos inteiros e positivos
+// (abaixo de 1s a conversão resultaria em 0, que desativa o limite)
+func timeoutSeconds(flagName string, timeout time.Duration) (int, error) {
+	if timeout < time.Second || timeout%time.Second != 0 {
+		return 0, fmt.Errorf("-%s deve ser um número inteiro de segundos, ao menos 1s: %s", flagName, timeout)
+	}
+	return int(timeout / time.Second), nil
+}
+
+// runProcess processa os PDFs do diretório darms (comando padrão)
+func runProcess(args []string) {
+	flags := flag.NewFlagSet("darm-processor", flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Arquivo de configuração")
+	workers := flags.Int("workers", 0, "Número de PDFs processados em paralelo (padrão: config ou GOMAXPROCS)")
+	timeout := flags.Duration("timeout", 0, "Tempo limite por PDF, ex.: 30s (padrão: config)")
+	conflict := flags.String("conflict", "", "Tratamento de guias duplicadas: insert, ignore, update ou not_exists (padrão: config)")
+	dialect := flags.String("dialect", "", "Banco de destino dos scripts: mysql, postgres, sqlserver ou oracle (padrão: config)")
+	bulkLoad := flags.Bool("bulk-load", false, "Gera também LOAD_TODOS_DARMs.tsv + LOAD_TODOS_DARMs.sql (LOAD DATA LOCAL INFILE)")
+	export := flags.String("export", "", "Formatos de exportação separados por vírgula: json, jsonl, csv (padrão: config)")
+	decimalComma := flags.Bool("decimal-comma", false, "Usa vírgula como separador decimal no CSV exportado")
+	strict := flags.Bool("strict", false, "Rejeita PDFs com dados ausentes ou inválidos em vez de aplicar valores padrão")
+	overridesFile := flags.String("overrides", "", "Arquivo de correções manuais (.csv ou .json) por hash ou nome do PDF (padrão: config)")
+	healthCheck := flags.Bool("health-check", false, "Consulta /healthz da API (comando serve) e sai com código 0 se ela estiver saudável")
+	flags.Parse(args)
+
+	// Carregar configuração
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		logrus.Fatalf("❌ Erro ao carregar configuração: %v", err)
+	}
+	if *healthCheck {
+		if err := server.HealthCheck(cfg.Server.Addr, 3*time.Second); err != nil {
+			logrus.Fatalf("❌ Health check falhou: %v", err)
+		}
+		logrus.Info("✅ API saudável")
+		return
+	}
+	if *workers > 0 {
+		cfg.Processing.Workers = *workers
+	}
+	if *timeout != 0 {
+		seconds, err := timeoutSeconds("timeout", *timeout)
+		if err != nil {
+			logrus.Fatalf("❌ %v", err)
+		}
+		cfg.Processing.TimeoutSeconds = seconds
+	}
+	if *conflict != "" {
+		cfg.SQL.ConflictStrategy = *conflict
+	}
+	if *dialect != "" {
+		cfg.SQL.Dialect = *dialect
+	}
+	if *bulkLoad {
+		cfg.SQL.BulkLoad = true
+	}
+	if *export != "" {
+		cfg.Output.Formats = strings.Split(*export, ",")
+	}
+	if *decimalComma {
+		cfg.Output.CSVDecimalComma = true
+	}
+	if *strict {
+		cfg.Validation.Mode = validation.ModeStrict
+	}
+	if *overridesFile != "" {
+		cfg.Paths.OverridesFile = *overridesFile
+	}
+	if err := cfg.Validate(); err != nil {
+		logrus.Fatalf("❌ Configuração inválida: %v", err)
+	}
+
+	// Interromper de forma limpa em SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Criar processador
+	dp := processor.NewDarmProcessorWithConfig(cfg)
+
+	// Inicializar
+	if err := dp.Init(); err != nil {
+		logrus.Fatalf("❌ Erro ao inicializar: %v", err)
+	}
+
+	// Processar DARMs
+	if err := dp.ProcessDarms(ctx); err != nil {
+		if errors.Is(err, context.Canceled) {
+			logrus.Warn("⚠️ Processamento cancelado pelo usuário - relatório gerado com os arquivos concluídos")
+			stop()
+			os.Exit(130)
+		}
+		logrus.Fatalf("❌ Erro durante o processamento: %v", err)
+	}
+
+	logrus.Info("✅ Processamento concluído com sucesso!")
+}
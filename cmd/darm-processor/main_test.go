@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeoutSeconds(t *testing.T) {
+	tests := map[time.Duration]int{
+		time.Second:      1,
+		30 * time.Second: 30,
+		2 * time.Minute:  120,
+	}
+	for timeout, want := range tests {
+		if got, err := timeoutSeconds("timeout", timeout); err != nil || got != want {
+			t.Errorf("timeoutSeconds(%s) = %d, %v; esperado %d", timeout, got, err, want)
+		}
+	}
+
+	// Abaixo de 1s a conversão para segundos desativaria o limite
+	for _, timeout := range []time.Duration{500 * time.Millisecond, 1500 * time.Millisecond, -time.Second} {
+		if _, err := timeoutSeconds("timeout", timeout); err == nil {
+			t.Errorf("timeoutSeconds(%s) deveria falhar", timeout)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/conciliacao"
+	"gerador-query-darm-go/config"
+	"gerador-query-darm-go/importer"
+	"gerador-query-darm-go/processor"
+	"gerador-query-darm-go/retorno"
+)
+
+// runReconcileCommand implementa o comando "reconcile": confronta os DARMs
+// emitidos (PDFs de darms ou planilhas importadas) com os pagamentos dos
+// arquivos de retorno do banco, gerando o relatório de conciliação e o SQL
+// apenas dos DARMs pagos pelo valor emitido
+func runReconcileCommand(args []string) error {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Arquivo de configuração")
+	darmsDir := flags.String("darms", "", "Diretório dos PDFs dos DARMs emitidos (padrão: paths.darms_dir)")
+	input := flags.String("input", "", "Lê os DARMs emitidos deste arquivo CSV, JSON ou JSONL em vez dos PDFs")
+	outputDir := flags.String("output", "", "Diretório do relatório e do SQL da conciliação (padrão: paths.output_dir)")
+	conflict := flags.String("conflict", "", "Tratamento de guias duplicadas: insert, ignore, update ou not_exists (padrão: config)")
+	dialect := flags.String("dialect", "", "Banco de destino dos scripts: mysql, postgres, sqlserver ou oracle (padrão: config)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Uso: darm-processor reconcile [opções] arquivo.ret ...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("informe ao menos um arquivo de retorno")
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if *conflict != "" {
+		cfg.SQL.ConflictStrategy = *conflict
+	}
+	if *dialect != "" {
+		cfg.SQL.Dialect = *dialect
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if *darmsDir == "" {
+		*darmsDir = cfg.DarmsDir()
+	}
+	if *outputDir == "" {
+		*outputDir = cfg.OutputDir()
+	}
+	opts, err := cfg.ScriptOptions()
+	if err != nil {
+		return err
+	}
+
+	// Ler os retornos antes da extração: um arquivo inválido interrompe logo
+	arquivos := []*retorno.Arquivo{}
+	for _, path := range flags.Args() {
+		arquivo, err := retorno.ReadFile(path, cfg.RetornoOptions())
+		if err != nil {
+			return err
+		}
+		logrus.Infof("🏦 %s (%s): %d pagamento(s), total R$ %s", arquivo.Nome, arquivo.Layout, len(arquivo.Pagamentos), arquivo.ValorTotal)
+		arquivos = append(arquivos, arquivo)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	darms, err := darmsEmitidos(ctx, cfg, *darmsDir, *input)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de saída: %v", err)
+	}
+
+	c := conciliacao.Conciliar(darms, arquivos)
+	if err := conciliacao.WriteRelatorio(*outputDir, c); err != nil {
+		return err
+	}
+	if err := conciliacao.WriteSQL(*outputDir, c, opts); err != nil {
+		return err
+	}
+
+	logrus.Info("✅ Conciliação concluída!")
+	return nil
+}
+
+// darmsEmitidos extrai os DARMs emitidos dos PDFs (ou da planilha informada),
+// processando em um diretório temporário para não sobrescrever os scripts de
+// inserts. DARMs com falha de extração não podem ser conciliados e são listados.
+func darmsEmitidos(ctx context.Context, cfg *config.Config, darmsDir, input string) ([]conciliacao.Darm, error) {
+	var docs []processor.Documento
+	if input != "" {
+		registros, err := importer.ReadFile(input, "")
+		if err != nil {
+			return nil, err
+		}
+		docs = importer.Documentos(registros)
+	} else {
+		lidos, err := processor.ListDiretorio(darmsDir)
+		if err != nil {
+			return nil, err
+		}
+		docs = lidos
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("nenhum DARM emitido encontrado para conciliar")
+	}
+
+	tempDir, err := os.MkdirTemp("", "darm-reconcile-")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório temporário: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Apenas os registros interessam: sem exportações
+	extracaoCfg := *cfg
+	extracaoCfg.Output.Formats = nil
+
+	dp := processor.NewDarmProcessorWithConfig(&extracaoCfg)
+	dp.DarmsDir = tempDir
+	dp.OutputDir = tempDir
+	if err := dp.Init(); err != nil {
+		return nil, err
+	}
+	if err := dp.ProcessDocumentos(ctx, docs); err != nil {
+		return nil, err
+	}
+
+	for _, falha := range dp.Falhas {
+		logrus.Warnf("⚠️ %s não será conciliado: %s", falha.Arquivo, falha.Erro)
+	}
+	darms := make([]conciliacao.Darm, 0, len(dp.Resultados))
+	for _, resultado := range dp.Resultados {
+		darms = append(darms, conciliacao.Darm{Arquivo: resultado.Arquivo, Registro: resultado.Registro})
+	}
+	return darms, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gerador-query-darm-go/conciliacao"
+	"gerador-query-darm-go/config"
+	"gerador-query-darm-go/internal/darmtest"
+)
+
+func TestRunReconcileCommand(t *testing.T) {
+	// O processador cria o diretório darms no diretório de trabalho
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	configPath := filepath.Join(dir, "config.json")
+	cfg := config.Default()
+	cfg.Retorno.GuiaInicio, cfg.Retorno.GuiaTamanho = 20, 10
+	content, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	input := filepath.Join(dir, "emitidos.csv")
+	csv := "inscricao;codigoReceita;valorPrincipal;valorTotal;dataVencimento;exercicio;numeroGuia;codigoBarras\n" +
+		"90001;2623;1.234,56;1.234,56;15/12/2024;2025;1;" + darmtest.CodigoBarras(1, 123456) + "\n" +
+		"90002;2623;10,00;10,00;15/12/2024;2025;2;" + darmtest.CodigoBarras(2, 1000) + "\n"
+	if err := os.WriteFile(input, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ret := filepath.Join(dir, "RET0731.ret")
+	pagamentos := []darmtest.PagamentoRetorno{
+		{CodigoBarras: darmtest.CodigoBarras(1, 123456), Centavos: 123456, Data: time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC), Autenticacao: "AUT0001"},
+	}
+	if err := os.WriteFile(ret, []byte(darmtest.RetornoFebraban150(104, 731, pagamentos)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outputDir := filepath.Join(dir, "conciliacao")
+	if err := runReconcileCommand([]string{"-config", configPath, "-input", input, "-output", outputDir, ret}); err != nil {
+		t.Fatalf("runReconcileCommand falhou: %v", err)
+	}
+
+	content, err = os.ReadFile(filepath.Join(outputDir, conciliacao.RelatorioBase+".json"))
+	if err != nil {
+		t.Fatalf("relatório de conciliação deveria ser gerado: %v", err)
+	}
+	var c conciliacao.Conciliacao
+	if err := json.Unmarshal(content, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Contagem(conciliacao.StatusPago) != 1 || c.Contagem(conciliacao.StatusNaoPago) != 1 {
+		t.Errorf("conciliação inesperada: %s", content)
+	}
+
+	script, err := os.ReadFile(filepath.Join(outputDir, conciliacao.InsertFile))
+	if err != nil {
+		t.Fatalf("SQL dos pagamentos confirmados deveria ser gerado: %v", err)
+	}
+	if !strings.Contains(string(script), "90001") || strings.Contains(string(script), "90002") {
+		t.Errorf("SQL deveria conter apenas o DARM pago:\n%s", script)
+	}
+	// O processamento dos DARMs emitidos não grava em inserts
+	if _, err := os.Stat(filepath.Join(dir, "inserts", "INSERT_TODOS_DARMs.sql")); !os.IsNotExist(err) {
+		t.Errorf("reconcile não deveria gerar o script de inserts: %v", err)
+	}
+
+	if err := runReconcileCommand([]string{"-config", configPath}); err == nil {
+		t.Error("reconcile sem arquivo de retorno deveria falhar")
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/retorno"
+)
+
+// runRetornoCommand implementa o comando "retorno": lê e confere arquivos de
+// retorno de arrecadação do banco (FEBRABAN 150 ou CNAB 240), mostrando o lote
+// e os totais, e opcionalmente grava os pagamentos em JSON
+func runRetornoCommand(args []string) error {
+	flags := flag.NewFlagSet("retorno", flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Arquivo de configuração")
+	jsonPath := flags.String("json", "", "Grava os arquivos lidos e seus pagamentos neste arquivo JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Uso: darm-processor retorno [opções] arquivo.ret ...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("informe ao menos um arquivo de retorno")
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	arquivos := []*retorno.Arquivo{}
+	for _, path := range flags.Args() {
+		arquivo, err := retorno.ReadFile(path, cfg.RetornoOptions())
+		if err != nil {
+			return err
+		}
+		lote := arquivo.Lote()
+		logrus.Infof("🏦 %s (%s): banco %03d %s, NSA %d, gerado em %s", arquivo.Nome, arquivo.Layout, arquivo.Banco, arquivo.NomeBanco, arquivo.NSA, arquivo.DataGeracao.Format("02/01/2006"))
+		logrus.Infof("💰 %d pagamento(s), total R$ %s (conferido com o trailer); lote CD_BANCO=%d NR_BDA=%d NR_LOTE_NSA=%d", len(arquivo.Pagamentos), arquivo.ValorTotal, lote.CodigoBanco, lote.NumeroBDA, lote.NSA)
+		arquivos = append(arquivos, arquivo)
+	}
+
+	if *jsonPath == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(arquivos, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao gerar JSON dos pagamentos: %v", err)
+	}
+	if err := os.WriteFile(*jsonPath, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("erro ao gravar JSON dos pagamentos: %v", err)
+	}
+	logrus.Infof("📄 Pagamentos gravados em %s", *jsonPath)
+	return nil
+}
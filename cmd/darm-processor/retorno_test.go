@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gerador-query-darm-go/internal/darmtest"
+	"gerador-query-darm-go/retorno"
+)
+
+func TestRunRetornoCommand(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "RET0731.ret")
+	pagamentos := []darmtest.PagamentoRetorno{
+		{CodigoBarras: darmtest.CodigoBarras(7, 1000), Centavos: 1000, Data: time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC), Autenticacao: "AUT0001"},
+	}
+	if err := os.WriteFile(input, []byte(darmtest.RetornoFebraban150(104, 731, pagamentos)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	jsonPath := filepath.Join(dir, "pagamentos.json")
+	configPath := filepath.Join(dir, "config.json")
+	if err := runRetornoCommand([]string{"-config", configPath, "-json", jsonPath, input}); err != nil {
+		t.Fatalf("runRetornoCommand falhou: %v", err)
+	}
+
+	content, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("JSON dos pagamentos deveria ser gerado: %v", err)
+	}
+	var arquivos []retorno.Arquivo
+	if err := json.Unmarshal(content, &arquivos); err != nil {
+		t.Fatal(err)
+	}
+	if len(arquivos) != 1 || arquivos[0].NSA != 731 || len(arquivos[0].Pagamentos) != 1 || arquivos[0].Pagamentos[0].Autenticacao != "AUT0001" {
+		t.Errorf("JSON inesperado: %s", content)
+	}
+
+	if err := runRetornoCommand([]string{"-config", configPath}); err == nil {
+		t.Error("retorno sem arquivo deveria falhar")
+	}
+}
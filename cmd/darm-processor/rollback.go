@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gerador-query-darm-go/sqlgen"
+)
+
+// runRollbackCommand implementa o comando "rollback": regenera o script de
+// rollback a partir de um INSERT_TODOS_DARMs.sql existente
+func runRollbackCommand(args []string) error {
+	flags := flag.NewFlagSet("rollback", flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Arquivo de configuração")
+	input := flags.String("input", "", "Script de INSERT de origem (padrão: INSERT_TODOS_DARMs.sql em paths.output_dir)")
+	output := flags.String("output", "", "Script de rollback a gerar (padrão: ROLLBACK_TODOS_DARMs.sql ao lado do script de origem)")
+	flags.Parse(args)
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if *input == "" {
+		*input = filepath.Join(cfg.OutputDir(), "INSERT_TODOS_DARMs.sql")
+	}
+	opts, err := cfg.ScriptOptions()
+	if err != nil {
+		return err
+	}
+
+	script, err := os.ReadFile(*input)
+	if err != nil {
+		return fmt.Errorf("erro ao ler script de INSERT: %v", err)
+	}
+
+	keys, err := sqlgen.RollbackKeysFromScript(string(script))
+	if err != nil {
+		return fmt.Errorf("erro ao interpretar %s: %v", filepath.Base(*input), err)
+	}
+
+	path := *output
+	if path == "" {
+		path = filepath.Join(filepath.Dir(*input), "ROLLBACK_TODOS_DARMs.sql")
+	}
+	return sqlgen.WriteRollbackScript(path, keys, opts)
+}
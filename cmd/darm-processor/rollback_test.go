@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"gerador-query-darm-go/config"
+	"gerador-query-darm-go/internal/darmtest"
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/validation"
+)
+
+func TestRunRollbackCommand(t *testing.T) {
+	opts, err := config.Default().ScriptOptions()
+	if err != nil {
+		t.Fatalf("ScriptOptions falhou: %v", err)
+	}
+
+	rows := []sqlgen.DarmRow{}
+	for i, name := range []string{"0001.pdf", "0002.pdf", "0003.pdf"} {
+		record, err := validation.Parse(darmtest.DarmData(name), validation.Options{})
+		if err != nil {
+			t.Fatalf("Parse falhou: %v", err)
+		}
+		row := sqlgen.RowFromRecord(record)
+		row["SQ_DOC"] = (i + 1) * 1000
+		rows = append(rows, row)
+	}
+
+	script, err := sqlgen.Generate(rows, opts)
+	if err != nil {
+		t.Fatalf("Generate falhou: %v", err)
+	}
+	dir := t.TempDir()
+	input := filepath.Join(dir, "INSERT_TODOS_DARMs.sql")
+	if err := os.WriteFile(input, script, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(dir, "rollback.sql")
+	err = runRollbackCommand([]string{
+		"-config", filepath.Join(dir, "inexistente.json"),
+		"-input", input,
+		"-output", output,
+	})
+	if err != nil {
+		t.Fatalf("runRollbackCommand falhou: %v", err)
+	}
+
+	regenerated, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("erro ao ler rollback regenerado: %v", err)
+	}
+	if expected := sqlgen.RenderRollbackScript(sqlgen.RollbackKeysFromRows(rows), opts); string(regenerated) != expected {
+		t.Errorf("rollback regenerado difere do original:\n%s\n---\n%s", regenerated, expected)
+	}
+}
+
+func TestRunRollbackCommandDefaultsToOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	outputDir := filepath.Join(dir, "saida")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := validation.Parse(darmtest.DarmData("0001.pdf"), validation.Options{})
+	if err != nil {
+		t.Fatalf("Parse falhou: %v", err)
+	}
+	opts, err := config.Default().ScriptOptions()
+	if err != nil {
+		t.Fatalf("ScriptOptions falhou: %v", err)
+	}
+	row := sqlgen.RowFromRecord(record)
+	row["SQ_DOC"] = 1000
+	script, err := sqlgen.Generate([]sqlgen.DarmRow{row}, opts)
+	if err != nil {
+		t.Fatalf("Generate falhou: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "INSERT_TODOS_DARMs.sql"), script, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := filepath.Join(dir, "config.json")
+	cfg := `{"paths": {"darms_dir": "darms", "output_dir": ` + strconv.Quote(outputDir) + `}}`
+	if err := os.WriteFile(configPath, []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runRollbackCommand([]string{"-config", configPath}); err != nil {
+		t.Fatalf("runRollbackCommand falhou: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "ROLLBACK_TODOS_DARMs.sql")); err != nil {
+		t.Errorf("rollback deveria ser gerado em paths.output_dir: %v", err)
+	}
+}
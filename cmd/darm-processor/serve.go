@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"gerador-query-darm-go/server"
+)
+
+// runServeCommand implementa o comando "serve": atende a API HTTP de
+// extração e geração de SQL até receber SIGINT/SIGTERM
+func runServeCommand(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Arquivo de configuração")
+	addr := flags.String("addr", "", "Endereço da API, ex.: :8080 (padrão: config)")
+	maxUpload := flags.Int("max-upload-mb", 0, "Tamanho máximo de cada requisição em MB (padrão: config)")
+	timeout := flags.Duration("timeout", 0, "Tempo limite de cada requisição, ex.: 2m (padrão: config)")
+	flags.Parse(args)
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if *addr != "" {
+		cfg.Server.Addr = *addr
+	}
+	if *maxUpload > 0 {
+		cfg.Server.MaxUploadMB = *maxUpload
+	}
+	if *timeout != 0 {
+		seconds, err := timeoutSeconds("timeout", *timeout)
+		if err != nil {
+			return err
+		}
+		cfg.Server.RequestTimeoutSeconds = seconds
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return server.New(cfg, version).ListenAndServe(ctx)
+}
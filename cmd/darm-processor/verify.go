@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/config"
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/verificacao"
+)
+
+// runVerifyCommand implementa o comando "verify": confere se as linhas de
+// FarrDarmsPagos no banco (ou em um dump CSV da tabela) correspondem às dos
+// scripts de inserts, gravando o relatório de verificação. Retorna erro quando
+// há linhas ausentes, a mais ou divergentes.
+func runVerifyCommand(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Arquivo de configuração")
+	insertsDir := flags.String("inserts", "", "Diretório com INSERT_TODOS_DARMs.sql e os INSERT_DARM_PAGO_*.sql (padrão: paths.output_dir)")
+	csvPath := flags.String("csv", "", "Dump CSV de FarrDarmsPagos com cabeçalho (padrão: consulta o banco de config.database)")
+	outputDir := flags.String("output", "", "Diretório do relatório de verificação (padrão: o diretório de inserts)")
+	timeout := flags.Duration("timeout", time.Minute, "Tempo limite da consulta ao banco")
+	flags.Parse(args)
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if *insertsDir == "" {
+		*insertsDir = cfg.OutputDir()
+	}
+	if *csvPath == "" {
+		if err := checkDatabaseDialect(cfg); err != nil {
+			return err
+		}
+	}
+
+	geradas, err := verificacao.ReadInserts(*insertsDir)
+	if err != nil {
+		return err
+	}
+	guias := verificacao.Guias(geradas)
+	logrus.Infof("📄 %d linha(s) de %d guia(s) lidas de %s", len(geradas), len(guias), *insertsDir)
+
+	var banco []verificacao.Linha
+	fonte := *csvPath
+	if *csvPath != "" {
+		if banco, err = verificacao.ReadCSV(*csvPath); err != nil {
+			return err
+		}
+	} else {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := context.WithTimeout(ctx, *timeout)
+		defer cancel()
+
+		fonte = fmt.Sprintf("%s:%d/%s", cfg.Database.Host, cfg.Database.Port, cfg.Database.Database)
+		if banco, err = readDatabase(ctx, cfg.Database, guias, verificacao.Lotes(geradas)); err != nil {
+			return err
+		}
+	}
+	logrus.Infof("🗄️ %d linha(s) de FarrDarmsPagos lidas de %s", len(banco), fonte)
+
+	v := verificacao.Verificar(geradas, banco)
+	v.Inserts, v.Fonte = *insertsDir, fonte
+
+	dir := *outputDir
+	if dir == "" {
+		dir = *insertsDir
+	}
+	if err := verificacao.WriteRelatorio(dir, v); err != nil {
+		return err
+	}
+
+	if !v.Consistente() {
+		return fmt.Errorf("verificação encontrou %d linha(s) ausente(s), %d a mais e %d divergente(s)",
+			v.Contagem(verificacao.StatusAusente), v.Contagem(verificacao.StatusExtra), v.Contagem(verificacao.StatusDivergente))
+	}
+	logrus.Info("✅ Banco confere com os scripts gerados!")
+	return nil
+}
+
+// checkDatabaseDialect rejeita a consulta direta ao banco quando o dialeto
+// configurado não é mysql: o verify só tem o driver do MySQL
+func checkDatabaseDialect(cfg *config.Config) error {
+	insert, err := cfg.InsertOptions()
+	if err != nil {
+		return err
+	}
+	if _, ok := insert.EffectiveDialect().(sqlgen.MySQLDialect); !ok {
+		return fmt.Errorf("a consulta direta ao banco está disponível apenas para o dialeto mysql (sql.dialect = %s); exporte FarrDarmsPagos em CSV e use -csv", insert.EffectiveDialect().Name())
+	}
+	return nil
+}
+
+// readDatabase conecta ao MySQL de config.database e lê as linhas das guias
+// nos lotes de arrecadação dos scripts
+func readDatabase(ctx context.Context, database config.DatabaseConfig, guias []int, lotes []map[string]string) ([]verificacao.Linha, error) {
+	dsn := mysql.NewConfig()
+	dsn.User = database.Username
+	dsn.Passwd = database.Password
+	dsn.Net = "tcp"
+	dsn.Addr = net.JoinHostPort(database.Host, strconv.Itoa(database.Port))
+	dsn.DBName = database.Database
+	if database.Charset != "" {
+		dsn.Params = map[string]string{"charset": database.Charset}
+	}
+
+	db, err := sql.Open("mysql", dsn.FormatDSN())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao banco: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao banco %s: %v", dsn.Addr, err)
+	}
+	return verificacao.ReadDatabase(ctx, db, guias, lotes)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/verificacao"
+)
+
+func TestRunVerifyCommand(t *testing.T) {
+	dir := t.TempDir()
+	insertsDir := filepath.Join(dir, "inserts")
+	if err := os.Mkdir(insertsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Colunas fora do dump não são comparadas
+	row := sqlgen.DarmRow{}
+	for _, column := range sqlgen.Columns() {
+		row[column] = nil
+	}
+	for column, value := range map[string]interface{}{"AA_EXERCICIO": 2025, "CD_BANCO": 70, "NR_BDA": 37, "NR_COMPLEMENTO": 0, "NR_LOTE_NSA": 730,
+		"TP_LOTE_D": 1, "SQ_DOC": 1000, "NR_GUIA": 1, "NR_INSCRICAO": "90001", "VL_PAGO": 123456, "DT_PAGTO": sqlgen.SQLNow{}} {
+		row[column] = value
+	}
+	script, err := sqlgen.Generate([]sqlgen.DarmRow{row}, sqlgen.ScriptOptions{Insert: sqlgen.InsertOptions{Conflict: sqlgen.ConflictInsert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(insertsDir, verificacao.ConsolidatedFile), script, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	header := "NR_GUIA;AA_EXERCICIO;CD_BANCO;NR_BDA;NR_COMPLEMENTO;NR_LOTE_NSA;TP_LOTE_D;SQ_DOC;NR_INSCRICAO;VL_PAGO;DT_PAGTO\n"
+	dumps := map[string]string{
+		"confere.csv":    header + "1;2025;70;37;0;730;1;1000;90001;123456;2025-01-10 08:00:00\n",
+		"divergente.csv": header + "1;2025;70;37;0;730;1;1000;90002;123456;2025-01-10 08:00:00\n",
+	}
+	for name, content := range dumps {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	configPath := filepath.Join(dir, "config.json")
+	if err := runVerifyCommand([]string{"-config", configPath, "-inserts", insertsDir, "-csv", filepath.Join(dir, "confere.csv")}); err != nil {
+		t.Fatalf("runVerifyCommand falhou: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(insertsDir, verificacao.RelatorioBase+".md")); err != nil {
+		t.Errorf("relatório deveria ser gerado no diretório de inserts: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	err = runVerifyCommand([]string{"-config", configPath, "-inserts", insertsDir, "-csv", filepath.Join(dir, "divergente.csv"), "-output", outputDir})
+	if err == nil || !strings.Contains(err.Error(), "1 divergente") {
+		t.Errorf("verificação divergente deveria falhar: %v", err)
+	}
+	report, err := os.ReadFile(filepath.Join(outputDir, verificacao.RelatorioBase+".csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(report), "divergente;1;INSERT_TODOS_DARMs.sql;") || !strings.Contains(string(report), "NR_INSCRICAO;90001;90002") {
+		t.Errorf("relatório CSV inesperado:\n%s", report)
+	}
+
+	// Sem -csv, apenas o MySQL pode ser consultado
+	if err := os.WriteFile(configPath, []byte(`{"sql": {"dialect": "postgres"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = runVerifyCommand([]string{"-config", configPath, "-inserts", insertsDir})
+	if err == nil || !strings.Contains(err.Error(), "apenas para o dialeto mysql (sql.dialect = postgres)") {
+		t.Errorf("dialeto postgres sem -csv deveria falhar: %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+// Package conciliacao confronta os DARMs emitidos (extraídos dos PDFs) com os
+// pagamentos dos arquivos de retorno do banco, classificando cada guia como
+// paga, paga com valor ou código de barras divergente, não paga ou paga sem
+// DARM conhecido.
+package conciliacao
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"gerador-query-darm-go/retorno"
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/validation"
+)
+
+// Status de conciliação de cada item
+const (
+	// StatusPago indica DARM pago pelo valor total emitido
+	StatusPago = "pago"
+	// StatusValorDivergente indica DARM pago por valor diferente do emitido
+	StatusValorDivergente = "valor_divergente"
+	// StatusCodigoDivergente indica DARM localizado apenas pela guia, pago com
+	// código de barras diferente do emitido (pagamento a conferir)
+	StatusCodigoDivergente = "codigo_divergente"
+	// StatusNaoPago indica DARM sem pagamento nos arquivos de retorno
+	StatusNaoPago = "nao_pago"
+	// StatusPagoDesconhecido indica pagamento sem DARM correspondente
+	StatusPagoDesconhecido = "pago_desconhecido"
+)
+
+// Critérios de correspondência entre DARM e pagamento
+const (
+	CriterioCodigoBarras = "codigo_barras"
+	CriterioGuia         = "guia"
+)
+
+// Darm é um DARM emitido, identificado pelo arquivo de origem
+type Darm struct {
+	Arquivo  string
+	Registro *validation.DarmRecord
+}
+
+// Item é o resultado da conciliação de um DARM ou de um pagamento
+type Item struct {
+	Status string `json:"status"`
+	// Arquivo é o PDF do DARM (vazio em pagamentos sem DARM)
+	Arquivo      string `json:"arquivo,omitempty"`
+	Guia         int    `json:"guia,omitempty"`
+	CodigoBarras string `json:"codigoBarras,omitempty"`
+	// ValorDarm é o valor total emitido; ValorPago, o informado pelo banco
+	ValorDarm validation.Money `json:"valorDarm"`
+	ValorPago validation.Money `json:"valorPago"`
+	Criterio  string           `json:"criterio,omitempty"`
+	// Retorno é o arquivo de retorno do pagamento
+	Retorno    string             `json:"retorno,omitempty"`
+	NSA        int                `json:"nsa,omitempty"`
+	Pagamento  *retorno.Pagamento `json:"pagamento,omitempty"`
+	Observacao string             `json:"observacao,omitempty"`
+
+	registro *validation.DarmRecord
+	lote     sqlgen.LoteArrecadacao
+}
+
+// Diferenca retorna o valor pago menos o valor emitido
+func (i Item) Diferenca() validation.Money {
+	return i.ValorPago.Sub(i.ValorDarm)
+}
+
+// Conciliacao é o resultado do confronto entre DARMs e pagamentos
+type Conciliacao struct {
+	GeradoEm time.Time `json:"geradoEm"`
+	// Retornos são os arquivos de retorno confrontados
+	Retornos []string `json:"retornos"`
+	Itens    []Item   `json:"itens"`
+	// NaoEfetivados são os pagamentos rejeitados pelo banco (ocorrência diferente de 00), ignorados
+	NaoEfetivados int `json:"naoEfetivados"`
+}
+
+// Contagem retorna a quantidade de itens com o status informado
+func (c *Conciliacao) Contagem(status string) int {
+	count := 0
+	for _, item := range c.Itens {
+		if item.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Total retorna a soma dos valores pagos dos itens com o status informado
+func (c *Conciliacao) Total(status string) validation.Money {
+	var total validation.Money
+	for _, item := range c.Itens {
+		if item.Status == status {
+			total = total.Add(item.ValorPago)
+		}
+	}
+	return total
+}
+
+// Conciliar associa cada pagamento efetivado a um DARM, pelo código de barras
+// (44 dígitos, também a partir da linha digitável) ou, sem ele, pelo número da
+// guia extraído do código de barras do pagamento. Cada DARM é conciliado com um
+// único pagamento; pagamentos repetidos da mesma guia ficam sem DARM.
+func Conciliar(darms []Darm, arquivos []*retorno.Arquivo) *Conciliacao {
+	c := &Conciliacao{GeradoEm: time.Now(), Retornos: []string{}, Itens: []Item{}}
+
+	itens := make([]Item, len(darms))
+	porCodigo := map[string][]int{}
+	porGuia := map[int][]int{}
+	for i, darm := range darms {
+		codigo := retorno.NormalizarCodigoBarras(darm.Registro.CodigoBarras)
+		itens[i] = Item{
+			Status:       StatusNaoPago,
+			Arquivo:      darm.Arquivo,
+			Guia:         darm.Registro.Guia,
+			CodigoBarras: codigo,
+			ValorDarm:    darm.Registro.ValorTotal,
+			registro:     darm.Registro,
+		}
+		if len(codigo) == 44 {
+			porCodigo[codigo] = append(porCodigo[codigo], i)
+		}
+		if darm.Registro.Guia > 0 {
+			porGuia[darm.Registro.Guia] = append(porGuia[darm.Registro.Guia], i)
+		}
+	}
+
+	conciliado := make([]bool, len(darms))
+	// disponivel retorna o primeiro DARM ainda não conciliado entre os candidatos
+	disponivel := func(candidatos []int) (int, bool) {
+		for _, i := range candidatos {
+			if !conciliado[i] {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	desconhecidos := []Item{}
+	for _, arquivo := range arquivos {
+		c.Retornos = append(c.Retornos, arquivo.Nome)
+		for _, pagamento := range arquivo.Pagamentos {
+			if !pagamento.Efetivado() {
+				c.NaoEfetivados++
+				continue
+			}
+			pagamento := pagamento
+
+			criterio := CriterioCodigoBarras
+			i, ok := disponivel(porCodigo[pagamento.CodigoBarras])
+			if !ok && pagamento.Guia > 0 {
+				criterio = CriterioGuia
+				i, ok = disponivel(porGuia[pagamento.Guia])
+			}
+
+			if !ok {
+				item := Item{
+					Status:       StatusPagoDesconhecido,
+					Guia:         pagamento.Guia,
+					CodigoBarras: pagamento.CodigoBarras,
+					ValorPago:    pagamento.ValorPago,
+					Retorno:      arquivo.Nome,
+					NSA:          arquivo.NSA,
+					Pagamento:    &pagamento,
+					Observacao:   "nenhum DARM emitido com este código de barras ou guia",
+				}
+				if len(porCodigo[pagamento.CodigoBarras]) > 0 || (pagamento.Guia > 0 && len(porGuia[pagamento.Guia]) > 0) {
+					item.Observacao = "pagamento em duplicidade: DARM já conciliado com outro pagamento"
+				}
+				desconhecidos = append(desconhecidos, item)
+				continue
+			}
+
+			conciliado[i] = true
+			item := &itens[i]
+			item.Status = StatusPago
+			item.ValorPago = pagamento.ValorPago
+			item.Criterio = criterio
+			item.Retorno = arquivo.Nome
+			item.NSA = arquivo.NSA
+			item.Pagamento = &pagamento
+			item.lote = arquivo.Lote()
+			if item.ValorPago != item.ValorDarm {
+				item.Status = StatusValorDivergente
+				item.Observacao = fmt.Sprintf("pago R$ %s, emitido R$ %s (diferença R$ %s)", item.ValorPago, item.ValorDarm, item.Diferenca())
+			}
+			if criterio == CriterioGuia && item.CodigoBarras != "" && item.CodigoBarras != pagamento.CodigoBarras {
+				item.Status = StatusCodigoDivergente
+				item.Observacao = joinObservacao(item.Observacao, "código de barras do pagamento difere do DARM")
+			}
+		}
+	}
+
+	c.Itens = append(itens, desconhecidos...)
+	return c
+}
+
+// joinObservacao junta observações separadas por ponto e vírgula
+func joinObservacao(atual, nova string) string {
+	if atual == "" {
+		return nova
+	}
+	return atual + "; " + nova
+}
+
+// Rows monta as linhas de FarrDarmsPagos dos DARMs pagos pelo valor emitido,
+// com o lote do arquivo de retorno, o valor e a data de pagamento informados
+// pelo banco e o SQ_DOC fixado no instante informado
+func (c *Conciliacao) Rows(now time.Time) []sqlgen.DarmRow {
+	rows := []sqlgen.DarmRow{}
+	for _, item := range c.Itens {
+		if item.Status != StatusPago {
+			continue
+		}
+		row := sqlgen.RowFromRecord(item.registro)
+		row.SetLote(item.lote)
+		row.SetPagamento(item.Pagamento.ValorPago, item.Pagamento.DataPagamento)
+		rows = append(rows, row)
+	}
+	sqlgen.AssignSQDocs(rows, now)
+	return rows
+}
+
+// guiaLabel formata a guia para o relatório (vazia quando desconhecida)
+func guiaLabel(guia int) string {
+	if guia == 0 {
+		return ""
+	}
+	return strconv.Itoa(guia)
+}
@@ -0,0 +1,180 @@
+package conciliacao
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gerador-query-darm-go/internal/darmtest"
+	"gerador-query-darm-go/retorno"
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/validation"
+)
+
+// darmTeste gera um DARM de R$ 1.234,56 com o código de barras informado
+func darmTeste(t *testing.T, arquivo, codigoBarras string) Darm {
+	t.Helper()
+	data := darmtest.DarmData(arquivo)
+	data.CodigoBarras = codigoBarras
+	record, err := validation.Parse(data, validation.Options{})
+	if err != nil {
+		t.Fatalf("Parse falhou: %v", err)
+	}
+	return Darm{Arquivo: arquivo, Registro: record}
+}
+
+func retornoTeste(t *testing.T, pagamentos []darmtest.PagamentoRetorno) *retorno.Arquivo {
+	t.Helper()
+	arquivo, err := retorno.Parse(strings.NewReader(darmtest.RetornoFebraban150(104, 731, pagamentos)), retorno.Options{NumeroBDA: 12, GuiaInicio: 20, GuiaTamanho: 10})
+	if err != nil {
+		t.Fatalf("Parse do retorno falhou: %v", err)
+	}
+	arquivo.Nome = "RET0731.ret"
+	return arquivo
+}
+
+func TestConciliar(t *testing.T) {
+	data := time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC)
+	darms := []Darm{
+		// Pago pelo código de barras
+		darmTeste(t, "0000001.pdf", darmtest.CodigoBarras(1, 123456)),
+		// Pago com valor divergente
+		darmTeste(t, "0000002.pdf", darmtest.CodigoBarras(2, 123456)),
+		// Sem código de barras extraído: conciliado pela guia
+		darmTeste(t, "0000003.pdf", ""),
+		// Não pago
+		darmTeste(t, "0000004.pdf", darmtest.CodigoBarras(4, 123456)),
+		// Mesma guia e valor, mas outro código de barras: não entra no SQL
+		darmTeste(t, "0000005.pdf", darmtest.CodigoBarras(5, 123456)),
+	}
+	pagamentos := []darmtest.PagamentoRetorno{
+		{CodigoBarras: darmtest.CodigoBarras(1, 123456), Centavos: 123456, Data: data, Autenticacao: "AUT1"},
+		{CodigoBarras: darmtest.CodigoBarras(2, 100000), Centavos: 100000, Data: data, Autenticacao: "AUT2"},
+		{CodigoBarras: darmtest.CodigoBarras(3, 123456), Centavos: 123456, Data: data, Autenticacao: "AUT3"},
+		// Pagamento em duplicidade do DARM 1
+		{CodigoBarras: darmtest.CodigoBarras(1, 123456), Centavos: 123456, Data: data, Autenticacao: "AUT4"},
+		// Pagamento sem DARM emitido
+		{CodigoBarras: darmtest.CodigoBarras(9, 5000), Centavos: 5000, Data: data, Autenticacao: "AUT5"},
+		{CodigoBarras: darmtest.CodigoBarras(5, 123456)[:43] + "1", Centavos: 123456, Data: data, Autenticacao: "AUT6"},
+	}
+
+	c := Conciliar(darms, []*retorno.Arquivo{retornoTeste(t, pagamentos)})
+
+	expected := []struct {
+		status, criterio, autenticacao string
+		guia                           int
+	}{
+		{StatusPago, CriterioCodigoBarras, "AUT1", 1},
+		{StatusCodigoDivergente, CriterioGuia, "AUT2", 2},
+		{StatusPago, CriterioGuia, "AUT3", 3},
+		{StatusNaoPago, "", "", 4},
+		{StatusCodigoDivergente, CriterioGuia, "AUT6", 5},
+		{StatusPagoDesconhecido, "", "AUT4", 1},
+		{StatusPagoDesconhecido, "", "AUT5", 9},
+	}
+	if len(c.Itens) != len(expected) {
+		t.Fatalf("esperados %d itens, obtidos %d: %+v", len(expected), len(c.Itens), c.Itens)
+	}
+	for i, e := range expected {
+		item := c.Itens[i]
+		if item.Status != e.status || item.Criterio != e.criterio || item.autenticacao() != e.autenticacao || item.Guia != e.guia {
+			t.Errorf("item %d inesperado: %+v", i, item)
+		}
+	}
+
+	if diferenca := c.Itens[1].Diferenca(); diferenca != validation.Money(-23456) {
+		t.Errorf("diferença inesperada: %s", diferenca)
+	}
+	if observacao := c.Itens[1].Observacao; !strings.Contains(observacao, "diferença R$ -234,56") || !strings.Contains(observacao, "código de barras") {
+		t.Errorf("observação de valor e código de barras esperada: %q", observacao)
+	}
+	if !strings.Contains(c.Itens[5].Observacao, "duplicidade") {
+		t.Errorf("observação de duplicidade esperada: %q", c.Itens[5].Observacao)
+	}
+	if c.Contagem(StatusPago) != 2 || c.Total(StatusPago) != validation.Money(246912) {
+		t.Errorf("resumo inesperado: %d, R$ %s", c.Contagem(StatusPago), c.Total(StatusPago))
+	}
+
+	// SQL apenas dos DARMs pagos pelo valor emitido, com o lote e a data do banco
+	rows := c.Rows(time.Now())
+	if len(rows) != 2 || rows[0]["NR_GUIA"] != 1 || rows[1]["NR_GUIA"] != 3 {
+		t.Fatalf("linhas inesperadas: %+v", rows)
+	}
+	if rows[0]["DT_PAGTO"] != data || rows[0]["VL_PAGO"] != validation.Money(123456) || rows[0]["NR_LOTE_NSA"] != 731 {
+		t.Errorf("linha inesperada: %+v", rows[0])
+	}
+	if rows[0]["SQ_DOC"] == rows[1]["SQ_DOC"] {
+		t.Error("SQ_DOC deveria ser único por linha")
+	}
+}
+
+func TestConciliarIgnoraNaoEfetivados(t *testing.T) {
+	arquivo := retornoTeste(t, []darmtest.PagamentoRetorno{
+		{CodigoBarras: darmtest.CodigoBarras(1, 123456), Centavos: 123456, Data: time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC)},
+	})
+	arquivo.Pagamentos[0].Ocorrencias = "BD"
+
+	c := Conciliar([]Darm{darmTeste(t, "0000001.pdf", darmtest.CodigoBarras(1, 123456))}, []*retorno.Arquivo{arquivo})
+	if c.NaoEfetivados != 1 || len(c.Itens) != 1 || c.Itens[0].Status != StatusNaoPago {
+		t.Errorf("pagamento rejeitado pelo banco não deveria conciliar: %+v", c)
+	}
+}
+
+func TestWriteRelatorioESQL(t *testing.T) {
+	data := time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC)
+	darms := []Darm{
+		darmTeste(t, "0000001.pdf", darmtest.CodigoBarras(1, 123456)),
+		darmTeste(t, "0000002.pdf", darmtest.CodigoBarras(2, 123456)),
+	}
+	arquivo := retornoTeste(t, []darmtest.PagamentoRetorno{
+		{CodigoBarras: darmtest.CodigoBarras(1, 123456), Centavos: 123456, Data: data, Autenticacao: "AUT1"},
+	})
+	c := Conciliar(darms, []*retorno.Arquivo{arquivo})
+
+	dir := t.TempDir()
+	if err := WriteRelatorio(dir, c); err != nil {
+		t.Fatalf("WriteRelatorio falhou: %v", err)
+	}
+	md, err := os.ReadFile(filepath.Join(dir, RelatorioBase+".md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"✅ Pago: 1 (R$ 1.234,56 pagos)", "⏳ Não pago: 1", "| 0000001.pdf | 1 | 1.234,56 | 1.234,56 | 09/01/2025 | AUT1 | RET0731.ret |"} {
+		if !strings.Contains(string(md), want) {
+			t.Errorf("relatório sem %q:\n%s", want, md)
+		}
+	}
+	csvContent, err := os.ReadFile(filepath.Join(dir, RelatorioBase+".csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(csvContent), "nao_pago;0000002.pdf;2;") {
+		t.Errorf("CSV inesperado:\n%s", csvContent)
+	}
+
+	opts := sqlgen.ScriptOptions{Insert: sqlgen.InsertOptions{Conflict: sqlgen.ConflictInsert}}
+	if err := WriteSQL(dir, c, opts); err != nil {
+		t.Fatalf("WriteSQL falhou: %v", err)
+	}
+	script, err := os.ReadFile(filepath.Join(dir, InsertFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(script), "'2025-01-09 00:00:00'") || strings.Count(string(script), "INSERT") != 1 {
+		t.Errorf("INSERT inesperado:\n%s", script)
+	}
+	if _, err := os.Stat(filepath.Join(dir, RollbackFile)); err != nil {
+		t.Errorf("rollback não gerado: %v", err)
+	}
+
+	// Sem pagamentos confirmados, nenhum SQL é gerado
+	vazio := t.TempDir()
+	if err := WriteSQL(vazio, Conciliar(darms, nil), opts); err != nil {
+		t.Fatalf("WriteSQL falhou: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(vazio, InsertFile)); !os.IsNotExist(err) {
+		t.Errorf("INSERT não deveria ser gerado: %v", err)
+	}
+}
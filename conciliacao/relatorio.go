@@ -0,0 +1,161 @@
+package conciliacao
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/output"
+	"gerador-query-darm-go/sqlgen"
+)
+
+// Arquivos gerados pela conciliação
+const (
+	RelatorioBase = "RELATORIO_CONCILIACAO"
+	InsertFile    = "INSERT_CONCILIADOS.sql"
+	RollbackFile  = "ROLLBACK_CONCILIADOS.sql"
+)
+
+// statusOrder é a ordem dos status no resumo do relatório
+var statusOrder = []string{StatusPago, StatusValorDivergente, StatusCodigoDivergente, StatusNaoPago, StatusPagoDesconhecido}
+
+// statusLabel descreve o status para o relatório
+func statusLabel(status string) string {
+	switch status {
+	case StatusPago:
+		return "✅ Pago"
+	case StatusValorDivergente:
+		return "⚠️ Pago com valor divergente"
+	case StatusCodigoDivergente:
+		return "⚠️ Pago com código de barras divergente"
+	case StatusNaoPago:
+		return "⏳ Não pago"
+	case StatusPagoDesconhecido:
+		return "❓ Pago sem DARM conhecido"
+	}
+	return status
+}
+
+// dataPagamento formata a data de pagamento do item (vazia sem pagamento)
+func (i Item) dataPagamento() string {
+	if i.Pagamento == nil {
+		return ""
+	}
+	return i.Pagamento.DataPagamento.Format("02/01/2006")
+}
+
+// autenticacao retorna a autenticação bancária do pagamento do item
+func (i Item) autenticacao() string {
+	if i.Pagamento == nil {
+		return ""
+	}
+	return i.Pagamento.Autenticacao
+}
+
+// RenderMarkdown gera RELATORIO_CONCILIACAO.md
+func RenderMarkdown(c *Conciliacao) string {
+	var b strings.Builder
+	escape := strings.NewReplacer("|", "\\|", "\n", " ").Replace
+
+	fmt.Fprintf(&b, "# RELATÓRIO DE CONCILIAÇÃO DE DARMs\n\n## Data/Hora: %s\n\n", c.GeradoEm.Format("02/01/2006 15:04:05"))
+	b.WriteString("### Resumo:\n")
+	fmt.Fprintf(&b, "- Arquivos de retorno: %s\n", strings.Join(c.Retornos, ", "))
+	for _, status := range statusOrder {
+		fmt.Fprintf(&b, "- %s: %d (R$ %s pagos)\n", statusLabel(status), c.Contagem(status), c.Total(status))
+	}
+	if c.NaoEfetivados > 0 {
+		fmt.Fprintf(&b, "- Pagamentos não efetivados pelo banco (ignorados): %d\n", c.NaoEfetivados)
+	}
+	fmt.Fprintf(&b, "- SQL gerado: %s (apenas DARMs pagos pelo valor emitido)\n", InsertFile)
+
+	for _, status := range statusOrder {
+		if c.Contagem(status) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s:\n| Arquivo | Guia | Valor emitido | Valor pago | Pagamento | Autenticação | Retorno | Observação |\n|---|---|---|---|---|---|---|---|\n", statusLabel(status))
+		for _, item := range c.Itens {
+			if item.Status != status {
+				continue
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+				escape(item.Arquivo), guiaLabel(item.Guia), item.ValorDarm, item.ValorPago, item.dataPagamento(),
+				escape(item.autenticacao()), escape(item.Retorno), escape(item.Observacao))
+		}
+	}
+	return b.String()
+}
+
+// csvHeader são as colunas de RELATORIO_CONCILIACAO.csv
+var csvHeader = []string{
+	"status", "arquivo", "guia", "codigo_barras", "valor_darm", "valor_pago", "diferenca",
+	"data_pagamento", "autenticacao", "criterio", "retorno", "nsa", "observacao",
+}
+
+// RenderCSV gera RELATORIO_CONCILIACAO.csv (separado por ponto e vírgula)
+func RenderCSV(c *Conciliacao) ([]byte, error) {
+	var b strings.Builder
+	writer := csv.NewWriter(&b)
+	writer.Comma = ';'
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, item := range c.Itens {
+		nsa := ""
+		if item.NSA > 0 {
+			nsa = strconv.Itoa(item.NSA)
+		}
+		line := []string{
+			item.Status, item.Arquivo, guiaLabel(item.Guia), item.CodigoBarras,
+			item.ValorDarm.Decimal(), item.ValorPago.Decimal(), item.Diferenca().Decimal(),
+			item.dataPagamento(), item.autenticacao(), item.Criterio, item.Retorno, nsa, item.Observacao,
+		}
+		if err := writer.Write(line); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return []byte(b.String()), writer.Error()
+}
+
+// WriteRelatorio grava o relatório de conciliação em Markdown, JSON e CSV
+func WriteRelatorio(dir string, c *Conciliacao) error {
+	err := output.WriteFormatos(dir, RelatorioBase, "relatório de conciliação",
+		output.MarkdownFormato(RenderMarkdown(c)), output.JSONFormato(c), output.CSVFormato(func() ([]byte, error) { return RenderCSV(c) }))
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("📋 Relatório gerado: %s.md/.json/.csv (%d pago(s), %d divergente(s), %d não pago(s), %d sem DARM)",
+		RelatorioBase, c.Contagem(StatusPago), c.Contagem(StatusValorDivergente)+c.Contagem(StatusCodigoDivergente), c.Contagem(StatusNaoPago), c.Contagem(StatusPagoDesconhecido))
+	return nil
+}
+
+// WriteSQL grava INSERT_CONCILIADOS.sql e o rollback correspondente apenas com
+// os DARMs pagos pelo valor emitido; sem pagamentos confirmados, nada é gerado
+func WriteSQL(dir string, c *Conciliacao, opts sqlgen.ScriptOptions) error {
+	rows := c.Rows(time.Now())
+	if len(rows) == 0 {
+		logrus.Info("📭 Nenhum pagamento confirmado: INSERT_CONCILIADOS.sql não gerado.")
+		return nil
+	}
+
+	script, err := sqlgen.Generate(rows, opts)
+	if err != nil {
+		return fmt.Errorf("erro ao montar INSERT dos pagamentos conciliados: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, InsertFile), script, 0644); err != nil {
+		return fmt.Errorf("erro ao gerar %s: %v", InsertFile, err)
+	}
+	if err := sqlgen.WriteRollbackScript(filepath.Join(dir, RollbackFile), sqlgen.RollbackKeysFromRows(rows), opts); err != nil {
+		return err
+	}
+
+	logrus.Infof("📄 %s gerado com %d pagamento(s) confirmado(s)", InsertFile, len(rows))
+	return nil
+}
@@ -0,0 +1,395 @@
+// Package config lê o config.json e converte as opções nas estruturas dos
+// pacotes sqlgen, output e validation.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/output"
+	"gerador-query-darm-go/retorno"
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/validation"
+)
+
+// Config representa o arquivo config.json
+type Config struct {
+	Database   DatabaseConfig   `json:"database"`
+	Paths      PathsConfig      `json:"paths"`
+	SQL        SQLConfig        `json:"sql"`
+	Processing ProcessingConfig `json:"processing"`
+	Output     OutputConfig     `json:"output"`
+	Validation ValidationConfig `json:"validation"`
+	Server     ServerConfig     `json:"server"`
+	Retorno    RetornoConfig    `json:"retorno"`
+	Logging    LoggingConfig    `json:"logging"`
+}
+
+// DatabaseConfig contém os dados de conexão com o banco
+type DatabaseConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Database string `json:"database"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Charset  string `json:"charset"`
+}
+
+// PathsConfig contém os diretórios utilizados pelo processador
+type PathsConfig struct {
+	// BaseDir é o diretório a partir do qual darms_dir e output_dir relativos
+	// são resolvidos (relativo ao diretório de trabalho)
+	BaseDir string `json:"base_dir"`
+	// DarmsDir é o diretório dos PDFs dos DARMs
+	DarmsDir string `json:"darms_dir"`
+	// OutputDir é o diretório dos scripts SQL, exportações e relatórios
+	OutputDir string `json:"output_dir"`
+	// OverridesFile é o arquivo de correções manuais (.csv ou .json), aplicado a cada execução
+	OverridesFile string `json:"overrides_file"`
+}
+
+// SQLConfig contém as opções de geração de SQL
+type SQLConfig struct {
+	Encoding       string `json:"encoding"`
+	BatchSize      int    `json:"batch_size"`
+	UseTransaction bool   `json:"use_transaction"`
+	UseIgnore      bool   `json:"use_ignore"`
+	// ConflictStrategy sobrepõe use_ignore: insert, ignore, update ou not_exists
+	ConflictStrategy string `json:"conflict_strategy"`
+	// UpdateColumns são as colunas atualizadas pela estratégia update
+	UpdateColumns []string `json:"update_columns"`
+	// Dialect é o banco de destino: mysql (padrão), postgres, sqlserver ou oracle
+	Dialect string `json:"dialect"`
+	// BulkLoad gera também LOAD_TODOS_DARMs.tsv + LOAD_TODOS_DARMs.sql (LOAD DATA LOCAL INFILE)
+	BulkLoad bool `json:"bulk_load"`
+	// Comments inclui comentários no script consolidado (desligado = formato Control-M)
+	Comments bool `json:"comments"`
+	// PerGuiaChecks gera também um CHECK_GUIA_<n>.sql por guia, além do CHECK_GUIAS.sql
+	PerGuiaChecks bool `json:"per_guia_checks"`
+}
+
+// ProcessingConfig contém as opções do pool de processamento
+type ProcessingConfig struct {
+	// Workers é o número de PDFs processados em paralelo (0 = GOMAXPROCS)
+	Workers int `json:"workers"`
+	// TimeoutSeconds é o tempo limite de processamento de cada PDF (0 = sem limite)
+	TimeoutSeconds int `json:"timeout_seconds"`
+}
+
+// OutputConfig contém as exportações dos dados extraídos
+type OutputConfig struct {
+	// Formats lista os formatos exportados: json, jsonl e/ou csv
+	Formats []string `json:"formats"`
+	// CSVDecimalComma usa vírgula como separador decimal no CSV
+	CSVDecimalComma bool `json:"csv_decimal_comma"`
+}
+
+// ValidationConfig controla o tratamento de dados ausentes ou inválidos no PDF
+type ValidationConfig struct {
+	// Mode é lenient (aplica valores padrão com aviso) ou strict (rejeita o PDF)
+	Mode string `json:"mode"`
+}
+
+// ServerConfig contém as opções da API HTTP (comando serve)
+type ServerConfig struct {
+	// Addr é o endereço em que a API escuta, ex.: 127.0.0.1:8080 (apenas a
+	// máquina local) ou :8080 (todas as interfaces)
+	Addr string `json:"addr"`
+	// Token é exigido em "Authorization: Bearer" nas rotas que alteram a
+	// revisão; vazio aceita essas rotas apenas a partir da máquina local
+	Token string `json:"token"`
+	// MaxUploadMB é o tamanho máximo de cada requisição de upload (0 = sem limite)
+	MaxUploadMB int `json:"max_upload_mb"`
+	// MaxFiles é o número máximo de PDFs por requisição (0 = sem limite)
+	MaxFiles int `json:"max_files"`
+	// RequestTimeoutSeconds é o tempo limite de processamento de cada requisição (0 = sem limite)
+	RequestTimeoutSeconds int `json:"request_timeout_seconds"`
+}
+
+// RetornoConfig contém as opções de leitura dos arquivos de retorno do banco
+type RetornoConfig struct {
+	// NumeroBDA é o boletim diário de arrecadação (NR_BDA), ausente do layout (0 = lote padrão)
+	NumeroBDA int `json:"numero_bda"`
+	// GuiaInicio e GuiaTamanho localizam o número da guia no código de barras
+	// (posição a partir de 1 nos 44 dígitos; tamanho 0 = não extrai a guia)
+	GuiaInicio  int `json:"guia_inicio"`
+	GuiaTamanho int `json:"guia_tamanho"`
+}
+
+// LoggingConfig contém as opções de logging
+type LoggingConfig struct {
+	// Level é o nível mínimo: debug, info, warning, error ou fatal
+	Level string `json:"level"`
+	// Format é text ou json
+	Format string `json:"format"`
+	// OutputFile recebe uma cópia do log, além do terminal (vazio = apenas o terminal)
+	OutputFile string `json:"output_file"`
+}
+
+// Default retorna a configuração padrão
+func Default() *Config {
+	return &Config{
+		Database: DatabaseConfig{
+			Host:     "localhost",
+			Port:     3306,
+			Database: "silfae",
+			Username: "root",
+			Charset:  "latin1",
+		},
+		Paths: PathsConfig{
+			BaseDir:   ".",
+			DarmsDir:  "darms",
+			OutputDir: "inserts",
+		},
+		SQL: SQLConfig{
+			Encoding:       "latin1",
+			BatchSize:      100,
+			UseTransaction: true,
+			UseIgnore:      true,
+			Dialect:        "mysql",
+		},
+		Processing: ProcessingConfig{
+			Workers:        0,
+			TimeoutSeconds: 30,
+		},
+		Validation: ValidationConfig{
+			Mode: validation.ModeLenient,
+		},
+		Server: ServerConfig{
+			Addr:                  "127.0.0.1:8080",
+			MaxUploadMB:           32,
+			MaxFiles:              50,
+			RequestTimeoutSeconds: 120,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "text",
+		},
+	}
+}
+
+// Load carrega a configuração de um arquivo JSON.
+// Se o arquivo não existir, retorna a configuração padrão.
+func Load(path string) (*Config, error) {
+	config := Default()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de configuração: %v", err)
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar arquivo de configuração: %v", err)
+	}
+
+	return config, nil
+}
+
+// Validate verifica as opções que não podem ser validadas na leitura do JSON
+func (c *Config) Validate() error {
+	if c.SQL.BatchSize < 0 {
+		return fmt.Errorf("sql.batch_size não pode ser negativo: %d", c.SQL.BatchSize)
+	}
+
+	if _, err := validation.ParseMode(c.Validation.Mode); err != nil {
+		return err
+	}
+
+	if c.Processing.Workers < 0 || c.Processing.TimeoutSeconds < 0 {
+		return fmt.Errorf("processing.workers e processing.timeout_seconds não podem ser negativos")
+	}
+
+	if c.Server.MaxUploadMB < 0 || c.Server.MaxFiles < 0 || c.Server.RequestTimeoutSeconds < 0 {
+		return fmt.Errorf("server.max_upload_mb, server.max_files e server.request_timeout_seconds não podem ser negativos")
+	}
+
+	if err := c.RetornoOptions().Validate(); err != nil {
+		return fmt.Errorf("retorno: %v", err)
+	}
+
+	for _, format := range c.Output.Formats {
+		if _, err := output.NewOutputWriter(format, output.ExportOptions{}); err != nil {
+			return err
+		}
+	}
+
+	if c.SQL.BulkLoad {
+		if _, err := c.BulkLoadOptions(); err != nil {
+			return err
+		}
+	}
+
+	opts, err := c.InsertOptions()
+	if err != nil {
+		return err
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	if c.Paths.DarmsDir == "" || c.Paths.OutputDir == "" {
+		return fmt.Errorf("paths.darms_dir e paths.output_dir não podem ser vazios")
+	}
+
+	if _, err := logrus.ParseLevel(c.Logging.Level); err != nil {
+		return fmt.Errorf("logging.level inválido: %q", c.Logging.Level)
+	}
+	if c.Logging.Format != "text" && c.Logging.Format != "json" {
+		return fmt.Errorf("logging.format inválido: %q (use text ou json)", c.Logging.Format)
+	}
+	return nil
+}
+
+// ValidationMode retorna o modo de validação normalizado (lenient ou strict)
+func (c *Config) ValidationMode() string {
+	mode, err := validation.ParseMode(c.Validation.Mode)
+	if err != nil {
+		return validation.ModeLenient
+	}
+	return mode
+}
+
+// StrictValidation indica se valores padrão rejeitam o PDF em vez de gerar aviso
+func (c *Config) StrictValidation() bool {
+	return c.ValidationMode() == validation.ModeStrict
+}
+
+// ValidationOptions retorna as opções de conversão dos dados extraídos
+func (c *Config) ValidationOptions() validation.Options {
+	return validation.Options{Strict: c.StrictValidation()}
+}
+
+// RetornoOptions retorna as opções de leitura dos arquivos de retorno do banco
+func (c *Config) RetornoOptions() retorno.Options {
+	return retorno.Options{
+		NumeroBDA:   c.Retorno.NumeroBDA,
+		GuiaInicio:  c.Retorno.GuiaInicio,
+		GuiaTamanho: c.Retorno.GuiaTamanho,
+	}
+}
+
+// InsertOptions retorna as opções de geração dos INSERTs.
+// Sem conflict_strategy, use_ignore escolhe entre ignore e insert.
+func (c *Config) InsertOptions() (sqlgen.InsertOptions, error) {
+	strategy := sqlgen.ConflictInsert
+	if c.SQL.UseIgnore {
+		strategy = sqlgen.ConflictIgnore
+	}
+	if c.SQL.ConflictStrategy != "" {
+		parsed, err := sqlgen.ParseConflictStrategy(c.SQL.ConflictStrategy)
+		if err != nil {
+			return sqlgen.InsertOptions{}, err
+		}
+		strategy = parsed
+	}
+
+	dialect, err := sqlgen.ParseDialect(c.SQL.Dialect)
+	if err != nil {
+		return sqlgen.InsertOptions{}, err
+	}
+
+	return sqlgen.InsertOptions{
+		Conflict:      strategy,
+		UpdateColumns: c.SQL.UpdateColumns,
+		Dialect:       dialect,
+	}, nil
+}
+
+// ScriptOptions retorna as opções do script consolidado
+func (c *Config) ScriptOptions() (sqlgen.ScriptOptions, error) {
+	insert, err := c.InsertOptions()
+	if err != nil {
+		return sqlgen.ScriptOptions{}, err
+	}
+
+	return sqlgen.ScriptOptions{
+		Insert:         insert,
+		BatchSize:      c.SQL.BatchSize,
+		UseTransaction: c.SQL.UseTransaction,
+		Comments:       c.SQL.Comments,
+	}, nil
+}
+
+// BulkLoadOptions retorna as opções da carga em massa (LOAD DATA)
+func (c *Config) BulkLoadOptions() (sqlgen.BulkLoadOptions, error) {
+	insert, err := c.InsertOptions()
+	if err != nil {
+		return sqlgen.BulkLoadOptions{}, err
+	}
+	if _, ok := insert.EffectiveDialect().(sqlgen.MySQLDialect); !ok {
+		return sqlgen.BulkLoadOptions{}, fmt.Errorf("sql.bulk_load disponível apenas para o dialeto mysql")
+	}
+	if insert.Conflict == sqlgen.ConflictUpdate || insert.Conflict == sqlgen.ConflictNotExists {
+		return sqlgen.BulkLoadOptions{}, fmt.Errorf("sql.bulk_load não suporta a estratégia %s", insert.Conflict)
+	}
+
+	charset, err := sqlgen.MySQLCharset(c.SQL.Encoding)
+	if err != nil {
+		return sqlgen.BulkLoadOptions{}, err
+	}
+
+	return sqlgen.BulkLoadOptions{
+		Insert:   insert,
+		Charset:  charset,
+		DataFile: sqlgen.BulkDataFile,
+	}, nil
+}
+
+// BaseDir retorna o diretório base em caminho absoluto
+func (c *Config) BaseDir() string {
+	baseDir, err := filepath.Abs(c.Paths.BaseDir)
+	if err != nil {
+		return c.Paths.BaseDir
+	}
+	return baseDir
+}
+
+// DarmsDir retorna o diretório dos PDFs, resolvido a partir do diretório base
+func (c *Config) DarmsDir() string {
+	return c.resolvePath(c.Paths.DarmsDir)
+}
+
+// OutputDir retorna o diretório de saída, resolvido a partir do diretório base
+func (c *Config) OutputDir() string {
+	return c.resolvePath(c.Paths.OutputDir)
+}
+
+// resolvePath resolve um caminho relativo ao diretório base
+func (c *Config) resolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.BaseDir(), path)
+}
+
+// WorkerCount retorna o número efetivo de workers
+func (c *Config) WorkerCount() int {
+	if c.Processing.Workers > 0 {
+		return c.Processing.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// FileTimeout retorna o tempo limite de processamento por PDF
+func (c *Config) FileTimeout() time.Duration {
+	return time.Duration(c.Processing.TimeoutSeconds) * time.Second
+}
+
+// MaxUploadBytes retorna o tamanho máximo de uma requisição de upload da API (0 = sem limite)
+func (c *Config) MaxUploadBytes() int64 {
+	return int64(c.Server.MaxUploadMB) << 20
+}
+
+// RequestTimeout retorna o tempo limite de uma requisição da API (0 = sem limite)
+func (c *Config) RequestTimeout() time.Duration {
+	return time.Duration(c.Server.RequestTimeoutSeconds) * time.Second
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/validation"
+)
+
+func TestValidationModeConfig(t *testing.T) {
+	config := Default()
+	config.Validation.Mode = "paranoid"
+	if err := config.Validate(); err == nil {
+		t.Error("modo de validação desconhecido deveria ser rejeitado")
+	}
+
+	config.Validation.Mode = ""
+	if config.StrictValidation() || config.ValidationMode() != validation.ModeLenient {
+		t.Error("modo vazio deveria ser lenient")
+	}
+	config.Validation.Mode = "STRICT"
+	if !config.StrictValidation() {
+		t.Error("modo strict deveria ser reconhecido")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if config, err := Load(path); err != nil || config.SQL.Dialect != "mysql" {
+		t.Fatalf("arquivo ausente deveria retornar a configuração padrão: %v", err)
+	}
+
+	content := `{"sql": {"dialect": "postgres", "conflict_strategy": "not_exists"}, "validation": {"mode": "strict"}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load falhou: %v", err)
+	}
+	opts, err := config.InsertOptions()
+	if err != nil {
+		t.Fatalf("InsertOptions falhou: %v", err)
+	}
+	if opts.EffectiveDialect().Name() != "postgres" || opts.Conflict != sqlgen.ConflictNotExists || !config.ValidationOptions().Strict {
+		t.Errorf("configuração lida incorretamente: %+v", opts)
+	}
+	if config.SQL.BatchSize != 100 {
+		t.Errorf("campos ausentes do arquivo deveriam manter o padrão: batch_size = %d", config.SQL.BatchSize)
+	}
+	if config.MaxUploadBytes() != 32<<20 || config.RequestTimeout() != 2*time.Minute {
+		t.Errorf("limites padrão da API inesperados: %+v", config.Server)
+	}
+	if (&Config{Server: ServerConfig{MaxFiles: -1}}).Validate() == nil {
+		t.Error("limite negativo da API deveria ser rejeitado")
+	}
+
+	if (&Config{Retorno: RetornoConfig{GuiaInicio: 40, GuiaTamanho: 10}}).Validate() == nil {
+		t.Error("posição da guia fora do código de barras deveria ser rejeitada")
+	}
+
+	if (&Config{Output: OutputConfig{Formats: []string{"xml"}}}).Validate() == nil {
+		t.Error("formato desconhecido deveria ser rejeitado")
+	}
+
+	invalid := Default()
+	invalid.Logging.Format = "xml"
+	if invalid.Validate() == nil {
+		t.Error("formato de log desconhecido deveria ser rejeitado")
+	}
+	invalid = Default()
+	invalid.Paths.OutputDir = ""
+	if invalid.Validate() == nil {
+		t.Error("diretório de saída vazio deveria ser rejeitado")
+	}
+}
+
+func TestPaths(t *testing.T) {
+	config := Default()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("configuração padrão deveria ser válida: %v", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.DarmsDir() != filepath.Join(wd, "darms") || config.OutputDir() != filepath.Join(wd, "inserts") {
+		t.Errorf("diretórios padrão inesperados: %s, %s", config.DarmsDir(), config.OutputDir())
+	}
+
+	base := t.TempDir()
+	config.Paths.BaseDir = base
+	config.Paths.DarmsDir = "pdfs"
+	config.Paths.OutputDir = filepath.Join(base, "saida", "sql")
+	if config.DarmsDir() != filepath.Join(base, "pdfs") || config.OutputDir() != filepath.Join(base, "saida", "sql") {
+		t.Errorf("diretórios deveriam ser resolvidos a partir de base_dir: %s, %s", config.DarmsDir(), config.OutputDir())
+	}
+}
+
+func TestValidateProcessingLimits(t *testing.T) {
+	config := Default()
+	config.Processing.Workers = -1
+	if err := config.Validate(); err == nil {
+		t.Error("processing.workers negativo deveria ser rejeitado")
+	}
+
+	config = Default()
+	config.Processing.TimeoutSeconds = -5
+	if err := config.Validate(); err == nil {
+		t.Error("processing.timeout_seconds negativo deveria ser rejeitado")
+	}
+
+	config.Processing.TimeoutSeconds = 0
+	if err := config.Validate(); err != nil || config.FileTimeout() != 0 {
+		t.Errorf("timeout_seconds 0 desativa o limite por arquivo: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package extraction
+
+import (
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Regex dos campos do DARF (campos numerados 02 a 10 do modelo da Receita
+// Federal). Os campos opcionais (referência, multa e juros) são lidos só na
+// linha do rótulo, para que um campo em branco não capture o número do seguinte.
+var (
+	darfPeriodoRegex    = regexp.MustCompile(`(?i)PER[IÍ]ODO\s+DE\s+APURA[CÇ][AÃ]O\s*:?\s*(\d{2}/\d{2}/\d{4})`)
+	darfCNPJRegex       = regexp.MustCompile(`(?i)(?:CNPJ|CPF)\s*:?\s*(\d[\d./-]{10,17}\d)`)
+	darfReceitaRegex    = regexp.MustCompile(`(?i)C[OÓ]DIGO\s+DA\s+RECEITA\s*:?\s*(\d{4})\b`)
+	darfReferenciaRegex = regexp.MustCompile(`(?i)N[UÚ]MERO\s+DE\s+REFER[EÊ]NCIA[ \t]*:?[ \t]*(\d[\d./-]*)`)
+	vencimentoRegex     = regexp.MustCompile(`(?i)(?:DATA\s+DE\s+)?VENCIMENTO\s*:?\s*(\d{2}/\d{2}/\d{4})`)
+	darfPrincipalRegex  = regexp.MustCompile(`(?i)VALOR\s+DO\s+PRINCIPAL\s*:?\s*R?\$?\s*([\d.,]+)`)
+	darfMultaRegex      = regexp.MustCompile(`(?i)VALOR\s+DA\s+MULTA[ \t]*:?[ \t]*R?\$?[ \t]*([\d.,]+)`)
+	// O rótulo dos juros cita o DL 1025/69: o valor é o primeiro com centavos
+	darfJurosRegex = regexp.MustCompile(`(?i)VALOR\s+DOS\s+JUROS[^\n]*?(\d{1,3}(?:\.\d{3})*,\d{2})`)
+	darfTotalRegex = regexp.MustCompile(`(?i)VALOR\s+TOTAL\s*:?\s*R?\$?\s*([\d.,]+)`)
+)
+
+// DarfData representa os dados extraídos de um DARF
+type DarfData struct {
+	PeriodoApuracao  string `json:"periodoApuracao"`
+	CNPJ             string `json:"cnpj"` // CNPJ ou CPF do contribuinte
+	CodigoReceita    string `json:"codigoReceita"`
+	NumeroReferencia string `json:"numeroReferencia,omitempty"`
+	DataVencimento   string `json:"dataVencimento"`
+	ValorPrincipal   string `json:"valorPrincipal"`
+	ValorMulta       string `json:"valorMulta,omitempty"`
+	ValorJuros       string `json:"valorJuros,omitempty"`
+	ValorTotal       string `json:"valorTotal"`
+}
+
+// ExtractDarf extrai os dados do DARF do texto do PDF. Retorna nil quando
+// faltam o código da receita ou os valores.
+func ExtractDarf(text string) *DarfData {
+	data := &DarfData{
+		PeriodoApuracao:  findField(text, TipoDARF, "periodoApuracao", darfPeriodoRegex),
+		CNPJ:             findField(text, TipoDARF, "cnpj", darfCNPJRegex),
+		CodigoReceita:    findField(text, TipoDARF, "codigoReceita", darfReceitaRegex),
+		NumeroReferencia: findField(text, TipoDARF, "numeroReferencia", darfReferenciaRegex),
+		DataVencimento:   findField(text, TipoDARF, "dataVencimento", vencimentoRegex),
+		ValorPrincipal:   findField(text, TipoDARF, "valorPrincipal", darfPrincipalRegex),
+		ValorMulta:       findField(text, TipoDARF, "valorMulta", darfMultaRegex),
+		ValorJuros:       findField(text, TipoDARF, "valorJuros", darfJurosRegex),
+		ValorTotal:       findField(text, TipoDARF, "valorTotal", darfTotalRegex),
+	}
+
+	if data.CodigoReceita == "" || (data.ValorPrincipal == "" && data.ValorTotal == "") {
+		logrus.Info("Dados insuficientes extraídos do DARF")
+		logrus.Infof("Dados encontrados: %+v", data)
+		return nil
+	}
+	if data.ValorTotal == "" {
+		data.ValorTotal = data.ValorPrincipal
+		logrus.Info("Usando valor do principal como valor total do DARF")
+	}
+	return data
+}
@@ -0,0 +1,240 @@
+// Package extraction lê o texto dos PDFs de DARM e extrai os campos da guia
+// como texto (DarmData), sem conversão nem validação.
+package extraction
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/pix"
+)
+
+// Regex compilados para melhor performance
+var (
+	// Regex para extração de dados
+	inscricaoRegex      = regexp.MustCompile(`(?:Inscrição|INSCRIÇÃO|Inscrição Municipal|Inscrição)\s*:?\s*(\d+)`)
+	inscricaoAltRegex   = regexp.MustCompile(`(?:Inscrição|INSCRIÇÃO)\s*(\d+)`)
+	inscricaoShortRegex = regexp.MustCompile(`Insc\.?\s*:?\s*(\d+)`)
+	inscricaoNumRegex   = regexp.MustCompile(`02\.\s*INSCRIÇÃO MUNICIPAL\s*(\d+)`)
+
+	codigoBarrasRegex = regexp.MustCompile(`[\d\.\s]+`)
+	cleanDigitsRegex  = regexp.MustCompile(`\D`)
+
+	codigoReceitaRegex1 = regexp.MustCompile(`(?:RECEITA|Receita)\s*(\d{1,4}-\d{1,2})(?:[^\d]|$)`)
+	codigoReceitaRegex2 = regexp.MustCompile(`01\.\s*RECEITA\s*(\d{1,4}-\d{1,2})(?:[^\d]|$)`)
+	codigoReceitaRegex3 = regexp.MustCompile(`(\d{1,4})-(\d{1,2})(?:[^\d]|$)`)
+
+	valorPrincipalRegex1 = regexp.MustCompile(`(?:Valor Principal|VALOR PRINCIPAL|Valor principal)\s*:?\s*R?\$?\s*([\d,\.]+)`)
+	valorPrincipalRegex2 = regexp.MustCompile(`(?:Principal|PRINCIPAL)\s*:?\s*R?\$?\s*([\d,\.]+)`)
+	valorPrincipalRegex3 = regexp.MustCompile(`R?\$?\s*([\d,\.]+)\s*(?:Principal|PRINCIPAL)`)
+	valorPrincipalRegex4 = regexp.MustCompile(`06\.\s*VALOR DO TRIBUTO\s*R?\$?\s*([\d,\.]+)`)
+
+	valorTotalRegex1 = regexp.MustCompile(`(?:Valor Total|VALOR TOTAL|Valor total)\s*:?\s*R?\$?\s*([\d,\.]+)`)
+	valorTotalRegex2 = regexp.MustCompile(`(?:Total|TOTAL)\s*:?\s*R?\$?\s*([\d,\.]+)`)
+	valorTotalRegex3 = regexp.MustCompile(`R?\$?\s*([\d,\.]+)\s*(?:Total|TOTAL)`)
+	valorTotalRegex4 = regexp.MustCompile(`09\.\s*VALOR TOTAL\s*R?\$?\s*([\d,\.]+)`)
+
+	dataVencimentoRegex1 = regexp.MustCompile(`(?:Vencimento|VENCIMENTO|Venc\.?)\s*:?\s*(\d{2}/\d{2}/\d{4})`)
+	dataVencimentoRegex2 = regexp.MustCompile(`(\d{2}/\d{2}/\d{4})\s*(?:Vencimento|VENCIMENTO)`)
+	dataVencimentoRegex3 = regexp.MustCompile(`03\.\s*DATA VENCIMENTO\s*(\d{2}/\d{2}/\d{4})`)
+
+	exercicioRegex1 = regexp.MustCompile(`(?:Exercício|EXERCÍCIO|Exerc\.?)\s*:?\s*(\d{4})`)
+	exercicioRegex2 = regexp.MustCompile(`(\d{4})\s*(?:Exercício|EXERCÍCIO)`)
+	exercicioRegex3 = regexp.MustCompile(`04\.\s*ANO DE REFERÊNCIA\s*(\d{4})`)
+
+	numeroGuiaRegex1 = regexp.MustCompile(`05\.\s*GUIA\s*NØ\s*(\d+)`)
+	numeroGuiaRegex2 = regexp.MustCompile(`05\.\s*GUIA\s*NØ(\d+)`)
+	numeroGuiaRegex3 = regexp.MustCompile(`(?:Guia|GUIA|Número da Guia|Nº Guia)\s*:?\s*(\d+)`)
+	numeroGuiaRegex4 = regexp.MustCompile(`(?:Guia|GUIA)\s*(\d+)`)
+	numeroGuiaRegex5 = regexp.MustCompile(`Guia\.?\s*:?\s*(\d+)`)
+
+	competenciaRegex1 = regexp.MustCompile(`(?:Competência|COMPETÊNCIA|Comp\.?)\s*:?\s*(\d{2}/\d{4})`)
+	competenciaRegex2 = regexp.MustCompile(`(\d{2}/\d{4})\s*(?:Competência|COMPETÊNCIA)`)
+)
+
+// DarmData representa os dados extraídos de um DARM
+type DarmData struct {
+	Inscricao      string `json:"inscricao"`
+	CodigoBarras   string `json:"codigoBarras"`
+	CodigoReceita  string `json:"codigoReceita"`
+	ValorPrincipal string `json:"valorPrincipal"`
+	ValorTotal     string `json:"valorTotal"`
+	DataVencimento string `json:"dataVencimento"`
+	Exercicio      string `json:"exercicio"`
+	NumeroGuia     string `json:"numeroGuia"`
+	Competencia    string `json:"competencia"`
+	// PixCopiaECola é o BR Code Pix impresso na guia, quando houver
+	PixCopiaECola string `json:"pixCopiaECola,omitempty"`
+	// NumeroGuiaCompleto é o número da guia impresso no PDF, sem zeros à
+	// esquerda; NumeroGuia fica com os 3 primeiros dígitos usados em NR_GUIA
+	NumeroGuiaCompleto string `json:"numeroGuiaCompleto,omitempty"`
+}
+
+// setNumeroGuia registra o número da guia lido do PDF: o número completo e
+// os 3 primeiros dígitos significativos usados em NR_GUIA
+func (d *DarmData) setNumeroGuia(raw string) {
+	guiaRaw := strings.TrimLeft(strings.TrimSpace(raw), "0")
+	d.NumeroGuiaCompleto = guiaRaw
+	if len(guiaRaw) > 3 {
+		guiaRaw = guiaRaw[:3]
+	}
+	if guiaRaw == "" {
+		guiaRaw = "0"
+	}
+	d.NumeroGuia = guiaRaw
+	logrus.Infof("Campo numeroGuia encontrado: %s", d.NumeroGuia)
+}
+
+// ExtractText extrai os dados do DARM do texto do PDF. Retorna nil quando
+// faltam a inscrição ou os valores.
+func ExtractText(text string) *DarmData {
+	data := &DarmData{}
+
+	// Extrair inscrição
+	if matches := inscricaoRegex.FindStringSubmatch(text); len(matches) > 1 {
+		data.Inscricao = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo inscricao encontrado: %s", data.Inscricao)
+	} else if matches := inscricaoAltRegex.FindStringSubmatch(text); len(matches) > 1 {
+		data.Inscricao = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo inscricao encontrado: %s", data.Inscricao)
+	} else if matches := inscricaoShortRegex.FindStringSubmatch(text); len(matches) > 1 {
+		data.Inscricao = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo inscricao encontrado: %s", data.Inscricao)
+	} else if matches := inscricaoNumRegex.FindStringSubmatch(text); len(matches) > 1 {
+		data.Inscricao = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo inscricao encontrado: %s", data.Inscricao)
+	}
+
+	// Extrair código de barras
+	allMatches := codigoBarrasRegex.FindAllString(text, -1)
+	if len(allMatches) > 0 {
+		codigo := strings.Join(allMatches, "")
+		codigo = cleanDigitsRegex.ReplaceAllString(codigo, "")
+		if len(codigo) > 48 {
+			codigo = codigo[:48]
+		}
+		data.CodigoBarras = codigo
+		logrus.Infof("Campo codigoBarras encontrado: %s", data.CodigoBarras)
+	}
+
+	// Extrair código de receita
+	if matches := codigoReceitaRegex1.FindStringSubmatch(text); len(matches) > 1 {
+		codigoCompleto := matches[1]
+		if strings.Contains(codigoCompleto, "-") {
+			data.CodigoReceita = strings.ReplaceAll(codigoCompleto, "-", "")
+		} else {
+			data.CodigoReceita = codigoCompleto
+		}
+		logrus.Infof("Campo codigoReceita encontrado: %s", data.CodigoReceita)
+	} else if matches := codigoReceitaRegex2.FindStringSubmatch(text); len(matches) > 1 {
+		codigoCompleto := matches[1]
+		if strings.Contains(codigoCompleto, "-") {
+			data.CodigoReceita = strings.ReplaceAll(codigoCompleto, "-", "")
+		} else {
+			data.CodigoReceita = codigoCompleto
+		}
+		logrus.Infof("Campo codigoReceita encontrado: %s", data.CodigoReceita)
+	} else if matches := codigoReceitaRegex3.FindStringSubmatch(text); len(matches) > 2 {
+		data.CodigoReceita = matches[1] + matches[2]
+		logrus.Infof("Campo codigoReceita encontrado: %s", data.CodigoReceita)
+	}
+
+	// Extrair valor principal
+	if matches := valorPrincipalRegex1.FindStringSubmatch(text); len(matches) > 1 {
+		data.ValorPrincipal = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo valorPrincipal encontrado: %s", data.ValorPrincipal)
+	} else if matches := valorPrincipalRegex2.FindStringSubmatch(text); len(matches) > 1 {
+		data.ValorPrincipal = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo valorPrincipal encontrado: %s", data.ValorPrincipal)
+	} else if matches := valorPrincipalRegex3.FindStringSubmatch(text); len(matches) > 1 {
+		data.ValorPrincipal = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo valorPrincipal encontrado: %s", data.ValorPrincipal)
+	} else if matches := valorPrincipalRegex4.FindStringSubmatch(text); len(matches) > 1 {
+		data.ValorPrincipal = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo valorPrincipal encontrado: %s", data.ValorPrincipal)
+	}
+
+	// Extrair valor total
+	if matches := valorTotalRegex1.FindStringSubmatch(text); len(matches) > 1 {
+		data.ValorTotal = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo valorTotal encontrado: %s", data.ValorTotal)
+	} else if matches := valorTotalRegex2.FindStringSubmatch(text); len(matches) > 1 {
+		data.ValorTotal = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo valorTotal encontrado: %s", data.ValorTotal)
+	} else if matches := valorTotalRegex3.FindStringSubmatch(text); len(matches) > 1 {
+		data.ValorTotal = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo valorTotal encontrado: %s", data.ValorTotal)
+	} else if matches := valorTotalRegex4.FindStringSubmatch(text); len(matches) > 1 {
+		data.ValorTotal = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo valorTotal encontrado: %s", data.ValorTotal)
+	}
+
+	// Extrair data de vencimento
+	if matches := dataVencimentoRegex1.FindStringSubmatch(text); len(matches) > 1 {
+		data.DataVencimento = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo dataVencimento encontrado: %s", data.DataVencimento)
+	} else if matches := dataVencimentoRegex2.FindStringSubmatch(text); len(matches) > 1 {
+		data.DataVencimento = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo dataVencimento encontrado: %s", data.DataVencimento)
+	} else if matches := dataVencimentoRegex3.FindStringSubmatch(text); len(matches) > 1 {
+		data.DataVencimento = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo dataVencimento encontrado: %s", data.DataVencimento)
+	}
+
+	// Extrair exercício
+	if matches := exercicioRegex1.FindStringSubmatch(text); len(matches) > 1 {
+		data.Exercicio = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo exercicio encontrado: %s", data.Exercicio)
+	} else if matches := exercicioRegex2.FindStringSubmatch(text); len(matches) > 1 {
+		data.Exercicio = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo exercicio encontrado: %s", data.Exercicio)
+	} else if matches := exercicioRegex3.FindStringSubmatch(text); len(matches) > 1 {
+		data.Exercicio = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo exercicio encontrado: %s", data.Exercicio)
+	}
+
+	// Extrair número da guia
+	if matches := numeroGuiaRegex1.FindStringSubmatch(text); len(matches) > 1 {
+		data.setNumeroGuia(matches[1])
+	} else if matches := numeroGuiaRegex2.FindStringSubmatch(text); len(matches) > 1 {
+		data.setNumeroGuia(matches[1])
+	} else if matches := numeroGuiaRegex3.FindStringSubmatch(text); len(matches) > 1 {
+		data.setNumeroGuia(matches[1])
+	} else if matches := numeroGuiaRegex4.FindStringSubmatch(text); len(matches) > 1 {
+		data.setNumeroGuia(matches[1])
+	} else if matches := numeroGuiaRegex5.FindStringSubmatch(text); len(matches) > 1 {
+		data.setNumeroGuia(matches[1])
+	}
+
+	// Extrair competência
+	if matches := competenciaRegex1.FindStringSubmatch(text); len(matches) > 1 {
+		data.Competencia = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo competencia encontrado: %s", data.Competencia)
+	} else if matches := competenciaRegex2.FindStringSubmatch(text); len(matches) > 1 {
+		data.Competencia = strings.TrimSpace(matches[1])
+		logrus.Infof("Campo competencia encontrado: %s", data.Competencia)
+	}
+
+	// Extrair Pix copia e cola (BR Code com CRC válido)
+	if payload := pix.Find(text); payload != "" {
+		data.PixCopiaECola = payload
+		logrus.Infof("Campo pixCopiaECola encontrado: %s", data.PixCopiaECola)
+	}
+
+	// Validar se temos os dados mínimos necessários
+	if data.Inscricao == "" || (data.ValorPrincipal == "" && data.ValorTotal == "") {
+		logrus.Info("Dados insuficientes extraídos do PDF")
+		logrus.Infof("Dados encontrados: %+v", data)
+		return nil
+	}
+
+	// Se não encontrou valor principal, usar valor total
+	if data.ValorPrincipal == "" && data.ValorTotal != "" {
+		data.ValorPrincipal = data.ValorTotal
+		logrus.Info("Usando valor total como valor principal")
+	}
+
+	return data
+}
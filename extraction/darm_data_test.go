@@ -0,0 +1,136 @@
+package extraction
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestExtractText testa extração de dados do DARM
+func TestExtractText(t *testing.T) {
+	logrus.SetLevel(logrus.ErrorLevel)
+
+	// Texto de exemplo com dados de DARM
+	text := `
+	02. INSCRIÇÃO MUNICIPAL 123456
+	01. RECEITA 262-3
+	06. VALOR DO TRIBUTO R$ 1.234,56
+	09. VALOR TOTAL R$ 1.234,56
+	03. DATA VENCIMENTO 15/12/2024
+	04. ANO DE REFERÊNCIA 2025
+	05. GUIA NØ
+	123456789
+	`
+
+	data := ExtractText(text)
+
+	if data == nil {
+		t.Fatal("Dados não deveriam ser nil")
+	}
+
+	if data.Inscricao != "123456" {
+		t.Errorf("Inscrição esperada: 123456, obtida: %s", data.Inscricao)
+	}
+
+	if data.CodigoReceita != "2623" {
+		t.Errorf("Código de receita esperado: 2623, obtido: %s", data.CodigoReceita)
+	}
+
+	if data.ValorPrincipal != "1.234,56" {
+		t.Errorf("Valor principal esperado: 1.234,56, obtido: %s", data.ValorPrincipal)
+	}
+
+	if data.ValorTotal != "1.234,56" {
+		t.Errorf("Valor total esperado: 1.234,56, obtido: %s", data.ValorTotal)
+	}
+
+	if data.DataVencimento != "15/12/2024" {
+		t.Errorf("Data de vencimento esperada: 15/12/2024, obtida: %s", data.DataVencimento)
+	}
+
+	if data.Exercicio != "2025" {
+		t.Errorf("Exercício esperado: 2025, obtido: %s", data.Exercicio)
+	}
+
+	// NR_GUIA usa os três primeiros dígitos do número impresso na guia
+	if data.NumeroGuia != "123" {
+		t.Errorf("Número da guia esperado: 123, obtido: %s", data.NumeroGuia)
+	}
+}
+
+// TestExtractTextPix testa o BR Code Pix quebrado em linhas pela camada de texto
+func TestExtractTextPix(t *testing.T) {
+	logrus.SetLevel(logrus.ErrorLevel)
+
+	payload := "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+	text := `
+	02. INSCRIÇÃO MUNICIPAL 123456
+	09. VALOR TOTAL R$ 1.234,56
+	05. GUIA NØ
+	123456789
+	PAGUE COM PIX
+	` + payload[:60] + "\n" + payload[60:]
+
+	data := ExtractText(text)
+	if data == nil {
+		t.Fatal("Dados não deveriam ser nil")
+	}
+	if data.PixCopiaECola != payload {
+		t.Errorf("Pix copia e cola esperado: %s, obtido: %s", payload, data.PixCopiaECola)
+	}
+	// NR_GUIA usa os 3 primeiros dígitos; o número completo confere o txid
+	if data.NumeroGuia != "123" || data.NumeroGuiaCompleto != "123456789" {
+		t.Errorf("número da guia esperado 123/123456789, obtido %s/%s", data.NumeroGuia, data.NumeroGuiaCompleto)
+	}
+
+	if data := ExtractText(text[:len(text)-4]); data == nil || data.PixCopiaECola != "" {
+		t.Errorf("BR Code truncado não deveria ser extraído: %+v", data)
+	}
+}
+
+// BenchmarkExtractText testa performance da extração
+func BenchmarkExtractText(b *testing.B) {
+	logrus.SetLevel(logrus.ErrorLevel)
+
+	text := `
+	02. INSCRIÇÃO MUNICIPAL 123456
+	01. RECEITA 262-3
+	06. VALOR DO TRIBUTO R$ 1.234,56
+	09. VALOR TOTAL R$ 1.234,56
+	03. DATA VENCIMENTO 15/12/2024
+	04. ANO DE REFERÊNCIA 2025
+	05. GUIA NØ
+	123456789
+	`
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ExtractText(text)
+	}
+}
+
+func TestExtractInvalidPDF(t *testing.T) {
+	if _, err := Extract(context.Background(), strings.NewReader("não é um PDF")); err == nil {
+		t.Error("conteúdo que não é PDF deveria ser rejeitado")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Extract(ctx, strings.NewReader("não é um PDF")); err == nil {
+		t.Error("contexto cancelado deveria interromper a extração")
+	}
+}
+
+func TestExtractBytesInvalidPDF(t *testing.T) {
+	if _, err := ExtractBytes([]byte("não é um PDF")); err == nil {
+		t.Error("conteúdo que não é PDF deveria ser rejeitado")
+	}
+	if _, err := ExtractReaderAt(strings.NewReader(""), 0); err == nil {
+		t.Error("conteúdo vazio deveria ser rejeitado")
+	}
+	if _, err := ExtractFile("inexistente.pdf"); err == nil {
+		t.Error("arquivo inexistente deveria ser rejeitado")
+	}
+}
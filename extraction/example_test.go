@@ -0,0 +1,24 @@
+package extraction_test
+
+import (
+	"fmt"
+
+	"gerador-query-darm-go/extraction"
+)
+
+func ExampleExtractText() {
+	text := `
+	02. INSCRIÇÃO MUNICIPAL 123456
+	01. RECEITA 262-3
+	06. VALOR DO TRIBUTO R$ 1.234,56
+	09. VALOR TOTAL R$ 1.234,56
+	03. DATA VENCIMENTO 15/12/2024
+	04. ANO DE REFERÊNCIA 2025
+	05. GUIA NØ
+	123456789
+	`
+
+	data := extraction.ExtractText(text)
+	fmt.Printf("inscrição=%s receita=%s total=%s guia=%s\n", data.Inscricao, data.CodigoReceita, data.ValorTotal, data.NumeroGuia)
+	// Output: inscrição=123456 receita=2623 total=1.234,56 guia=123
+}
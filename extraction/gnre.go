@@ -0,0 +1,60 @@
+package extraction
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Regex dos campos da GNRE (modelo da Guia Nacional de Recolhimento)
+var (
+	gnreUFRegex        = regexp.MustCompile(`(?i)UF\s+FAVORECIDA\s*:?\s*([A-Z]{2})\b`)
+	gnreReceitaRegex   = regexp.MustCompile(`(?i)C[OÓ]DIGO\s+DA\s+RECEITA\s*:?\s*(\d{6})\b`)
+	gnreCNPJRegex      = regexp.MustCompile(`(?i)CNPJ(?:/CPF)?\s*:?\s*(\d[\d./-]{10,17}\d)`)
+	gnreOrigemRegex    = regexp.MustCompile(`(?i)DOCUMENTO\s+DE\s+ORIGEM\s*:?\s*(\d+)`)
+	gnrePeriodoRegex   = regexp.MustCompile(`(?i)PER[IÍ]ODO\s+DE\s+REFER[EÊ]NCIA\s*:?\s*(\d{2}/\d{4})`)
+	gnrePrincipalRegex = regexp.MustCompile(`(?i)VALOR\s+PRINCIPAL\s*:?\s*R?\$?\s*([\d.,]+)`)
+	gnreTotalRegex     = regexp.MustCompile(`(?i)TOTAL\s+A\s+RECOLHER\s*:?\s*R?\$?\s*([\d.,]+)`)
+	gnreControleRegex  = regexp.MustCompile(`(?i)CONTROLE\s*:?\s*(\d{16})\b`)
+)
+
+// GnreData representa os dados extraídos de uma GNRE
+type GnreData struct {
+	UFFavorecida      string `json:"ufFavorecida"`
+	CodigoReceita     string `json:"codigoReceita"`
+	CNPJ              string `json:"cnpj"` // CNPJ ou CPF do contribuinte emitente
+	DocumentoOrigem   string `json:"documentoOrigem,omitempty"`
+	PeriodoReferencia string `json:"periodoReferencia,omitempty"`
+	DataVencimento    string `json:"dataVencimento"`
+	ValorPrincipal    string `json:"valorPrincipal"`
+	ValorTotal        string `json:"valorTotal"`
+	NumeroControle    string `json:"numeroControle,omitempty"`
+}
+
+// ExtractGnre extrai os dados da GNRE do texto do PDF. Retorna nil quando
+// faltam a UF favorecida, o código da receita ou os valores.
+func ExtractGnre(text string) *GnreData {
+	data := &GnreData{
+		UFFavorecida:      strings.ToUpper(findField(text, TipoGNRE, "ufFavorecida", gnreUFRegex)),
+		CodigoReceita:     findField(text, TipoGNRE, "codigoReceita", gnreReceitaRegex),
+		CNPJ:              findField(text, TipoGNRE, "cnpj", gnreCNPJRegex),
+		DocumentoOrigem:   findField(text, TipoGNRE, "documentoOrigem", gnreOrigemRegex),
+		PeriodoReferencia: findField(text, TipoGNRE, "periodoReferencia", gnrePeriodoRegex),
+		DataVencimento:    findField(text, TipoGNRE, "dataVencimento", vencimentoRegex),
+		ValorPrincipal:    findField(text, TipoGNRE, "valorPrincipal", gnrePrincipalRegex),
+		ValorTotal:        findField(text, TipoGNRE, "valorTotal", gnreTotalRegex),
+		NumeroControle:    findField(text, TipoGNRE, "numeroControle", gnreControleRegex),
+	}
+
+	if data.UFFavorecida == "" || data.CodigoReceita == "" || (data.ValorPrincipal == "" && data.ValorTotal == "") {
+		logrus.Info("Dados insuficientes extraídos da GNRE")
+		logrus.Infof("Dados encontrados: %+v", data)
+		return nil
+	}
+	if data.ValorTotal == "" {
+		data.ValorTotal = data.ValorPrincipal
+		logrus.Info("Usando valor principal como total a recolher da GNRE")
+	}
+	return data
+}
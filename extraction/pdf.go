@@ -0,0 +1,193 @@
+package extraction
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+	"github.com/sirupsen/logrus"
+)
+
+// Extracao é o resultado da extração de um PDF. Tipo indica qual dos dados
+// foi extraído: Dados (DARM), Darf ou Gnre; vazio equivale a DARM (dados
+// importados ou revisados).
+type Extracao struct {
+	Tipo   string
+	Dados  *DarmData
+	Darf   *DarfData
+	Gnre   *GnreData
+	Texto  string
+	Pagina int    // Página em que os dados foram encontrados (0 = desconhecida)
+	Hash   string // SHA-256 do arquivo PDF
+}
+
+// sizedReaderAt é um io.ReaderAt que conhece o próprio tamanho
+type sizedReaderAt interface {
+	io.ReaderAt
+	Size() int64
+}
+
+// ErrDadosInsuficientes indica que o PDF não contém os campos mínimos do DARM
+var ErrDadosInsuficientes = errors.New("dados insuficientes extraídos do PDF")
+
+// Extract lê um PDF de DARM e retorna os dados extraídos. A leitura do PDF não
+// é interrompível: com o contexto cancelado Extract retorna imediatamente e a
+// extração em andamento é descartada.
+func Extract(ctx context.Context, r io.Reader) (*DarmData, error) {
+	// Leitores com acesso aleatório (bytes.Reader, strings.Reader, SectionReader)
+	// são usados diretamente; os demais são lidos para a memória
+	source, ok := r.(sizedReaderAt)
+	if !ok {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler PDF: %v", err)
+		}
+		source = bytes.NewReader(content)
+	}
+
+	type extractResult struct {
+		extracao *Extracao
+		err      error
+	}
+
+	// Canal com buffer: a goroutine termina mesmo se o resultado for descartado
+	done := make(chan extractResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- extractResult{err: fmt.Errorf("panic durante a extração: %v", r)}
+			}
+		}()
+		extracao, err := ExtractReaderAt(source, source.Size())
+		done <- extractResult{extracao: extracao, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-done:
+		if result.err != nil {
+			return nil, result.err
+		}
+		switch tipo := result.extracao.Tipo; {
+		case tipo == TipoDesconhecido:
+			return nil, ErrTipoDesconhecido
+		case tipo != TipoDARM:
+			return nil, fmt.Errorf("documento do tipo %s não é um DARM", tipo)
+		case result.extracao.Dados == nil:
+			return nil, ErrDadosInsuficientes
+		}
+		return result.extracao.Dados, nil
+	}
+}
+
+// ExtractFile extrai os dados do DARM de um arquivo PDF
+func ExtractFile(filePath string) (*Extracao, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %v", err)
+	}
+	return ExtractReaderAt(file, info.Size())
+}
+
+// ExtractBytes extrai os dados do DARM de um PDF em memória (upload HTTP,
+// arquivo de um ZIP, anexo de e-mail)
+func ExtractBytes(content []byte) (*Extracao, error) {
+	return ExtractReaderAt(bytes.NewReader(content), int64(len(content)))
+}
+
+// ExtractReaderAt classifica o documento dos size bytes de r e extrai os dados
+// do tipo reconhecido, com o texto, a página e o hash para a proveniência (os
+// dados são nil quando faltam campos ou o tipo é desconhecido)
+func ExtractReaderAt(r io.ReaderAt, size int64) (*Extracao, error) {
+	pages, err := ExtractPages(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao extrair texto do PDF: %v", err)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(r, 0, size)); err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %v", err)
+	}
+
+	text := strings.Join(pages, "")
+	extracao := &Extracao{
+		Tipo:  Classify(text),
+		Texto: text,
+		Hash:  hex.EncodeToString(hash.Sum(nil)),
+	}
+	logrus.Infof("Tipo de documento: %s", extracao.Tipo)
+
+	switch extracao.Tipo {
+	case TipoDARM:
+		if extracao.Dados = ExtractText(text); extracao.Dados != nil {
+			extracao.Pagina = findPage(pages, extracao.Dados.Inscricao)
+		}
+	case TipoDARF:
+		if extracao.Darf = ExtractDarf(text); extracao.Darf != nil {
+			extracao.Pagina = findPage(pages, extracao.Darf.CNPJ)
+		}
+	case TipoGNRE:
+		if extracao.Gnre = ExtractGnre(text); extracao.Gnre != nil {
+			extracao.Pagina = findPage(pages, extracao.Gnre.CodigoReceita)
+		}
+	}
+	return extracao, nil
+}
+
+// findPage retorna a primeira página (1-based) que contém o campo extraído
+// (a inscrição do DARM, o CNPJ do DARF, a receita da GNRE)
+func findPage(pages []string, campo string) int {
+	if campo == "" {
+		return 0
+	}
+	for i, page := range pages {
+		if strings.Contains(page, campo) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ExtractPages extrai o texto de cada página de um PDF
+func ExtractPages(r io.ReaderAt, size int64) ([]string, error) {
+	reader, err := pdf.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir PDF: %v", err)
+	}
+
+	totalPage := reader.NumPage()
+	pages := make([]string, 0, totalPage)
+
+	for pageIndex := 1; pageIndex <= totalPage; pageIndex++ {
+		page := reader.Page(pageIndex)
+		if page.V.IsNull() {
+			pages = append(pages, "")
+			continue
+		}
+
+		textContent, err := page.GetPlainText(nil)
+		if err != nil {
+			logrus.Warnf("Erro ao extrair texto da página %d: %v", pageIndex, err)
+			pages = append(pages, "")
+			continue
+		}
+
+		pages = append(pages, textContent)
+	}
+
+	return pages, nil
+}
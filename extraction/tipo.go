@@ -0,0 +1,59 @@
+package extraction
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Tipos de documento reconhecidos no texto do PDF
+const (
+	TipoDARM = "DARM" // Documento de Arrecadação de Receitas Municipais
+	TipoDARF = "DARF" // Documento de Arrecadação de Receitas Federais
+	TipoGNRE = "GNRE" // Guia Nacional de Recolhimento de Tributos Estaduais
+	// TipoDesconhecido marca textos sem as marcas de nenhum dos tipos
+	TipoDesconhecido = "desconhecido"
+)
+
+// ErrTipoDesconhecido indica um PDF que não é DARM, DARF nem GNRE
+var ErrTipoDesconhecido = errors.New("tipo de documento não reconhecido (esperado DARM, DARF ou GNRE)")
+
+// marcasTipo são as marcas de cada tipo de documento, na ordem em que são
+// testadas: primeiro as siglas, depois os títulos. DARF e GNRE vêm antes do
+// DARM porque também trazem inscrições e valores que os regex do DARM aceitam.
+var marcasTipo = []struct {
+	tipo  string
+	regex *regexp.Regexp
+}{
+	{TipoGNRE, regexp.MustCompile(`(?i)\bGNRE\b`)},
+	{TipoDARF, regexp.MustCompile(`(?i)\bDARF\b`)},
+	{TipoDARM, regexp.MustCompile(`(?i)\bDARM\b`)},
+	{TipoGNRE, regexp.MustCompile(`(?i)TRIBUTOS\s+ESTADUAIS`)},
+	{TipoDARF, regexp.MustCompile(`(?i)RECEITAS?\s+FEDERA(?:L|IS)`)},
+	{TipoDARM, regexp.MustCompile(`(?i)MUNICIPA|INSC`)},
+}
+
+// Classify identifica o tipo do documento pelo texto do PDF
+func Classify(text string) string {
+	for _, marca := range marcasTipo {
+		if marca.regex.MatchString(text) {
+			return marca.tipo
+		}
+	}
+	return TipoDesconhecido
+}
+
+// findField retorna o primeiro grupo capturado por um dos regex, registrando o
+// campo encontrado no log
+func findField(text, tipo, campo string, regexes ...*regexp.Regexp) string {
+	for _, regex := range regexes {
+		if matches := regex.FindStringSubmatch(text); len(matches) > 1 {
+			value := strings.TrimSpace(matches[1])
+			logrus.Infof("Campo %s %s encontrado: %s", tipo, campo, value)
+			return value
+		}
+	}
+	return ""
+}
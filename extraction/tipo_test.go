@@ -0,0 +1,110 @@
+package extraction
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// textoDarf é a camada de texto de um DARF comum (campos 02 a 10)
+const textoDarf = `
+MINISTÉRIO DA FAZENDA
+SECRETARIA DA RECEITA FEDERAL DO BRASIL
+Documento de Arrecadação de Receitas Federais
+DARF
+02 PERÍODO DE APURAÇÃO 31/12/2024
+03 NÚMERO DO CPF OU CNPJ 11.222.333/0001-81
+04 CÓDIGO DA RECEITA 2089
+05 NÚMERO DE REFERÊNCIA
+06 DATA DE VENCIMENTO 31/01/2025
+07 VALOR DO PRINCIPAL 1.000,00
+08 VALOR DA MULTA 20,00
+09 VALOR DOS JUROS E/OU ENCARGOS DL - 1025/69 10,50
+10 VALOR TOTAL 1.030,50
+`
+
+// textoGnre é a camada de texto de uma GNRE
+const textoGnre = `
+GNRE - Guia Nacional de Recolhimento de Tributos Estaduais
+UF Favorecida: SP
+Código da Receita: 100099
+CNPJ/CPF: 11.222.333/0001-81
+Inscrição Estadual: 123456789
+Nº Documento de Origem: 445566
+Período de Referência: 12/2024
+Data de Vencimento: 10/01/2025
+Valor Principal: R$ 500,00
+Multa: R$ 0,00
+Total a Recolher: R$ 500,00
+Nº de Controle: 2025010100000017
+`
+
+func TestClassify(t *testing.T) {
+	tests := map[string]string{
+		textoDarf: TipoDARF,
+		textoGnre: TipoGNRE,
+		"DARM - Documento de Arrecadação de Receitas Municipais\nPeríodo de apuração 12/2024": TipoDARM,
+		"02. INSCRIÇÃO MUNICIPAL 123456\n09. VALOR TOTAL R$ 1.234,56":                         TipoDARM,
+		"Guia de Recolhimento do FGTS\nValor a recolher 1.234,56":                             TipoDesconhecido,
+		"": TipoDesconhecido,
+	}
+	for text, expected := range tests {
+		if tipo := Classify(text); tipo != expected {
+			t.Errorf("Classify(%q) = %s, esperado %s", text, tipo, expected)
+		}
+	}
+}
+
+func TestExtractDarf(t *testing.T) {
+	logrus.SetLevel(logrus.ErrorLevel)
+
+	data := ExtractDarf(textoDarf)
+	if data == nil {
+		t.Fatal("Dados não deveriam ser nil")
+	}
+	expected := DarfData{
+		PeriodoApuracao: "31/12/2024",
+		CNPJ:            "11.222.333/0001-81",
+		CodigoReceita:   "2089",
+		DataVencimento:  "31/01/2025",
+		ValorPrincipal:  "1.000,00",
+		ValorMulta:      "20,00",
+		ValorJuros:      "10,50",
+		ValorTotal:      "1.030,50",
+	}
+	if *data != expected {
+		t.Errorf("DARF inesperado:\n%+v\nesperado:\n%+v", *data, expected)
+	}
+
+	if data := ExtractDarf(strings.Replace(textoDarf, "CÓDIGO DA RECEITA 2089", "", 1)); data != nil {
+		t.Errorf("DARF sem código da receita deveria ser nil: %+v", data)
+	}
+}
+
+func TestExtractGnre(t *testing.T) {
+	logrus.SetLevel(logrus.ErrorLevel)
+
+	data := ExtractGnre(textoGnre)
+	if data == nil {
+		t.Fatal("Dados não deveriam ser nil")
+	}
+	expected := GnreData{
+		UFFavorecida:      "SP",
+		CodigoReceita:     "100099",
+		CNPJ:              "11.222.333/0001-81",
+		DocumentoOrigem:   "445566",
+		PeriodoReferencia: "12/2024",
+		DataVencimento:    "10/01/2025",
+		ValorPrincipal:    "500,00",
+		ValorTotal:        "500,00",
+		NumeroControle:    "2025010100000017",
+	}
+	if *data != expected {
+		t.Errorf("GNRE inesperada:\n%+v\nesperado:\n%+v", *data, expected)
+	}
+
+	if data := ExtractGnre(strings.Replace(textoGnre, "UF Favorecida: SP", "", 1)); data != nil {
+		t.Errorf("GNRE sem UF favorecida deveria ser nil: %+v", data)
+	}
+}
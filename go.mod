@@ -3,8 +3,12 @@ module gerador-query-darm-go
 go 1.21
 
 require (
+	github.com/go-sql-driver/mysql v1.8.1
 	github.com/ledongthuc/pdf v0.0.0-20220302134840-0c2507a12d80
 	github.com/sirupsen/logrus v1.9.3
 )
 
-require golang.org/x/sys v0.5.0 // indirect
+require (
+	filippo.io/edwards25519 v1.1.0 // indirect
+	golang.org/x/sys v0.5.0 // indirect
+)
@@ -0,0 +1,275 @@
+// Package importer lê dados de DARM digitados ou recebidos em planilha (CSV,
+// JSON ou JSON Lines) como documentos já extraídos, para que passem pela
+// mesma validação, geração de SQL e relatório dos PDFs.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/processor"
+)
+
+// Formatos de entrada aceitos
+const (
+	FormatCSV   = "csv"
+	FormatJSON  = "json"
+	FormatJSONL = "jsonl"
+)
+
+// Registro é um DARM lido da entrada
+type Registro struct {
+	// Origem identifica o registro no relatório: a coluna/campo "arquivo" ou
+	// <arquivo de entrada>:<linha ou posição>
+	Origem string
+	Dados  extraction.DarmData
+	// Texto é o conteúdo original do registro, mantido para auditoria
+	Texto string
+
+	posicao int // Linha ou posição na entrada, para numerar a origem
+}
+
+// Documento converte o registro em um documento já extraído do processador
+func (r Registro) Documento() processor.Documento {
+	dados := r.Dados
+	return processor.Documento{
+		Nome:     r.Origem,
+		Extracao: &extraction.Extracao{Dados: &dados, Texto: r.Texto},
+	}
+}
+
+// Documentos converte os registros em documentos do processador
+func Documentos(registros []Registro) []processor.Documento {
+	docs := make([]processor.Documento, 0, len(registros))
+	for _, r := range registros {
+		docs = append(docs, r.Documento())
+	}
+	return docs
+}
+
+// registroJSON é um objeto da entrada JSON/JSONL: os campos de DarmData e,
+// opcionalmente, o nome do documento de origem
+type registroJSON struct {
+	Arquivo string `json:"arquivo"`
+	extraction.DarmData
+}
+
+// csvColumns associa os nomes de coluna aceitos no CSV (os do JSON de
+// DarmData e os do DARMs.csv exportado) aos campos
+var csvColumns = map[string]func(d *extraction.DarmData) *string{
+	"inscricao":        func(d *extraction.DarmData) *string { return &d.Inscricao },
+	"codigobarras":     func(d *extraction.DarmData) *string { return &d.CodigoBarras },
+	"codigo_barras":    func(d *extraction.DarmData) *string { return &d.CodigoBarras },
+	"codigoreceita":    func(d *extraction.DarmData) *string { return &d.CodigoReceita },
+	"codigo_receita":   func(d *extraction.DarmData) *string { return &d.CodigoReceita },
+	"valorprincipal":   func(d *extraction.DarmData) *string { return &d.ValorPrincipal },
+	"valor_principal":  func(d *extraction.DarmData) *string { return &d.ValorPrincipal },
+	"valortotal":       func(d *extraction.DarmData) *string { return &d.ValorTotal },
+	"valor_total":      func(d *extraction.DarmData) *string { return &d.ValorTotal },
+	"datavencimento":   func(d *extraction.DarmData) *string { return &d.DataVencimento },
+	"data_vencimento":  func(d *extraction.DarmData) *string { return &d.DataVencimento },
+	"exercicio":        func(d *extraction.DarmData) *string { return &d.Exercicio },
+	"numeroguia":       func(d *extraction.DarmData) *string { return &d.NumeroGuia },
+	"numero_guia":      func(d *extraction.DarmData) *string { return &d.NumeroGuia },
+	"competencia":      func(d *extraction.DarmData) *string { return &d.Competencia },
+	"pixcopiaecola":    func(d *extraction.DarmData) *string { return &d.PixCopiaECola },
+	"pix_copia_e_cola": func(d *extraction.DarmData) *string { return &d.PixCopiaECola },
+}
+
+// FormatFromPath deduz o formato da extensão do arquivo
+func FormatFromPath(path string) (string, error) {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if err := validateFormat(format); err != nil {
+		return "", fmt.Errorf("não foi possível deduzir o formato de %s: use -format=csv, json ou jsonl", filepath.Base(path))
+	}
+	return format, nil
+}
+
+// validateFormat verifica se o formato é aceito
+func validateFormat(format string) error {
+	switch format {
+	case FormatCSV, FormatJSON, FormatJSONL:
+		return nil
+	}
+	return fmt.Errorf("formato de entrada inválido: %q (use csv, json ou jsonl)", format)
+}
+
+// ReadFile lê os registros de um arquivo; format vazio é deduzido da extensão
+func ReadFile(path, format string) ([]Registro, error) {
+	if format == "" {
+		var err error
+		if format, err = FormatFromPath(path); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de entrada: %v", err)
+	}
+	defer file.Close()
+
+	registros, err := Read(file, format, filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar %s: %v", filepath.Base(path), err)
+	}
+	return registros, nil
+}
+
+// Read lê os registros no formato informado; nome identifica a entrada na
+// origem dos registros sem coluna "arquivo"
+func Read(r io.Reader, format, nome string) ([]Registro, error) {
+	if err := validateFormat(strings.ToLower(format)); err != nil {
+		return nil, err
+	}
+
+	var registros []Registro
+	var err error
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		registros, err = readCSV(r)
+	case FormatJSON:
+		registros, err = readJSON(r)
+	default:
+		registros, err = readJSONL(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+	numerarOrigens(registros, nome)
+	return registros, nil
+}
+
+// numerarOrigens nomeia os registros sem origem como <nome>:<posição>, com
+// zeros à esquerda para que a ordem do relatório siga a da entrada
+func numerarOrigens(registros []Registro, nome string) {
+	width := 1
+	if n := len(registros); n > 0 {
+		width = len(strconv.Itoa(registros[n-1].posicao))
+	}
+	for i := range registros {
+		if registros[i].Origem == "" {
+			registros[i].Origem = fmt.Sprintf("%s:%0*d", nome, width, registros[i].posicao)
+		}
+	}
+}
+
+// readCSV lê um CSV separado por ponto e vírgula com cabeçalho; a posição
+// dos registros é a linha do arquivo
+func readCSV(r io.Reader) ([]Registro, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("arquivo vazio")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	arquivoColumn := -1
+	fields := make([]func(d *extraction.DarmData) *string, len(header))
+	known := 0
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if column == "arquivo" {
+			arquivoColumn = i
+		}
+		if field, ok := csvColumns[column]; ok {
+			fields[i] = field
+			known++
+		}
+	}
+	if known == 0 {
+		return nil, fmt.Errorf("cabeçalho sem colunas de DARM (ex.: inscricao;codigoReceita;valorPrincipal;valorTotal;dataVencimento;exercicio;numeroGuia)")
+	}
+
+	registros := []Registro{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		registro := Registro{Texto: strings.Join(record, ";"), posicao: line}
+		for i, value := range record {
+			if i < len(fields) && fields[i] != nil {
+				*fields[i](&registro.Dados) = strings.TrimSpace(value)
+			}
+			if i == arquivoColumn {
+				registro.Origem = strings.TrimSpace(value)
+			}
+		}
+		registros = append(registros, registro)
+	}
+	return registros, nil
+}
+
+// decodeRegistro decodifica um objeto JSON de DARM, rejeitando campos desconhecidos
+func decodeRegistro(raw []byte, posicao int) (Registro, error) {
+	var obj registroJSON
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&obj); err != nil {
+		return Registro{}, err
+	}
+	return Registro{Origem: strings.TrimSpace(obj.Arquivo), Dados: obj.DarmData, Texto: string(raw), posicao: posicao}, nil
+}
+
+// readJSON lê um array de objetos; a posição dos registros é o índice (a partir de 1)
+func readJSON(r io.Reader) ([]Registro, error) {
+	var raws []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raws); err != nil {
+		return nil, err
+	}
+
+	registros := make([]Registro, 0, len(raws))
+	for i, raw := range raws {
+		registro, err := decodeRegistro(raw, i+1)
+		if err != nil {
+			return nil, fmt.Errorf("registro %d: %v", i+1, err)
+		}
+		registros = append(registros, registro)
+	}
+	return registros, nil
+}
+
+// readJSONL lê um objeto por linha, ignorando linhas em branco; a posição dos
+// registros é a linha do arquivo
+func readJSONL(r io.Reader) ([]Registro, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+
+	registros := []Registro{}
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		registro, err := decodeRegistro(append([]byte(nil), raw...), line)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: %v", line, err)
+		}
+		registros = append(registros, registro)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return registros, nil
+}
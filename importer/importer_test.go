@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/extraction"
+)
+
+func TestRead(t *testing.T) {
+	expected := extraction.DarmData{
+		Inscricao:      "90001",
+		CodigoReceita:  "2623",
+		ValorPrincipal: "1.234,56",
+		ValorTotal:     "1.234,56",
+		DataVencimento: "15/12/2024",
+		Exercicio:      "2025",
+		NumeroGuia:     "1",
+	}
+	inputs := map[string]string{
+		FormatCSV: "\ufeffInscricao;codigoReceita;valorPrincipal;valorTotal;dataVencimento;exercicio;numeroGuia;observacao\n" +
+			"90001;2623;1.234,56;1.234,56;15/12/2024;2025;1;digitado\n",
+		FormatJSON: `[{"inscricao": "90001", "codigoReceita": "2623", "valorPrincipal": "1.234,56",
+			"valorTotal": "1.234,56", "dataVencimento": "15/12/2024", "exercicio": "2025", "numeroGuia": "1"}]`,
+		FormatJSONL: "\n" + `{"inscricao": "90001", "codigoReceita": "2623", "valorPrincipal": "1.234,56", "valorTotal": "1.234,56", "dataVencimento": "15/12/2024", "exercicio": "2025", "numeroGuia": "1"}` + "\n",
+	}
+	for format, input := range inputs {
+		registros, err := Read(strings.NewReader(input), format, "entrada")
+		if err != nil {
+			t.Fatalf("%s: Read falhou: %v", format, err)
+		}
+		if len(registros) != 1 || registros[0].Dados != expected {
+			t.Fatalf("%s: registros inesperados: %+v", format, registros)
+		}
+		if registros[0].Origem != "entrada:"+map[string]string{FormatCSV: "2", FormatJSON: "1", FormatJSONL: "2"}[format] {
+			t.Errorf("%s: origem inesperada: %q", format, registros[0].Origem)
+		}
+		if registros[0].Texto == "" {
+			t.Errorf("%s: texto original deveria ser mantido", format)
+		}
+	}
+}
+
+func TestReadCSVExportColumns(t *testing.T) {
+	// Colunas do DARMs.csv exportado, com a coluna arquivo como origem
+	input := "arquivo;status;inscricao;codigo_receita;valor_total;numero_guia\n" +
+		"0001.pdf;valido;90001;2623;1234.56;1\n" +
+		";valido;90002;2623;10.00;2\n"
+	for i := 0; i < 9; i++ {
+		input += ";valido;9;2623;1.00;3\n"
+	}
+
+	registros, err := Read(strings.NewReader(input), "CSV", "planilha.csv")
+	if err != nil {
+		t.Fatalf("Read falhou: %v", err)
+	}
+	if len(registros) != 11 {
+		t.Fatalf("esperados 11 registros, obtidos %d", len(registros))
+	}
+	if registros[0].Origem != "0001.pdf" || registros[0].Dados.ValorTotal != "1234.56" || registros[0].Dados.NumeroGuia != "1" {
+		t.Errorf("primeiro registro inesperado: %+v", registros[0])
+	}
+	// A posição tem zeros à esquerda para manter a ordem do relatório
+	if registros[1].Origem != "planilha.csv:03" || registros[10].Origem != "planilha.csv:12" {
+		t.Errorf("origens inesperadas: %q, %q", registros[1].Origem, registros[10].Origem)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	invalid := map[string]string{
+		"vazio.csv":      "",
+		"cabecalho.csv":  "nome;valor\na;1\n",
+		"campo.json":     `[{"inscricao": "1", "valor": "1"}]`,
+		"objeto.json":    `{"inscricao": "1"}`,
+		"linha.jsonl":    "{\"inscricao\": \"1\"}\n{\"inscricao\":\n",
+		"planilha.xlsx":  "",
+		"formato.txt":    "",
+		"aspas.csv":      "inscricao;numeroGuia\n\"1;2\n",
+		"desconhec.json": `[{"inscricao": "1", "status": "valido"}]`,
+	}
+	dir := t.TempDir()
+	for name, content := range invalid {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadFile(path, ""); err == nil {
+			t.Errorf("%s deveria ser rejeitado", name)
+		}
+	}
+
+	if _, err := ReadFile(filepath.Join(dir, "inexistente.csv"), ""); err == nil {
+		t.Error("arquivo inexistente deveria falhar")
+	}
+	if _, err := Read(strings.NewReader("[]"), "xml", "entrada"); err == nil {
+		t.Error("formato inválido deveria falhar")
+	}
+}
+
+func TestDocumentos(t *testing.T) {
+	registros := []Registro{{Origem: "planilha.csv:2", Dados: extraction.DarmData{NumeroGuia: "1"}, Texto: "1"}}
+	docs := Documentos(registros)
+	if len(docs) != 1 || docs[0].Nome != "planilha.csv:2" || docs[0].Extracao == nil || docs[0].Extracao.Dados.NumeroGuia != "1" {
+		t.Fatalf("documentos inesperados: %+v", docs)
+	}
+
+	// O documento não compartilha os dados com o registro
+	docs[0].Extracao.Dados.NumeroGuia = "2"
+	if registros[0].Dados.NumeroGuia != "1" {
+		t.Error("alterar o documento não deveria alterar o registro")
+	}
+}
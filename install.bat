@@ -140,7 +140,7 @@ REM Função para compilar o projeto
 call :print_message "🔨 Compilando o projeto..." "%BLUE%"
     
 REM Compilar
-go build -ldflags="-X main.version=%VERSION%" -o build\%BINARY_NAME%.exe .
+go build -ldflags="-X main.version=%VERSION%" -o build\%BINARY_NAME%.exe .\cmd\darm-processor
     
 call :print_message "✅ Executável criado: build\%BINARY_NAME%.exe" "%GREEN%"
 goto :eof
@@ -203,7 +203,7 @@ echo   make clean       - Remove arquivos de build
 echo.
 call :print_message "📚 Documentação:" "%YELLOW%"
 echo   README_Go.md     - Documentação completa
-echo   exemplo_uso.go   - Exemplos de uso
+echo   processor\example_test.go - Exemplos de uso (go test)
 goto :eof
 
 REM Função principal
@@ -144,7 +144,7 @@ build_project() {
     print_message "🔨 Compilando o projeto..." "$BLUE"
     
     # Compilar
-    go build -ldflags="-X main.version=$VERSION" -o build/$BINARY_NAME .
+    go build -ldflags="-X main.version=$VERSION" -o build/$BINARY_NAME ./cmd/darm-processor
     
     print_message "✅ Executável criado: build/$BINARY_NAME" "$GREEN"
 }
@@ -207,7 +207,7 @@ show_next_steps() {
     print_message "" "$NC"
     print_message "📚 Documentação:" "$YELLOW"
     print_message "  README_Go.md     - Documentação completa" "$NC"
-    print_message "  exemplo_uso.go   - Exemplos de uso" "$NC"
+    print_message "  processor/example_test.go - Exemplos de uso (go test)" "$NC"
 }
 
 # Função principal
@@ -0,0 +1,25 @@
+// Package darmtest contém dados de DARM determinísticos compartilhados pelos
+// testes dos pacotes
+package darmtest
+
+import (
+	"path/filepath"
+	"strings"
+
+	"gerador-query-darm-go/extraction"
+)
+
+// DarmData gera dados de DARM determinísticos a partir do nome do arquivo
+func DarmData(filePath string) *extraction.DarmData {
+	base := strings.TrimSuffix(filepath.Base(filePath), ".pdf")
+	return &extraction.DarmData{
+		Inscricao:      "9" + base,
+		CodigoReceita:  "2623",
+		ValorPrincipal: "1.234,56",
+		ValorTotal:     "1.234,56",
+		DataVencimento: "15/12/2024",
+		Exercicio:      "2025",
+		NumeroGuia:     strings.TrimLeft(base, "0"),
+		Competencia:    "11/2024",
+	}
+}
@@ -0,0 +1,29 @@
+package darmtest
+
+import (
+	"fmt"
+
+	"gerador-query-darm-go/pix"
+)
+
+// tlv monta um campo ID + tamanho + valor do BR Code
+func tlv(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// PixBRCode gera um BR Code Pix estático com CRC válido (valor vazio = valor livre)
+func PixBRCode(chave, valor, txid string) string {
+	payload := tlv("00", "01") +
+		tlv("26", tlv("00", pix.GUI)+tlv("01", chave)) +
+		tlv("52", "0000") +
+		tlv("53", "986")
+	if valor != "" {
+		payload += tlv("54", valor)
+	}
+	payload += tlv("58", "BR") +
+		tlv("59", "PREFEITURA MUNICIPAL") +
+		tlv("60", "RIO DE JANEIRO") +
+		tlv("62", tlv("05", txid)) +
+		"6304"
+	return payload + fmt.Sprintf("%04X", pix.CRC16(payload))
+}
@@ -0,0 +1,127 @@
+package darmtest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PagamentoRetorno é um pagamento dos arquivos de retorno de teste
+type PagamentoRetorno struct {
+	CodigoBarras string
+	Centavos     int64
+	Data         time.Time
+	Autenticacao string
+}
+
+// CodigoBarras gera o código de barras de arrecadação (44 dígitos) de uma
+// guia de prefeitura, com o número da guia nas posições 20 a 29
+func CodigoBarras(guia int, centavos int64) string {
+	return fmt.Sprintf("8160%011d0000%010d%015d", centavos, guia, 0)
+}
+
+// registro monta uma linha de largura fixa a partir dos campos (posição
+// inicial a partir de 1 → valor)
+func registro(size int, campos map[int]string) string {
+	line := []byte(strings.Repeat(" ", size))
+	for pos, value := range campos {
+		copy(line[pos-1:], value)
+	}
+	return string(line)
+}
+
+// RetornoFebraban150 gera um arquivo de retorno de arrecadação FEBRABAN
+// (registros A, G e Z) com os totais corretos no trailer
+func RetornoFebraban150(banco, nsa int, pagamentos []PagamentoRetorno) string {
+	lines := []string{registro(150, map[int]string{
+		1:  "A2",
+		3:  "CONVENIO123",
+		23: "PREFEITURA",
+		43: fmt.Sprintf("%03d", banco),
+		46: "BANCO TESTE",
+		66: "20250110",
+		74: fmt.Sprintf("%06d", nsa),
+		80: "05",
+		82: "CODIGO DE BARRAS",
+	})}
+
+	var total int64
+	for i, p := range pagamentos {
+		lines = append(lines, registro(150, map[int]string{
+			1:   "G",
+			2:   "00010000000000012345",
+			22:  p.Data.Format("20060102"),
+			30:  p.Data.AddDate(0, 0, 1).Format("20060102"),
+			38:  p.CodigoBarras,
+			82:  fmt.Sprintf("%012d", p.Centavos),
+			94:  "0000150",
+			101: fmt.Sprintf("%08d", i+1),
+			109: "00001234",
+			117: "1",
+			118: p.Autenticacao,
+			141: "1",
+		}))
+		total += p.Centavos
+	}
+
+	lines = append(lines, registro(150, map[int]string{
+		1: "Z",
+		2: fmt.Sprintf("%06d", len(lines)+1),
+		8: fmt.Sprintf("%017d", total),
+	}))
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// RetornoCNAB240 gera um arquivo de retorno CNAB 240 com um lote de
+// pagamentos de tributos (segmentos O e Z) e os totais corretos nos trailers
+func RetornoCNAB240(banco, nsa int, pagamentos []PagamentoRetorno) string {
+	prefixo := fmt.Sprintf("%03d", banco)
+	lines := []string{registro(240, map[int]string{
+		1:   prefixo + "00000",
+		18:  "2",
+		19:  "12345678000199",
+		33:  "CONVENIO123",
+		73:  "PREFEITURA",
+		103: "BANCO TESTE",
+		143: "2",
+		144: "10012025",
+		152: "120000",
+		158: fmt.Sprintf("%06d", nsa),
+		164: "089",
+	})}
+	lines = append(lines, registro(240, map[int]string{1: prefixo + "00011", 9: "C"}))
+
+	var total int64
+	for i, p := range pagamentos {
+		lines = append(lines, registro(240, map[int]string{
+			1:   prefixo + "00013",
+			9:   fmt.Sprintf("%05d", 2*i+1),
+			14:  "O",
+			18:  p.CodigoBarras,
+			62:  "PREFEITURA",
+			92:  p.Data.Format("02012006"),
+			100: p.Data.Format("02012006"),
+			108: fmt.Sprintf("%015d", p.Centavos),
+			231: "00",
+		}))
+		lines = append(lines, registro(240, map[int]string{
+			1:  prefixo + "00013",
+			9:  fmt.Sprintf("%05d", 2*i+2),
+			14: "Z",
+			15: p.Autenticacao,
+		}))
+		total += p.Centavos
+	}
+
+	lines = append(lines, registro(240, map[int]string{
+		1:  prefixo + "00015",
+		18: fmt.Sprintf("%06d", len(lines)),
+		24: fmt.Sprintf("%018d", total),
+	}))
+	lines = append(lines, registro(240, map[int]string{
+		1:  prefixo + "99999",
+		18: "000001",
+		24: fmt.Sprintf("%06d", len(lines)+1),
+	}))
+	return strings.Join(lines, "\n") + "\n"
+}
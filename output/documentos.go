@@ -0,0 +1,138 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/validation"
+)
+
+// Arquivos de exportação dos documentos que não são DARM (não geram INSERT
+// em FarrDarmsPagos)
+const (
+	DarfFile = "DARFs.csv"
+	GnreFile = "GNREs.csv"
+)
+
+// ResultadoDocumento é um DARF ou uma GNRE processado: Tipo indica qual dos
+// registros está preenchido
+type ResultadoDocumento struct {
+	Arquivo      string
+	Tipo         string
+	Darf         *validation.DarfRecord
+	Gnre         *validation.GnreRecord
+	Proveniencia Proveniencia
+	Duracao      time.Duration
+}
+
+// darfHeader lista as colunas de DARFs.csv
+var darfHeader = []string{
+	"arquivo", "pagina", "hash_sha256", "periodo_apuracao", "cnpj_cpf", "codigo_receita", "numero_referencia",
+	"data_vencimento", "valor_principal", "valor_multa", "valor_juros", "valor_total",
+}
+
+// gnreHeader lista as colunas de GNREs.csv
+var gnreHeader = []string{
+	"arquivo", "pagina", "hash_sha256", "uf_favorecida", "codigo_receita", "cnpj_cpf", "documento_origem",
+	"periodo_referencia", "data_vencimento", "valor_principal", "valor_total", "numero_controle",
+}
+
+// formatData formata uma data opcional como DD/MM/YYYY
+func formatData(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format("02/01/2006")
+}
+
+// writeDocumentosCSV grava os documentos do tipo informado em CSV separado por ponto e vírgula
+func writeDocumentosCSV(w io.Writer, tipo string, documentos []ResultadoDocumento, opts ExportOptions) error {
+	cw := &CSVWriter{DecimalComma: opts.DecimalComma}
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+
+	header := darfHeader
+	if tipo == extraction.TipoGNRE {
+		header = gnreHeader
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, documento := range documentos {
+		pagina := ""
+		if documento.Proveniencia.Pagina > 0 {
+			pagina = strconv.Itoa(documento.Proveniencia.Pagina)
+		}
+		line := []string{documento.Arquivo, pagina, documento.Proveniencia.HashSHA256}
+
+		switch {
+		case tipo == extraction.TipoDARF && documento.Darf != nil:
+			darf := documento.Darf
+			line = append(line, darf.PeriodoApuracao.Format("02/01/2006"), darf.CNPJ, darf.CodigoReceita, darf.NumeroReferencia,
+				formatData(darf.Vencimento), cw.formatDecimal(darf.ValorPrincipal), cw.formatDecimal(darf.ValorMulta),
+				cw.formatDecimal(darf.ValorJuros), cw.formatDecimal(darf.ValorTotal))
+		case tipo == extraction.TipoGNRE && documento.Gnre != nil:
+			gnre := documento.Gnre
+			referencia := ""
+			if gnre.Referencia != nil {
+				referencia = gnre.Referencia.String()
+			}
+			line = append(line, gnre.UFFavorecida, gnre.CodigoReceita, gnre.CNPJ, gnre.DocumentoOrigem, referencia,
+				formatData(gnre.Vencimento), cw.formatDecimal(gnre.ValorPrincipal), cw.formatDecimal(gnre.ValorTotal), gnre.NumeroControle)
+		default:
+			continue
+		}
+
+		if err := writer.Write(line); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteDocumentos grava DARFs.csv e GNREs.csv com os documentos de cada tipo,
+// retornando os arquivos gerados (nenhum quando não há DARF nem GNRE)
+func WriteDocumentos(dir string, documentos []ResultadoDocumento, opts ExportOptions) ([]string, error) {
+	porTipo := map[string][]ResultadoDocumento{}
+	for _, documento := range documentos {
+		porTipo[documento.Tipo] = append(porTipo[documento.Tipo], documento)
+	}
+
+	gerados := []string{}
+	for _, tipo := range []string{extraction.TipoDARF, extraction.TipoGNRE} {
+		if len(porTipo[tipo]) == 0 {
+			continue
+		}
+		filename := DarfFile
+		if tipo == extraction.TipoGNRE {
+			filename = GnreFile
+		}
+
+		file, err := os.Create(filepath.Join(dir, filename))
+		if err != nil {
+			return gerados, fmt.Errorf("erro ao criar %s: %v", filename, err)
+		}
+		err = writeDocumentosCSV(file, tipo, porTipo[tipo], opts)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return gerados, fmt.Errorf("erro ao gravar %s: %v", filename, err)
+		}
+
+		logrus.Infof("📤 Exportação gerada: %s (%d %s)", filename, len(porTipo[tipo]), tipo)
+		gerados = append(gerados, filename)
+	}
+	return gerados, nil
+}
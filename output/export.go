@@ -0,0 +1,241 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/overrides"
+	"gerador-query-darm-go/validation"
+)
+
+// Status dos registros exportados
+const (
+	StatusValido = "valido"
+	StatusErro   = "erro"
+)
+
+// ExportRecord é um registro exportado: dados extraídos, status e proveniência
+type ExportRecord struct {
+	Arquivo    string     `json:"arquivo"`
+	Pagina     int        `json:"pagina,omitempty"`
+	HashSHA256 string     `json:"hashSha256,omitempty"`
+	ExtraidoEm *time.Time `json:"extraidoEm,omitempty"`
+	Status     string     `json:"status"`
+	Erro       string     `json:"erro,omitempty"`
+	// Avisos lista os valores padrão aplicados na linha (modo lenient)
+	Avisos []validation.Substituicao `json:"avisos,omitempty"`
+	// Correcoes lista os campos corrigidos manualmente (arquivo de correções)
+	Correcoes []overrides.Correcao `json:"correcoes,omitempty"`
+	// Dados são os textos extraídos; Registro é o DARM convertido e validado
+	Dados    *extraction.DarmData   `json:"dados,omitempty"`
+	Registro *validation.DarmRecord `json:"registro,omitempty"`
+}
+
+// OutputWriter grava registros exportados em um formato específico
+type OutputWriter interface {
+	// Extension retorna a extensão do arquivo gerado (sem ponto)
+	Extension() string
+	// Write grava os registros no writer
+	Write(w io.Writer, records []ExportRecord) error
+}
+
+// ExportOptions controla os formatos de exportação
+type ExportOptions struct {
+	// DecimalComma usa vírgula como separador decimal no CSV
+	DecimalComma bool
+}
+
+// NewOutputWriter cria o writer de um formato: json, jsonl ou csv
+func NewOutputWriter(format string, opts ExportOptions) (OutputWriter, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "json":
+		return &JSONWriter{}, nil
+	case "jsonl":
+		return &JSONLWriter{}, nil
+	case "csv":
+		return &CSVWriter{DecimalComma: opts.DecimalComma}, nil
+	}
+	return nil, fmt.Errorf("formato de exportação inválido: %q (use json, jsonl ou csv)", format)
+}
+
+// JSONWriter grava os registros como um array JSON
+type JSONWriter struct{}
+
+// Extension retorna a extensão do arquivo JSON
+func (jw *JSONWriter) Extension() string { return "json" }
+
+// Write grava os registros como um array JSON indentado
+func (jw *JSONWriter) Write(w io.Writer, records []ExportRecord) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// JSONLWriter grava um objeto JSON por linha (JSON Lines)
+type JSONLWriter struct{}
+
+// Extension retorna a extensão do arquivo JSON Lines
+func (jw *JSONLWriter) Extension() string { return "jsonl" }
+
+// Write grava um registro JSON por linha
+func (jw *JSONLWriter) Write(w io.Writer, records []ExportRecord) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// csvHeader lista as colunas do CSV exportado
+var csvHeader = []string{
+	"arquivo", "pagina", "hash_sha256", "extraido_em", "status", "erro", "avisos",
+	"inscricao", "codigo_barras", "codigo_receita", "valor_principal", "valor_total",
+	"data_vencimento", "exercicio", "numero_guia", "competencia",
+	"pix_copia_e_cola", "pix_txid", "correcoes",
+}
+
+// CSVWriter grava os registros em CSV separado por ponto e vírgula
+type CSVWriter struct {
+	DecimalComma bool
+}
+
+// Extension retorna a extensão do arquivo CSV
+func (cw *CSVWriter) Extension() string { return "csv" }
+
+// Write grava o cabeçalho e um registro por linha
+func (cw *CSVWriter) Write(w io.Writer, records []ExportRecord) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		pagina := ""
+		if record.Pagina > 0 {
+			pagina = strconv.Itoa(record.Pagina)
+		}
+		extraidoEm := ""
+		if record.ExtraidoEm != nil {
+			extraidoEm = record.ExtraidoEm.Format("02/01/2006 15:04:05")
+		}
+
+		line := []string{record.Arquivo, pagina, record.HashSHA256, extraidoEm, record.Status, record.Erro, validation.DescribeSubstituicoes(record.Avisos)}
+		if data, registro := record.Dados, record.Registro; data != nil && registro != nil {
+			line = append(line,
+				data.Inscricao,
+				data.CodigoBarras,
+				data.CodigoReceita,
+				cw.formatDecimal(registro.ValorPrincipal),
+				cw.formatDecimal(registro.ValorTotal),
+				data.DataVencimento,
+				data.Exercicio,
+				data.NumeroGuia,
+				data.Competencia,
+				data.PixCopiaECola,
+				pixTxID(registro),
+			)
+		} else {
+			line = append(line, make([]string, len(csvHeader)-len(line)-1)...)
+		}
+		line = append(line, overrides.DescribeCorrecoes(record.Correcoes))
+
+		if err := writer.Write(line); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// pixTxID retorna o txid do Pix conferido na validação, se houver
+func pixTxID(registro *validation.DarmRecord) string {
+	if registro.Pix == nil {
+		return ""
+	}
+	return registro.Pix.TxID
+}
+
+// formatDecimal aplica o separador decimal configurado
+func (cw *CSVWriter) formatDecimal(value validation.Money) string {
+	if cw.DecimalComma {
+		return strings.Replace(value.Decimal(), ".", ",", 1)
+	}
+	return value.Decimal()
+}
+
+// ExportRecords monta os registros de exportação dos resultados e falhas, ordenados por arquivo
+func ExportRecords(resultados []ResultadoDarm, falhas []FalhaProcessamento) []ExportRecord {
+	records := make([]ExportRecord, 0, len(resultados)+len(falhas))
+
+	for _, resultado := range resultados {
+		extraidoEm := resultado.Proveniencia.ExtraidoEm
+		records = append(records, ExportRecord{
+			Arquivo:    resultado.Arquivo,
+			Pagina:     resultado.Proveniencia.Pagina,
+			HashSHA256: resultado.Proveniencia.HashSHA256,
+			ExtraidoEm: &extraidoEm,
+			Status:     StatusValido,
+			Avisos:     resultado.Registro.Substituicoes,
+			Correcoes:  resultado.Proveniencia.Correcoes,
+			Dados:      resultado.Dados,
+			Registro:   resultado.Registro,
+		})
+	}
+
+	for _, falha := range falhas {
+		records = append(records, ExportRecord{
+			Arquivo: falha.Arquivo,
+			Status:  StatusErro,
+			Erro:    falha.Erro,
+		})
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Arquivo < records[j].Arquivo
+	})
+	return records
+}
+
+// WriteExports grava no diretório os arquivos DARMs.<formato> dos formatos informados
+func WriteExports(dir string, formats []string, opts ExportOptions, records []ExportRecord) error {
+	for _, format := range formats {
+		writer, err := NewOutputWriter(format, opts)
+		if err != nil {
+			return err
+		}
+
+		filename := "DARMs." + writer.Extension()
+		file, err := os.Create(filepath.Join(dir, filename))
+		if err != nil {
+			return fmt.Errorf("erro ao criar %s: %v", filename, err)
+		}
+
+		err = writer.Write(file, records)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao gravar %s: %v", filename, err)
+		}
+
+		logrus.Infof("📤 Exportação gerada: %s (%d registros)", filename, len(records))
+	}
+
+	return nil
+}
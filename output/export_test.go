@@ -0,0 +1,118 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/validation"
+)
+
+func testExportRecords() []ExportRecord {
+	extraidoEm := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	dados := &extraction.DarmData{
+		Inscricao:      "123;456",
+		CodigoReceita:  "2623",
+		ValorPrincipal: "1.234,56",
+		ValorTotal:     "1.234,56",
+		DataVencimento: "15/12/2024",
+		Exercicio:      "2025",
+		NumeroGuia:     "1",
+	}
+	registro, _ := validation.Parse(dados, validation.Options{})
+	return []ExportRecord{
+		{
+			Arquivo:    "0001.pdf",
+			Pagina:     2,
+			HashSHA256: "abc123",
+			ExtraidoEm: &extraidoEm,
+			Status:     StatusValido,
+			Dados:      dados,
+			Registro:   registro,
+		},
+		{Arquivo: "0002.pdf", Status: StatusErro, Erro: "dados insuficientes extraídos do PDF"},
+	}
+}
+
+func TestJSONWriters(t *testing.T) {
+	records := testExportRecords()
+
+	var buf bytes.Buffer
+	if err := (&JSONWriter{}).Write(&buf, records); err != nil {
+		t.Fatalf("JSONWriter falhou: %v", err)
+	}
+	var decoded []ExportRecord
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("JSON inválido: %v", err)
+	}
+	if len(decoded) != 2 || decoded[0].HashSHA256 != "abc123" || decoded[0].Pagina != 2 || decoded[1].Status != StatusErro {
+		t.Errorf("registros JSON inesperados: %+v", decoded)
+	}
+	if strings.Contains(buf.String(), `"extraidoEm": "0001`) {
+		t.Errorf("falhas não deveriam ter data de extração:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := (&JSONLWriter{}).Write(&buf, records); err != nil {
+		t.Fatalf("JSONLWriter falhou: %v", err)
+	}
+	lines := 0
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var record ExportRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("linha %d inválida: %v", lines+1, err)
+		}
+		lines++
+	}
+	if lines != 2 {
+		t.Errorf("esperadas 2 linhas JSONL, obtidas %d", lines)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	records := testExportRecords()
+
+	tests := []struct {
+		decimalComma bool
+		valor        string
+	}{
+		{decimalComma: false, valor: "1234.56"},
+		{decimalComma: true, valor: "1234,56"},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := (&CSVWriter{DecimalComma: test.decimalComma}).Write(&buf, records); err != nil {
+			t.Fatalf("CSVWriter falhou: %v", err)
+		}
+
+		reader := csv.NewReader(&buf)
+		reader.Comma = ';'
+		lines, err := reader.ReadAll()
+		if err != nil {
+			t.Fatalf("CSV inválido: %v", err)
+		}
+		if len(lines) != 3 {
+			t.Fatalf("esperadas 3 linhas (cabeçalho + 2), obtidas %d", len(lines))
+		}
+
+		valido := map[string]string{}
+		erro := map[string]string{}
+		for i, column := range lines[0] {
+			valido[column] = lines[1][i]
+			erro[column] = lines[2][i]
+		}
+		if valido["valor_principal"] != test.valor || valido["inscricao"] != "123;456" || valido["pagina"] != "2" {
+			t.Errorf("decimal_comma=%v: linha válida inesperada: %v", test.decimalComma, valido)
+		}
+		if erro["status"] != StatusErro || erro["erro"] == "" || erro["inscricao"] != "" {
+			t.Errorf("linha com erro inesperada: %v", erro)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FileUtils contém utilitários para manipulação de arquivos
+type FileUtils struct{}
+
+// NewFileUtils cria nova instância de FileUtils
+func NewFileUtils() *FileUtils {
+	return &FileUtils{}
+}
+
+// EnsureDir cria diretório se não existir
+func (fu *FileUtils) EnsureDir(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return os.MkdirAll(path, 0755)
+	}
+	return nil
+}
+
+// FileExists verifica se arquivo existe
+func (fu *FileUtils) FileExists(path string) bool {
+	_, err := os.Stat(path)
+	return !os.IsNotExist(err)
+}
+
+// GetFileSize retorna tamanho do arquivo
+func (fu *FileUtils) GetFileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// CopyFile copia arquivo
+func (fu *FileUtils) CopyFile(src, dst string) error {
+	// Ler arquivo fonte
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	// Criar diretório de destino se não existir
+	dir := filepath.Dir(dst)
+	if err := fu.EnsureDir(dir); err != nil {
+		return err
+	}
+
+	// Escrever arquivo destino
+	return os.WriteFile(dst, data, 0644)
+}
@@ -0,0 +1,37 @@
+package output
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestFileUtils testa utilitários de arquivo
+func TestFileUtils(t *testing.T) {
+	fileUtils := NewFileUtils()
+	tempDir := t.TempDir()
+
+	// Test EnsureDir
+	dir := filepath.Join(tempDir, "testdir")
+	err := fileUtils.EnsureDir(dir)
+	if err != nil {
+		t.Errorf("EnsureDir falhou: %v", err)
+	}
+
+	// Test FileExists
+	if !fileUtils.FileExists(dir) {
+		t.Error("FileExists deveria retornar true para diretório existente")
+	}
+
+	if fileUtils.FileExists(filepath.Join(tempDir, "nonexistent")) {
+		t.Error("FileExists deveria retornar false para arquivo inexistente")
+	}
+
+	// Test GetFileSize
+	size, err := fileUtils.GetFileSize(dir)
+	if err != nil {
+		t.Errorf("GetFileSize falhou: %v", err)
+	}
+	if size < 0 {
+		t.Error("Tamanho do arquivo deveria ser >= 0")
+	}
+}
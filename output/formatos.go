@@ -0,0 +1,59 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FormatoRelatorio é um dos arquivos de um relatório: a extensão e a função
+// que gera o conteúdo
+type FormatoRelatorio struct {
+	Ext    string
+	Render func() ([]byte, error)
+}
+
+// MarkdownFormato grava o relatório já renderizado em Markdown (.md)
+func MarkdownFormato(markdown string) FormatoRelatorio {
+	return FormatoRelatorio{Ext: ".md", Render: func() ([]byte, error) { return []byte(markdown), nil }}
+}
+
+// JSONFormato grava os dados do relatório em JSON indentado (.json)
+func JSONFormato(dados interface{}) FormatoRelatorio {
+	return FormatoRelatorio{Ext: ".json", Render: func() ([]byte, error) {
+		content, err := json.MarshalIndent(dados, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(content, '\n'), nil
+	}}
+}
+
+// CSVFormato grava o relatório gerado por render em CSV (.csv)
+func CSVFormato(render func() ([]byte, error)) FormatoRelatorio {
+	return FormatoRelatorio{Ext: ".csv", Render: render}
+}
+
+// WriteFormatos grava base+ext em dir para cada formato. Todos os conteúdos
+// são gerados antes da gravação, para que uma falha não deixe o relatório
+// pela metade; descricao identifica o relatório nas mensagens de erro
+// (ex.: "relatório de conciliação").
+func WriteFormatos(dir, base, descricao string, formatos ...FormatoRelatorio) error {
+	contents := make([][]byte, len(formatos))
+	for i, formato := range formatos {
+		content, err := formato.Render()
+		if err != nil {
+			return fmt.Errorf("erro ao gerar %s %s: %v", descricao, strings.ToUpper(strings.TrimPrefix(formato.Ext, ".")), err)
+		}
+		contents[i] = content
+	}
+
+	for i, formato := range formatos {
+		if err := os.WriteFile(filepath.Join(dir, base+formato.Ext), contents[i], 0644); err != nil {
+			return fmt.Errorf("erro ao gerar %s: %v", descricao, err)
+		}
+	}
+	return nil
+}
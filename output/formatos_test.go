@@ -0,0 +1,34 @@
+package output
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFormatos(t *testing.T) {
+	dir := t.TempDir()
+	csv := CSVFormato(func() ([]byte, error) { return []byte("a;b\n"), nil })
+	if err := WriteFormatos(dir, "RELATORIO", "relatório de teste", MarkdownFormato("# Título\n"), JSONFormato(map[string]int{"total": 2}), csv); err != nil {
+		t.Fatalf("WriteFormatos falhou: %v", err)
+	}
+	for ext, want := range map[string]string{".md": "# Título\n", ".json": "{\n  \"total\": 2\n}\n", ".csv": "a;b\n"} {
+		content, err := os.ReadFile(filepath.Join(dir, "RELATORIO"+ext))
+		if err != nil || string(content) != want {
+			t.Errorf("RELATORIO%s = %q, %v; esperado %q", ext, content, err, want)
+		}
+	}
+
+	// Uma falha de geração não grava nenhum arquivo
+	dir = t.TempDir()
+	falha := CSVFormato(func() ([]byte, error) { return nil, errors.New("coluna inválida") })
+	err := WriteFormatos(dir, "RELATORIO", "relatório de teste", MarkdownFormato("# Título\n"), falha)
+	if err == nil || !strings.Contains(err.Error(), "relatório de teste CSV: coluna inválida") {
+		t.Errorf("erro inesperado: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("nenhum arquivo deveria ser gravado: %v", entries)
+	}
+}
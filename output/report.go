@@ -0,0 +1,553 @@
+package output
+
+import (
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/overrides"
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/validation"
+)
+
+// RelatorioBase é o nome dos arquivos do relatório (.md, .html e .json)
+const RelatorioBase = "RELATORIO_PROCESSAMENTO"
+
+// StatusNaoProcessado marca arquivos não processados por interrupção
+const StatusNaoProcessado = "nao_processado"
+
+// ArquivoRelatorio é a situação de um PDF na execução
+type ArquivoRelatorio struct {
+	Arquivo        string                    `json:"arquivo"`
+	Tipo           string                    `json:"tipo,omitempty"`
+	Status         string                    `json:"status"`
+	Guia           string                    `json:"guia,omitempty"`
+	Pagina         int                       `json:"pagina,omitempty"`
+	HashSHA256     string                    `json:"hashSha256,omitempty"`
+	Erro           string                    `json:"erro,omitempty"`
+	CamposAusentes []string                  `json:"camposAusentes,omitempty"`
+	Avisos         []validation.Substituicao `json:"avisos,omitempty"`
+	Correcoes      []overrides.Correcao      `json:"correcoes,omitempty"`
+	DuracaoMs      int64                     `json:"duracaoMs"`
+}
+
+// TotalRelatorio soma os valores de um grupo de guias
+type TotalRelatorio struct {
+	Chave     string           `json:"chave"`
+	Guias     int              `json:"guias"`
+	Principal validation.Money `json:"vlPrincipal"`
+	Pago      validation.Money `json:"vlPago"`
+}
+
+// DuplicataRelatorio lista os arquivos que compartilham uma guia ou um PDF idêntico
+type DuplicataRelatorio struct {
+	Chave    string   `json:"chave"`
+	Arquivos []string `json:"arquivos"`
+}
+
+// Relatorio é o resultado da execução, renderizado em Markdown, HTML e JSON
+type Relatorio struct {
+	GeradoEm        time.Time            `json:"geradoEm"`
+	DuracaoMs       int64                `json:"duracaoMs"`
+	Interrompido    bool                 `json:"interrompido"`
+	Dialeto         string               `json:"dialeto"`
+	Conflito        string               `json:"conflito"`
+	ModoValidacao   string               `json:"modoValidacao"`
+	ComAvisos       int                  `json:"comAvisos"`
+	Corrigidos      int                  `json:"corrigidosManualmente"`
+	Transacao       bool                 `json:"transacao"`
+	TamanhoLote     int                  `json:"tamanhoLote"`
+	Validos         int                  `json:"validos"`
+	ComErro         int                  `json:"comErro"`
+	NaoProcessados  int                  `json:"naoProcessados"`
+	GuiasUnicas     int                  `json:"guiasUnicas"`
+	PorTipo         map[string]int       `json:"porTipo"`
+	Arquivos        []ArquivoRelatorio   `json:"arquivos"`
+	CamposAusentes  map[string]int       `json:"camposAusentes"`
+	Total           TotalRelatorio       `json:"total"`
+	PorReceita      []TotalRelatorio     `json:"porReceita"`
+	PorVencimento   []TotalRelatorio     `json:"porVencimento"`
+	GuiasDuplicadas []DuplicataRelatorio `json:"guiasDuplicadas"`
+	PDFsDuplicados  []DuplicataRelatorio `json:"pdfsDuplicados"`
+	ArquivosGerados []string             `json:"arquivosGerados"`
+}
+
+// camposDarm associa os campos de DarmData (nomes do JSON) aos valores
+func camposDarm(d *extraction.DarmData) [][2]string {
+	return [][2]string{
+		{"inscricao", d.Inscricao},
+		{"codigoBarras", d.CodigoBarras},
+		{"codigoReceita", d.CodigoReceita},
+		{"valorPrincipal", d.ValorPrincipal},
+		{"valorTotal", d.ValorTotal},
+		{"dataVencimento", d.DataVencimento},
+		{"exercicio", d.Exercicio},
+		{"numeroGuia", d.NumeroGuia},
+		{"competencia", d.Competencia},
+		{"pixCopiaECola", d.PixCopiaECola},
+	}
+}
+
+// addTotal acumula uma guia no grupo da chave
+func addTotal(groups map[string]*TotalRelatorio, key string, principal, pago validation.Money) {
+	total, ok := groups[key]
+	if !ok {
+		total = &TotalRelatorio{Chave: key}
+		groups[key] = total
+	}
+	total.Guias++
+	total.Principal = total.Principal.Add(principal)
+	total.Pago = total.Pago.Add(pago)
+}
+
+// sortedTotals retorna os grupos ordenados pela chave
+func sortedTotals(groups map[string]*TotalRelatorio) []TotalRelatorio {
+	totals := make([]TotalRelatorio, 0, len(groups))
+	for _, total := range groups {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Chave < totals[j].Chave })
+	return totals
+}
+
+// duplicates retorna as chaves associadas a mais de um arquivo, ordenadas
+func duplicates(arquivosPorChave map[string][]string) []DuplicataRelatorio {
+	result := []DuplicataRelatorio{}
+	for key, arquivos := range arquivosPorChave {
+		if len(arquivos) > 1 {
+			sort.Strings(arquivos)
+			result = append(result, DuplicataRelatorio{Chave: key, Arquivos: arquivos})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Chave < result[j].Chave })
+	return result
+}
+
+// Execucao reúne os dados de uma execução usados no relatório
+type Execucao struct {
+	Resultados []ResultadoDarm
+	// Documentos são os DARFs e GNREs, exportados à parte dos DARMs
+	Documentos []ResultadoDocumento
+	Falhas     []FalhaProcessamento
+	// Arquivos são os PDFs da execução; os que não têm resultado nem falha
+	// aparecem como não processados
+	Arquivos        []string
+	Inicio          time.Time
+	Script          sqlgen.ScriptOptions
+	ModoValidacao   string
+	ArquivosGerados []string
+}
+
+// BuildRelatorio monta o relatório a partir dos resultados e falhas da execução
+func BuildRelatorio(execucao Execucao) *Relatorio {
+	opts := execucao.Script
+	relatorio := &Relatorio{
+		GeradoEm:        time.Now(),
+		DuracaoMs:       time.Since(execucao.Inicio).Milliseconds(),
+		Dialeto:         opts.Insert.EffectiveDialect().Name(),
+		Conflito:        opts.Insert.Conflict.Description(),
+		ModoValidacao:   execucao.ModoValidacao,
+		Transacao:       opts.UseTransaction,
+		TamanhoLote:     opts.EffectiveBatchSize(),
+		CamposAusentes:  map[string]int{},
+		PorTipo:         map[string]int{},
+		Total:           TotalRelatorio{Chave: "total"},
+		GuiasDuplicadas: []DuplicataRelatorio{},
+		PDFsDuplicados:  []DuplicataRelatorio{},
+	}
+
+	arquivos := map[string]ArquivoRelatorio{}
+	porReceita := map[string]*TotalRelatorio{}
+	porVencimento := map[string]*TotalRelatorio{}
+	arquivosPorGuia := map[string][]string{}
+	arquivosPorHash := map[string][]string{}
+
+	for _, resultado := range execucao.Resultados {
+		registro := resultado.Registro
+		arquivo := ArquivoRelatorio{
+			Arquivo:    resultado.Arquivo,
+			Tipo:       extraction.TipoDARM,
+			Status:     StatusValido,
+			Guia:       fmt.Sprintf("%d", registro.Guia),
+			Pagina:     resultado.Proveniencia.Pagina,
+			HashSHA256: resultado.Proveniencia.HashSHA256,
+			Avisos:     registro.Substituicoes,
+			Correcoes:  resultado.Proveniencia.Correcoes,
+			DuracaoMs:  resultado.Duracao.Milliseconds(),
+		}
+		for _, campo := range camposDarm(resultado.Dados) {
+			if strings.TrimSpace(campo[1]) == "" {
+				arquivo.CamposAusentes = append(arquivo.CamposAusentes, campo[0])
+				relatorio.CamposAusentes[campo[0]]++
+			}
+		}
+		if len(arquivo.Avisos) > 0 {
+			relatorio.ComAvisos++
+		}
+		if resultado.Proveniencia.CorrigidoManualmente() {
+			relatorio.Corrigidos++
+		}
+		arquivos[resultado.Arquivo] = arquivo
+		relatorio.PorTipo[extraction.TipoDARM]++
+
+		principal, pago := registro.ValorPrincipal, registro.ValorTotal
+		vencimento := "sem vencimento"
+		if registro.Vencimento != nil {
+			vencimento = registro.Vencimento.Format("2006-01-02")
+		}
+		addTotal(porReceita, fmt.Sprintf("%d", registro.Receita.Numero()), principal, pago)
+		addTotal(porVencimento, vencimento, principal, pago)
+		relatorio.Total.Guias++
+		relatorio.Total.Principal = relatorio.Total.Principal.Add(principal)
+		relatorio.Total.Pago = relatorio.Total.Pago.Add(pago)
+
+		arquivosPorGuia[arquivo.Guia] = append(arquivosPorGuia[arquivo.Guia], resultado.Arquivo)
+		if arquivo.HashSHA256 != "" {
+			arquivosPorHash[arquivo.HashSHA256] = append(arquivosPorHash[arquivo.HashSHA256], resultado.Arquivo)
+		}
+	}
+
+	for _, documento := range execucao.Documentos {
+		arquivos[documento.Arquivo] = ArquivoRelatorio{
+			Arquivo:    documento.Arquivo,
+			Tipo:       documento.Tipo,
+			Status:     StatusValido,
+			Pagina:     documento.Proveniencia.Pagina,
+			HashSHA256: documento.Proveniencia.HashSHA256,
+			DuracaoMs:  documento.Duracao.Milliseconds(),
+		}
+		relatorio.PorTipo[documento.Tipo]++
+	}
+
+	for _, falha := range execucao.Falhas {
+		arquivos[falha.Arquivo] = ArquivoRelatorio{
+			Arquivo:   falha.Arquivo,
+			Status:    StatusErro,
+			Erro:      falha.Erro,
+			DuracaoMs: falha.Duracao.Milliseconds(),
+		}
+	}
+
+	for _, pdfFile := range execucao.Arquivos {
+		name := filepath.Base(pdfFile)
+		if _, ok := arquivos[name]; !ok {
+			arquivos[name] = ArquivoRelatorio{Arquivo: name, Status: StatusNaoProcessado}
+		}
+	}
+
+	for _, arquivo := range arquivos {
+		relatorio.Arquivos = append(relatorio.Arquivos, arquivo)
+		switch arquivo.Status {
+		case StatusValido:
+			relatorio.Validos++
+		case StatusErro:
+			relatorio.ComErro++
+		default:
+			relatorio.NaoProcessados++
+		}
+	}
+	sort.Slice(relatorio.Arquivos, func(i, j int) bool {
+		return relatorio.Arquivos[i].Arquivo < relatorio.Arquivos[j].Arquivo
+	})
+
+	relatorio.Interrompido = relatorio.NaoProcessados > 0
+	relatorio.GuiasUnicas = len(arquivosPorGuia)
+	relatorio.PorReceita = sortedTotals(porReceita)
+	relatorio.PorVencimento = sortedTotals(porVencimento)
+	relatorio.GuiasDuplicadas = duplicates(arquivosPorGuia)
+	relatorio.PDFsDuplicados = duplicates(arquivosPorHash)
+	relatorio.ArquivosGerados = execucao.ArquivosGerados
+	if relatorio.ArquivosGerados == nil {
+		relatorio.ArquivosGerados = []string{}
+	}
+
+	return relatorio
+}
+
+// formatDuracao formata milissegundos para leitura
+func formatDuracao(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+// formatVencimento exibe a chave de vencimento (AAAA-MM-DD) como DD/MM/AAAA
+func formatVencimento(key string) string {
+	if date, err := time.Parse("2006-01-02", key); err == nil {
+		return date.Format("02/01/2006")
+	}
+	return key
+}
+
+// statusLabel descreve o status do arquivo
+func statusLabel(status string) string {
+	switch status {
+	case StatusValido:
+		return "✅ válido"
+	case StatusErro:
+		return "❌ erro"
+	default:
+		return "⏸️ não processado"
+	}
+}
+
+// observacoes resume erro, campos ausentes, correções e avisos de um arquivo
+func (a ArquivoRelatorio) observacoes() string {
+	parts := []string{}
+	switch a.Tipo {
+	case extraction.TipoDARF:
+		parts = append(parts, "DARF exportado em "+DarfFile)
+	case extraction.TipoGNRE:
+		parts = append(parts, "GNRE exportada em "+GnreFile)
+	}
+	if a.Erro != "" {
+		parts = append(parts, a.Erro)
+	}
+	if len(a.CamposAusentes) > 0 {
+		parts = append(parts, "ausentes: "+strings.Join(a.CamposAusentes, ", "))
+	}
+	if len(a.Correcoes) > 0 {
+		parts = append(parts, "corrigido manualmente: "+overrides.DescribeCorrecoes(a.Correcoes))
+	}
+	if len(a.Avisos) > 0 {
+		parts = append(parts, "avisos: "+validation.DescribeSubstituicoes(a.Avisos))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// slowest retorna o arquivo mais demorado
+func (r *Relatorio) slowest() (ArquivoRelatorio, bool) {
+	var slowest ArquivoRelatorio
+	found := false
+	for _, arquivo := range r.Arquivos {
+		if arquivo.Status != StatusNaoProcessado && (!found || arquivo.DuracaoMs > slowest.DuracaoMs) {
+			slowest, found = arquivo, true
+		}
+	}
+	return slowest, found
+}
+
+// describePorTipo resume os documentos válidos por tipo (ex.: DARM 3, DARF 1)
+func (r *Relatorio) describePorTipo() string {
+	parts := []string{}
+	for _, tipo := range []string{extraction.TipoDARM, extraction.TipoDARF, extraction.TipoGNRE} {
+		if r.PorTipo[tipo] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", tipo, r.PorTipo[tipo]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sortedCampos retorna os campos ausentes ordenados pelo nome
+func (r *Relatorio) sortedCampos() []string {
+	campos := make([]string, 0, len(r.CamposAusentes))
+	for campo := range r.CamposAusentes {
+		campos = append(campos, campo)
+	}
+	sort.Strings(campos)
+	return campos
+}
+
+// shortHash abrevia o hash exibido no Markdown
+func shortHash(hash string) string {
+	if len(hash) <= 12 {
+		return hash
+	}
+	return hash[:12] + "..."
+}
+
+// escapeMarkdownCell impede que o conteúdo quebre a tabela Markdown
+func escapeMarkdownCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
+
+// RenderRelatorioMarkdown gera RELATORIO_PROCESSAMENTO.md
+func RenderRelatorioMarkdown(r *Relatorio) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# RELATÓRIO DE PROCESSAMENTO DE DARMs\n\n## Data/Hora: %s\n\n", r.GeradoEm.Format("02/01/2006 15:04:05"))
+	if r.Interrompido {
+		fmt.Fprintf(&b, "> ⚠️ **Processamento interrompido**: %d arquivo(s) não processado(s).\n\n", r.NaoProcessados)
+	}
+
+	b.WriteString("### Resumo:\n")
+	fmt.Fprintf(&b, "- Arquivos PDF: %d (✅ %d válidos, ❌ %d com erro, ⏸️ %d não processados)\n", len(r.Arquivos), r.Validos, r.ComErro, r.NaoProcessados)
+	fmt.Fprintf(&b, "- Guias únicas: %d\n", r.GuiasUnicas)
+	if tipos := r.describePorTipo(); tipos != "" {
+		fmt.Fprintf(&b, "- Documentos por tipo: %s\n", tipos)
+	}
+	fmt.Fprintf(&b, "- Duração total: %s\n", formatDuracao(r.DuracaoMs))
+	if slowest, ok := r.slowest(); ok {
+		fmt.Fprintf(&b, "- Arquivo mais demorado: %s (%s)\n", slowest.Arquivo, formatDuracao(slowest.DuracaoMs))
+	}
+	fmt.Fprintf(&b, "- Dialeto SQL: %s\n", r.Dialeto)
+	fmt.Fprintf(&b, "- Duplicatas no banco: %s\n", r.Conflito)
+	fmt.Fprintf(&b, "- Validação: %s (%d arquivo(s) com valores padrão)\n", r.ModoValidacao, r.ComAvisos)
+	fmt.Fprintf(&b, "- Corrigidos manualmente: %d arquivo(s)\n", r.Corrigidos)
+	if r.Transacao {
+		b.WriteString("- Script único em transação: sim\n")
+	} else {
+		b.WriteString("- Script único em transação: não\n")
+	}
+	if r.TamanhoLote > 0 {
+		fmt.Fprintf(&b, "- Registros por INSERT: até %d\n", r.TamanhoLote)
+	} else {
+		b.WriteString("- Registros por INSERT: todos em um único INSERT\n")
+	}
+
+	b.WriteString("\n### Arquivos:\n| # | Arquivo | Status | Guia | Tempo | Observações |\n|---|---------|--------|------|-------|-------------|\n")
+	for i, a := range r.Arquivos {
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | %s | %s |\n", i+1, escapeMarkdownCell(a.Arquivo), statusLabel(a.Status), a.Guia, formatDuracao(a.DuracaoMs), escapeMarkdownCell(a.observacoes()))
+	}
+
+	b.WriteString("\n### Campos Ausentes:\n")
+	if len(r.CamposAusentes) == 0 {
+		b.WriteString("- Nenhum campo ausente nos arquivos válidos\n")
+	}
+	for _, campo := range r.sortedCampos() {
+		fmt.Fprintf(&b, "- %s: %d arquivo(s)\n", campo, r.CamposAusentes[campo])
+	}
+
+	writeTotals := func(title, label string, totals []TotalRelatorio, formatKey func(string) string) {
+		fmt.Fprintf(&b, "\n### %s:\n| %s | Guias | VL_PRINCIPAL | VL_PAGO |\n|---|---|---|---|\n", title, label)
+		for _, total := range totals {
+			fmt.Fprintf(&b, "| %s | %d | %s | %s |\n", formatKey(total.Chave), total.Guias, total.Principal, total.Pago)
+		}
+		fmt.Fprintf(&b, "| **Total** | **%d** | **%s** | **%s** |\n", r.Total.Guias, r.Total.Principal, r.Total.Pago)
+	}
+	writeTotals("Totais por Receita", "Receita", r.PorReceita, func(key string) string { return key })
+	writeTotals("Totais por Vencimento", "Vencimento", r.PorVencimento, formatVencimento)
+
+	b.WriteString("\n### Duplicatas:\n")
+	if len(r.GuiasDuplicadas) == 0 && len(r.PDFsDuplicados) == 0 {
+		b.WriteString("- Nenhuma duplicata encontrada\n")
+	}
+	for _, dup := range r.GuiasDuplicadas {
+		fmt.Fprintf(&b, "- Guia %s em: %s\n", dup.Chave, strings.Join(dup.Arquivos, ", "))
+	}
+	for _, dup := range r.PDFsDuplicados {
+		fmt.Fprintf(&b, "- PDF idêntico (SHA-256 %s): %s\n", shortHash(dup.Chave), strings.Join(dup.Arquivos, ", "))
+	}
+
+	b.WriteString("\n### Arquivos Gerados:\n")
+	for _, name := range r.ArquivosGerados {
+		fmt.Fprintf(&b, "- **%s**\n", name)
+	}
+	fmt.Fprintf(&b, "- **%s.md / .html / .json** - Este relatório\n", RelatorioBase)
+
+	if r.Total.Guias > 0 {
+		b.WriteString(`
+### Próximos Passos:
+1. Execute **CHECK_GUIAS.sql** para verificar quais guias já existem no banco
+2. Execute **INSERT_TODOS_DARMs.sql** e confira total_antes/total_depois
+3. Em caso de problema, execute **ROLLBACK_TODOS_DARMs.sql**
+`)
+	}
+	if r.ComErro > 0 {
+		b.WriteString("\n⚠️ Corrija ou reprocesse os arquivos com erro listados acima.\n")
+	}
+
+	b.WriteString("\n---\nGerado automaticamente pelo DarmProcessor (Go)\n")
+	return b.String()
+}
+
+// relatorioHTMLTemplate é o relatório em HTML autocontido
+var relatorioHTMLTemplate = template.Must(template.New("relatorio").Funcs(template.FuncMap{
+	"data":       func(t time.Time) string { return t.Format("02/01/2006 15:04:05") },
+	"duracao":    formatDuracao,
+	"status":     statusLabel,
+	"vencimento": formatVencimento,
+}).Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>Relatório de Processamento de DARMs</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f0f0f0; }
+td.valor { text-align: right; }
+.erro { color: #b00020; }
+.aviso { background: #fff3cd; padding: 0.5em 1em; }
+</style>
+</head>
+<body>
+<h1>Relatório de Processamento de DARMs</h1>
+<p>Data/Hora: {{data .GeradoEm}} &middot; Duração: {{duracao .DuracaoMs}} &middot; Dialeto: {{.Dialeto}} &middot; Validação: {{.ModoValidacao}}</p>
+{{if .Interrompido}}<p class="aviso">Processamento interrompido: {{.NaoProcessados}} arquivo(s) não processado(s).</p>{{end}}
+<p>{{len .Arquivos}} PDF(s): {{.Validos}} válido(s), {{.ComErro}} com erro, {{.NaoProcessados}} não processado(s). Guias únicas: {{.GuiasUnicas}}.{{with .DescribePorTipo}} Por tipo: {{.}}.{{end}} Com valores padrão: {{.ComAvisos}}. Corrigidos manualmente: {{.Corrigidos}}.</p>
+<h2>Arquivos</h2>
+<table>
+<tr><th>Arquivo</th><th>Status</th><th>Guia</th><th>Tempo</th><th>Observações</th></tr>
+{{range .Arquivos}}<tr><td>{{.Arquivo}}</td><td>{{status .Status}}</td><td>{{.Guia}}</td><td>{{duracao .DuracaoMs}}</td><td{{if .Erro}} class="erro"{{end}}>{{.Observacoes}}</td></tr>
+{{end}}</table>
+<h2>Totais por Receita</h2>
+<table>
+<tr><th>Receita</th><th>Guias</th><th>VL_PRINCIPAL</th><th>VL_PAGO</th></tr>
+{{range .PorReceita}}<tr><td>{{.Chave}}</td><td>{{.Guias}}</td><td class="valor">{{.Principal}}</td><td class="valor">{{.Pago}}</td></tr>
+{{end}}<tr><th>Total</th><th>{{.Total.Guias}}</th><th class="valor">{{.Total.Principal}}</th><th class="valor">{{.Total.Pago}}</th></tr>
+</table>
+<h2>Totais por Vencimento</h2>
+<table>
+<tr><th>Vencimento</th><th>Guias</th><th>VL_PRINCIPAL</th><th>VL_PAGO</th></tr>
+{{range .PorVencimento}}<tr><td>{{vencimento .Chave}}</td><td>{{.Guias}}</td><td class="valor">{{.Principal}}</td><td class="valor">{{.Pago}}</td></tr>
+{{end}}<tr><th>Total</th><th>{{.Total.Guias}}</th><th class="valor">{{.Total.Principal}}</th><th class="valor">{{.Total.Pago}}</th></tr>
+</table>
+<h2>Duplicatas</h2>
+<ul>
+{{range .GuiasDuplicadas}}<li>Guia {{.Chave}}: {{range $i, $a := .Arquivos}}{{if $i}}, {{end}}{{$a}}{{end}}</li>
+{{end}}{{range .PDFsDuplicados}}<li>PDF idêntico ({{.Chave}}): {{range $i, $a := .Arquivos}}{{if $i}}, {{end}}{{$a}}{{end}}</li>
+{{end}}{{if and (not .GuiasDuplicadas) (not .PDFsDuplicados)}}<li>Nenhuma duplicata encontrada</li>
+{{end}}</ul>
+<h2>Arquivos Gerados</h2>
+<ul>
+{{range .ArquivosGerados}}<li>{{.}}</li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+// relatorioHTMLArquivo expõe as observações ao template HTML
+type relatorioHTMLArquivo struct {
+	ArquivoRelatorio
+	Observacoes string
+}
+
+// RenderRelatorioHTML gera RELATORIO_PROCESSAMENTO.html
+func RenderRelatorioHTML(r *Relatorio) (string, error) {
+	arquivos := make([]relatorioHTMLArquivo, 0, len(r.Arquivos))
+	for _, arquivo := range r.Arquivos {
+		arquivos = append(arquivos, relatorioHTMLArquivo{ArquivoRelatorio: arquivo, Observacoes: arquivo.observacoes()})
+	}
+
+	data := struct {
+		*Relatorio
+		Arquivos        []relatorioHTMLArquivo
+		DescribePorTipo string
+	}{r, arquivos, r.describePorTipo()}
+
+	var b strings.Builder
+	if err := relatorioHTMLTemplate.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// WriteRelatorio grava o relatório no diretório em Markdown, HTML e JSON
+func WriteRelatorio(dir string, relatorio *Relatorio) error {
+	html := FormatoRelatorio{Ext: ".html", Render: func() ([]byte, error) {
+		content, err := RenderRelatorioHTML(relatorio)
+		return []byte(content), err
+	}}
+	if err := WriteFormatos(dir, RelatorioBase, "relatório", MarkdownFormato(RenderRelatorioMarkdown(relatorio)), html, JSONFormato(relatorio)); err != nil {
+		return err
+	}
+
+	logrus.Infof("📋 Relatório gerado: %s.md/.html/.json (%d válidos, %d com erro)", RelatorioBase, relatorio.Validos, relatorio.ComErro)
+	return nil
+}
@@ -0,0 +1,48 @@
+// Package output grava as saídas de uma execução além dos scripts SQL: as
+// exportações dos dados extraídos (JSON, JSONL, CSV) e o relatório de
+// processamento (Markdown, HTML, JSON).
+package output
+
+import (
+	"time"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/overrides"
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/validation"
+)
+
+// FalhaProcessamento registra um arquivo que não pôde ser processado
+type FalhaProcessamento struct {
+	Arquivo  string
+	Erro     string
+	Duracao  time.Duration
+	Extracao *extraction.Extracao // Texto e dados extraídos antes da falha (nil se o PDF não pôde ser lido)
+}
+
+// Proveniencia registra a origem dos dados de um resultado
+type Proveniencia struct {
+	Arquivo    string    `json:"arquivo"`
+	Pagina     int       `json:"pagina,omitempty"`
+	HashSHA256 string    `json:"hashSha256,omitempty"`
+	ExtraidoEm time.Time `json:"extraidoEm"`
+	// Correcoes lista os campos corrigidos manualmente pelo arquivo de correções
+	Correcoes []overrides.Correcao `json:"correcoes,omitempty"`
+}
+
+// CorrigidoManualmente indica se algum campo veio do arquivo de correções
+func (p Proveniencia) CorrigidoManualmente() bool {
+	return len(p.Correcoes) > 0
+}
+
+// ResultadoDarm associa os dados extraídos e o INSERT gerado ao arquivo de origem
+type ResultadoDarm struct {
+	Arquivo      string
+	Dados        *extraction.DarmData
+	Linha        sqlgen.DarmRow
+	Registro     *validation.DarmRecord // Dados convertidos e validados (Dados mantém o texto extraído)
+	SQL          string
+	Texto        string
+	Proveniencia Proveniencia
+	Duracao      time.Duration // Tempo de extração e geração do SQL
+}
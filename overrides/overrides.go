@@ -0,0 +1,292 @@
+// Package overrides lê o arquivo de correções manuais (CSV ou JSON) e aplica
+// aos dados extraídos de cada PDF, identificado pelo hash SHA-256 ou pelo nome
+// do arquivo, para que as correções sobrevivam ao reprocessamento.
+package overrides
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gerador-query-darm-go/extraction"
+)
+
+// hashRegex reconhece chaves que são hashes SHA-256 (em vez de nomes de arquivo)
+var hashRegex = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// campos associa os nomes dos campos (os mesmos do JSON de DarmData) aos
+// ponteiros correspondentes
+func campos(d *extraction.DarmData) map[string]*string {
+	return map[string]*string{
+		"inscricao":      &d.Inscricao,
+		"codigoBarras":   &d.CodigoBarras,
+		"codigoReceita":  &d.CodigoReceita,
+		"valorPrincipal": &d.ValorPrincipal,
+		"valorTotal":     &d.ValorTotal,
+		"dataVencimento": &d.DataVencimento,
+		"exercicio":      &d.Exercicio,
+		"numeroGuia":     &d.NumeroGuia,
+		"competencia":    &d.Competencia,
+		"pixCopiaECola":  &d.PixCopiaECola,
+	}
+}
+
+// Compare lista, em ordem alfabética de campo, as correções que levam os
+// dados anteriores aos atuais (correções feitas fora do arquivo, ex.: painel)
+func Compare(anterior, atual *extraction.DarmData, motivo string) []Correcao {
+	antes, depois := campos(anterior), campos(atual)
+	nomes := make([]string, 0, len(antes))
+	for campo := range antes {
+		nomes = append(nomes, campo)
+	}
+	sort.Strings(nomes)
+
+	correcoes := []Correcao{}
+	for _, campo := range nomes {
+		if *antes[campo] != *depois[campo] {
+			correcoes = append(correcoes, Correcao{Campo: campo, Anterior: *antes[campo], Valor: *depois[campo], Motivo: motivo})
+		}
+	}
+	return correcoes
+}
+
+// Override é a correção de um PDF: os campos substituídos e o motivo
+type Override struct {
+	// Chave é o hash SHA-256 do PDF ou o nome do arquivo
+	Chave  string            `json:"chave"`
+	Campos map[string]string `json:"campos"`
+	Motivo string            `json:"motivo,omitempty"`
+}
+
+// Correcao registra um campo corrigido manualmente, com o valor extraído
+type Correcao struct {
+	Campo    string `json:"campo"`
+	Anterior string `json:"anterior"`
+	Valor    string `json:"valor"`
+	Motivo   string `json:"motivo,omitempty"`
+}
+
+// String descreve a correção como "campo: anterior → valor"
+func (c Correcao) String() string {
+	return fmt.Sprintf("%s: %q → %q", c.Campo, c.Anterior, c.Valor)
+}
+
+// DescribeCorrecoes lista as correções separadas por vírgula
+func DescribeCorrecoes(correcoes []Correcao) string {
+	parts := make([]string, 0, len(correcoes))
+	for _, c := range correcoes {
+		parts = append(parts, c.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Set é o conjunto de correções de um arquivo, indexado por hash e por nome
+type Set struct {
+	porHash    map[string]Override
+	porArquivo map[string]Override
+
+	mu     sync.Mutex
+	usadas map[string]bool
+	chaves []string // Na ordem do arquivo
+}
+
+// New cria o conjunto de correções, validando chaves e campos
+func New(overrides []Override) (*Set, error) {
+	set := &Set{
+		porHash:    map[string]Override{},
+		porArquivo: map[string]Override{},
+		usadas:     map[string]bool{},
+	}
+	for i, o := range overrides {
+		o.Chave = strings.TrimSpace(o.Chave)
+		if o.Chave == "" {
+			return nil, fmt.Errorf("correção %d sem chave (hash ou nome do arquivo)", i+1)
+		}
+		for campo := range o.Campos {
+			if _, ok := campos(&extraction.DarmData{})[campo]; !ok {
+				return nil, fmt.Errorf("correção de %s: campo desconhecido %q", o.Chave, campo)
+			}
+		}
+
+		index := set.porArquivo
+		if hashRegex.MatchString(o.Chave) {
+			o.Chave = strings.ToLower(o.Chave)
+			index = set.porHash
+		}
+		if existing, ok := index[o.Chave]; ok {
+			// Linhas repetidas da mesma chave (CSV) somam os campos
+			for campo, valor := range o.Campos {
+				existing.Campos[campo] = valor
+			}
+			if o.Motivo != "" {
+				existing.Motivo = o.Motivo
+			}
+			index[o.Chave] = existing
+			continue
+		}
+		copia := map[string]string{}
+		for campo, valor := range o.Campos {
+			copia[campo] = valor
+		}
+		o.Campos = copia
+		index[o.Chave] = o
+		set.chaves = append(set.chaves, o.Chave)
+	}
+	return set, nil
+}
+
+// Load lê as correções de um arquivo .json ou .csv
+func Load(path string) (*Set, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de correções: %v", err)
+	}
+	defer file.Close()
+
+	var overrides []Override
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		overrides, err = ReadJSON(file)
+	case ".csv":
+		overrides, err = ReadCSV(file)
+	default:
+		return nil, fmt.Errorf("arquivo de correções deve ser .json ou .csv: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar %s: %v", filepath.Base(path), err)
+	}
+	return New(overrides)
+}
+
+// ReadJSON lê um array de correções:
+//
+//	[{"chave": "2025001229.pdf", "campos": {"valorTotal": "1.234,56"}, "motivo": "..."}]
+func ReadJSON(r io.Reader) ([]Override, error) {
+	var overrides []Override
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&overrides); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// ReadCSV lê correções separadas por ponto e vírgula, um campo por linha, com
+// cabeçalho chave;campo;valor[;motivo]
+func ReadCSV(r io.Reader) ([]Override, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	if len(header) < 3 || strings.TrimSpace(header[0]) != "chave" || strings.TrimSpace(header[1]) != "campo" || strings.TrimSpace(header[2]) != "valor" {
+		return nil, fmt.Errorf("cabeçalho esperado: chave;campo;valor;motivo")
+	}
+
+	overrides := []Override{}
+	for i, record := range records[1:] {
+		if len(record) < 3 {
+			return nil, fmt.Errorf("linha %d: esperadas ao menos 3 colunas", i+2)
+		}
+		o := Override{
+			Chave:  record[0],
+			Campos: map[string]string{strings.TrimSpace(record[1]): record[2]},
+		}
+		if len(record) > 3 {
+			o.Motivo = record[3]
+		}
+		overrides = append(overrides, o)
+	}
+	return overrides, nil
+}
+
+// Apply aplica a correção do PDF (pelo hash, ou pelo nome do arquivo) a uma
+// cópia dos dados extraídos. Sem correção, retorna os próprios dados; dados
+// ausentes (extração insuficiente) podem ser preenchidos integralmente.
+func (s *Set) Apply(arquivo, hash string, dados *extraction.DarmData) (*extraction.DarmData, []Correcao) {
+	if s == nil {
+		return dados, nil
+	}
+
+	o, ok := s.porHash[strings.ToLower(hash)]
+	if !ok {
+		if o, ok = s.porArquivo[arquivo]; !ok {
+			return dados, nil
+		}
+	}
+
+	s.mu.Lock()
+	s.usadas[o.Chave] = true
+	s.mu.Unlock()
+
+	corrigido := &extraction.DarmData{}
+	if dados != nil {
+		*corrigido = *dados
+	}
+
+	nomes := make([]string, 0, len(o.Campos))
+	for campo := range o.Campos {
+		nomes = append(nomes, campo)
+	}
+	sort.Strings(nomes)
+
+	correcoes := []Correcao{}
+	ptrs := campos(corrigido)
+	for _, campo := range nomes {
+		valor := strings.TrimSpace(o.Campos[campo])
+		if *ptrs[campo] == valor {
+			continue
+		}
+		correcoes = append(correcoes, Correcao{Campo: campo, Anterior: *ptrs[campo], Valor: valor, Motivo: o.Motivo})
+		*ptrs[campo] = valor
+		if campo == "numeroGuia" {
+			// O número completo lido do PDF deixa de valer para conferir o Pix
+			corrigido.NumeroGuiaCompleto = ""
+		}
+	}
+	if len(correcoes) == 0 {
+		return dados, nil
+	}
+	return corrigido, correcoes
+}
+
+// NaoAplicadas retorna as chaves que não corresponderam a nenhum PDF
+func (s *Set) NaoAplicadas() []string {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chaves := []string{}
+	for _, chave := range s.chaves {
+		if !s.usadas[chave] {
+			chaves = append(chaves, chave)
+		}
+	}
+	return chaves
+}
+
+// Len retorna o número de PDFs com correção
+func (s *Set) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.chaves)
+}
@@ -0,0 +1,97 @@
+package overrides
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/extraction"
+)
+
+const hash = "9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08"
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"correcoes.json": `[{"chave": "0001.pdf", "campos": {"valorTotal": "10,00"}, "motivo": "PDF borrado"},
+			{"chave": "` + hash + `", "campos": {"numeroGuia": "42"}}]`,
+		"correcoes.csv": "chave;campo;valor;motivo\n0001.pdf;valorTotal;10,00;PDF borrado\n" + hash + ";numeroGuia;42\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		set, err := Load(path)
+		if err != nil {
+			t.Fatalf("%s: Load falhou: %v", name, err)
+		}
+		if set.Len() != 2 {
+			t.Errorf("%s: esperadas 2 correções, obtidas %d", name, set.Len())
+		}
+
+		dados, correcoes := set.Apply("0001.pdf", "", &extraction.DarmData{ValorTotal: "1,00", NumeroGuia: "1"})
+		expected := []Correcao{{Campo: "valorTotal", Anterior: "1,00", Valor: "10,00", Motivo: "PDF borrado"}}
+		if dados.ValorTotal != "10,00" || dados.NumeroGuia != "1" || !reflect.DeepEqual(correcoes, expected) {
+			t.Errorf("%s: correção por nome inesperada: %+v %+v", name, dados, correcoes)
+		}
+
+		// O hash tem precedência sobre o nome do arquivo e aceita maiúsculas
+		dados, correcoes = set.Apply("0001.pdf", strings.ToLower(hash), nil)
+		if dados == nil || dados.NumeroGuia != "42" || dados.ValorTotal != "" || len(correcoes) != 1 {
+			t.Errorf("%s: correção por hash inesperada: %+v %+v", name, dados, correcoes)
+		}
+		if len(set.NaoAplicadas()) != 0 {
+			t.Errorf("%s: todas as correções deveriam ter sido aplicadas: %v", name, set.NaoAplicadas())
+		}
+	}
+
+	invalid := map[string]string{
+		"campo.json":     `[{"chave": "a.pdf", "campos": {"valor": "1"}}]`,
+		"chave.json":     `[{"chave": " ", "campos": {"valorTotal": "1"}}]`,
+		"extra.json":     `[{"chave": "a.pdf", "valores": {}}]`,
+		"cabecalho.csv":  "arquivo;campo;valor\na.pdf;valorTotal;1\n",
+		"colunas.csv":    "chave;campo;valor\na.pdf;valorTotal\n",
+		"correcoes.xlsx": "",
+	}
+	for name, content := range invalid {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("%s deveria ser rejeitado", name)
+		}
+	}
+	if _, err := Load(filepath.Join(dir, "inexistente.csv")); err == nil {
+		t.Error("arquivo inexistente deveria ser rejeitado")
+	}
+}
+
+func TestApplyWithoutMatch(t *testing.T) {
+	set, err := New([]Override{
+		{Chave: "a.pdf", Campos: map[string]string{"valorTotal": "1,00"}},
+		{Chave: "b.pdf", Campos: map[string]string{"exercicio": "2025"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := &extraction.DarmData{ValorTotal: "1,00"}
+	if dados, correcoes := set.Apply("a.pdf", "", original); dados != original || correcoes != nil {
+		t.Error("correção sem efeito deveria manter os dados extraídos")
+	}
+	if dados, correcoes := set.Apply("c.pdf", "", original); dados != original || correcoes != nil {
+		t.Error("PDF sem correção deveria manter os dados extraídos")
+	}
+	if got := set.NaoAplicadas(); !reflect.DeepEqual(got, []string{"b.pdf"}) {
+		t.Errorf("NaoAplicadas = %v, esperado [b.pdf]", got)
+	}
+
+	var empty *Set
+	if dados, _ := empty.Apply("a.pdf", "", original); dados != original || empty.Len() != 0 {
+		t.Error("conjunto nil não deveria alterar os dados")
+	}
+}
@@ -0,0 +1,262 @@
+// Package pix interpreta o BR Code do Pix ("copia e cola") impresso nos DARMs:
+// campos TLV do padrão EMV, conta do recebedor, txid, valor e CRC16-CCITT.
+package pix
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GUI identifica a conta Pix no campo de informações do recebedor
+const GUI = "br.gov.bcb.pix"
+
+// IDs dos campos do BR Code usados pelo processador
+const (
+	idPayloadFormat   = "00"
+	idInitiation      = "01"
+	idMerchantFirst   = 26
+	idMerchantLast    = 51
+	idMoeda           = "53"
+	idValor           = "54"
+	idPais            = "58"
+	idNome            = "59"
+	idCidade          = "60"
+	idCEP             = "61"
+	idAdicionais      = "62"
+	idCRC             = "63"
+	idContaGUI        = "00"
+	idContaChave      = "01"
+	idContaInfo       = "02"
+	idContaURL        = "25"
+	idAdicionaisTxID  = "05"
+	iniciacaoDinamica = "12"
+)
+
+// valorRegex valida o valor da transação (até 13 caracteres, ponto decimal)
+var valorRegex = regexp.MustCompile(`^\d{1,10}(?:\.\d{1,2})?$`)
+
+// lineBreaks remove as quebras de linha inseridas pela camada de texto do PDF
+var lineBreaks = strings.NewReplacer("\r", "", "\n", "")
+
+// Campo é um campo TLV (ID, tamanho e valor) do BR Code
+type Campo struct {
+	ID    string `json:"id"`
+	Valor string `json:"valor"`
+}
+
+// BRCode é o payload Pix interpretado
+type BRCode struct {
+	Payload string `json:"payload"`
+	// Dinamico indica QR Code dinâmico (URL com a cobrança em vez da chave)
+	Dinamico bool   `json:"dinamico"`
+	Chave    string `json:"chave,omitempty"`
+	URL      string `json:"url,omitempty"`
+	// InfoAdicional é a mensagem ao pagador da conta do recebedor
+	InfoAdicional string `json:"infoAdicional,omitempty"`
+	// Valor é o valor da transação como escrito no payload (vazio = valor livre)
+	Valor  string `json:"valor,omitempty"`
+	Nome   string `json:"nome"`
+	Cidade string `json:"cidade"`
+	CEP    string `json:"cep,omitempty"`
+	TxID   string `json:"txid,omitempty"`
+	CRC    string `json:"crc"`
+}
+
+// Centavos converte o valor da transação em centavos; ok é false sem valor
+func (b *BRCode) Centavos() (centavos int64, ok bool) {
+	if b.Valor == "" {
+		return 0, false
+	}
+	reais, fracao, _ := strings.Cut(b.Valor, ".")
+	fracao = (fracao + "00")[:2]
+	value, err := strconv.ParseInt(reais+fracao, 10, 64)
+	return value, err == nil
+}
+
+// CRC16 calcula o CRC16-CCITT (polinômio 0x1021, valor inicial 0xFFFF) usado no BR Code
+func CRC16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// ParseTLV divide um payload (ou um campo template) em campos ID + tamanho + valor
+func ParseTLV(payload string) ([]Campo, error) {
+	campos := []Campo{}
+	for pos := 0; pos < len(payload); {
+		if pos+4 > len(payload) {
+			return nil, fmt.Errorf("campo incompleto na posição %d", pos+1)
+		}
+		id := payload[pos : pos+2]
+		size, ok := fieldSize(payload[pos+2 : pos+4])
+		if !ok || !isDigits(id) {
+			return nil, fmt.Errorf("ID/tamanho inválido na posição %d: %q", pos+1, payload[pos:pos+4])
+		}
+		if size > len(payload)-pos-4 {
+			return nil, fmt.Errorf("campo %s com tamanho %d excede o payload", id, size)
+		}
+		campos = append(campos, Campo{ID: id, Valor: payload[pos+4 : pos+4+size]})
+		pos += 4 + size
+	}
+	return campos, nil
+}
+
+// fieldSize lê o tamanho de um campo TLV: exatamente dois dígitos ASCII,
+// recusando sinais e espaços que strconv.Atoi aceitaria
+func fieldSize(raw string) (int, bool) {
+	if len(raw) != 2 || !isDigits(raw) {
+		return 0, false
+	}
+	return int(raw[0]-'0')*10 + int(raw[1]-'0'), true
+}
+
+// isDigits indica se o texto contém apenas dígitos
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Parse interpreta e valida um BR Code Pix: formato 01, conta br.gov.bcb.pix,
+// moeda 986, país BR, valor e CRC16 conferido no campo 63 (o último)
+func Parse(payload string) (*BRCode, error) {
+	payload = strings.TrimSpace(payload)
+	campos, err := ParseTLV(payload)
+	if err != nil {
+		return nil, fmt.Errorf("BR Code inválido: %v", err)
+	}
+	if len(campos) == 0 || campos[0].ID != idPayloadFormat || campos[0].Valor != "01" {
+		return nil, fmt.Errorf("BR Code inválido: payload deve começar com o formato 01 (000201)")
+	}
+	last := campos[len(campos)-1]
+	if last.ID != idCRC || len(last.Valor) != 4 {
+		return nil, fmt.Errorf("BR Code inválido: CRC (campo 63) deve ser o último campo")
+	}
+	if expected := fmt.Sprintf("%04X", CRC16(payload[:len(payload)-4])); !strings.EqualFold(last.Valor, expected) {
+		return nil, fmt.Errorf("BR Code com CRC inválido: %s, calculado %s", last.Valor, expected)
+	}
+
+	code := &BRCode{Payload: payload, CRC: strings.ToUpper(last.Valor)}
+	conta := false
+	for _, campo := range campos {
+		switch campo.ID {
+		case idInitiation:
+			code.Dinamico = campo.Valor == iniciacaoDinamica
+		case idMoeda:
+			if campo.Valor != "986" {
+				return nil, fmt.Errorf("BR Code com moeda %q, esperado 986 (BRL)", campo.Valor)
+			}
+		case idValor:
+			if !valorRegex.MatchString(campo.Valor) {
+				return nil, fmt.Errorf("BR Code com valor inválido: %q", campo.Valor)
+			}
+			code.Valor = campo.Valor
+		case idPais:
+			if campo.Valor != "BR" {
+				return nil, fmt.Errorf("BR Code com país %q, esperado BR", campo.Valor)
+			}
+		case idNome:
+			code.Nome = campo.Valor
+		case idCidade:
+			code.Cidade = campo.Valor
+		case idCEP:
+			code.CEP = campo.Valor
+		case idAdicionais:
+			adicionais, err := ParseTLV(campo.Valor)
+			if err != nil {
+				return nil, fmt.Errorf("BR Code com dados adicionais (campo 62) inválidos: %v", err)
+			}
+			for _, adicional := range adicionais {
+				if adicional.ID == idAdicionaisTxID {
+					code.TxID = adicional.Valor
+				}
+			}
+		default:
+			id, _ := strconv.Atoi(campo.ID)
+			if id < idMerchantFirst || id > idMerchantLast {
+				continue
+			}
+			// Contas de outros arranjos (cartões) podem constar do mesmo QR Code
+			subcampos, err := ParseTLV(campo.Valor)
+			if err != nil || len(subcampos) == 0 || subcampos[0].ID != idContaGUI || !strings.EqualFold(subcampos[0].Valor, GUI) {
+				continue
+			}
+			conta = true
+			for _, subcampo := range subcampos[1:] {
+				switch subcampo.ID {
+				case idContaChave:
+					code.Chave = subcampo.Valor
+				case idContaInfo:
+					code.InfoAdicional = subcampo.Valor
+				case idContaURL:
+					code.URL = subcampo.Valor
+				}
+			}
+		}
+	}
+
+	if !conta {
+		return nil, fmt.Errorf("BR Code sem conta Pix (%s)", GUI)
+	}
+	if code.Chave == "" && code.URL == "" {
+		return nil, fmt.Errorf("BR Code sem chave Pix nem URL de cobrança")
+	}
+	return code, nil
+}
+
+// Find procura o BR Code Pix no texto do PDF. A camada de texto costuma
+// quebrar o payload em várias linhas: cada ocorrência de 000201 é lida campo
+// a campo até o CRC (63), também sem as quebras de linha, e só é aceita com
+// CRC válido. Retorna vazio quando não há BR Code.
+func Find(text string) string {
+	for start := strings.Index(text, "000201"); start >= 0; {
+		rest := text[start:]
+		for _, candidate := range []string{rest, lineBreaks.Replace(rest)} {
+			if payload := payloadPrefix(candidate); payload != "" {
+				if _, err := Parse(payload); err == nil {
+					return payload
+				}
+			}
+		}
+
+		next := strings.Index(text[start+1:], "000201")
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return ""
+}
+
+// payloadPrefix percorre os campos TLV do início do texto até o CRC (6304xxxx),
+// retornando o payload ou vazio se a estrutura for interrompida antes
+func payloadPrefix(text string) string {
+	for pos := 0; pos+4 <= len(text); {
+		size, ok := fieldSize(text[pos+2 : pos+4])
+		if !ok || !isDigits(text[pos:pos+2]) || size > len(text)-pos-4 {
+			return ""
+		}
+		if text[pos:pos+2] == idCRC {
+			if size != 4 {
+				return ""
+			}
+			return text[:pos+8]
+		}
+		pos += 4 + size
+	}
+	return ""
+}
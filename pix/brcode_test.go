@@ -0,0 +1,85 @@
+package pix
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// exemploBCB é o BR Code estático do Manual de Padrões para Iniciação do Pix
+const exemploBCB = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+// brCode monta um BR Code com os campos informados e o CRC correto
+func brCode(campos ...string) string {
+	payload := strings.Join(campos, "") + "6304"
+	return payload + fmt.Sprintf("%04X", CRC16(payload))
+}
+
+func TestCRC16(t *testing.T) {
+	if crc := CRC16(exemploBCB[:len(exemploBCB)-4]); crc != 0x1D3D {
+		t.Errorf("CRC16 = %04X, esperado 1D3D", crc)
+	}
+	// Valor de referência do CRC-16/CCITT-FALSE
+	if crc := CRC16("123456789"); crc != 0x29B1 {
+		t.Errorf("CRC16(123456789) = %04X, esperado 29B1", crc)
+	}
+}
+
+func TestParse(t *testing.T) {
+	code, err := Parse(exemploBCB)
+	if err != nil {
+		t.Fatalf("Parse falhou: %v", err)
+	}
+	if code.Chave != "123e4567-e12b-12d1-a456-426655440000" || code.Nome != "Fulano de Tal" || code.Cidade != "BRASILIA" || code.TxID != "***" || code.CRC != "1D3D" {
+		t.Errorf("BR Code inesperado: %+v", code)
+	}
+	if _, ok := code.Centavos(); ok || code.Dinamico {
+		t.Errorf("exemplo é estático e sem valor: %+v", code)
+	}
+
+	// Dinâmico com valor e conta de outro arranjo antes da conta Pix
+	dinamico := brCode("000201", "010212", "26170013outro.arranjo",
+		"2655"+"0014br.gov.bcb.pix"+"2533pix.example.com/qr/v2/cobv/9d36b8", "52040000", "5303986", "540410.5", "5802BR",
+		"5910PREFEITURA", "6014RIO DE JANEIRO", "62150511DARM0000042")
+	code, err = Parse(dinamico)
+	if err != nil {
+		t.Fatalf("Parse falhou: %v", err)
+	}
+	if centavos, ok := code.Centavos(); !ok || centavos != 1050 || !code.Dinamico || code.URL != "pix.example.com/qr/v2/cobv/9d36b8" || code.TxID != "DARM0000042" {
+		t.Errorf("BR Code dinâmico inesperado: %+v", code)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	invalid := map[string]string{
+		"CRC":               exemploBCB[:len(exemploBCB)-4] + "1D3E",
+		"truncado":          exemploBCB[:len(exemploBCB)-10],
+		"sem formato":       brCode(exemploBCB[6 : len(exemploBCB)-8]),
+		"CRC no meio":       exemploBCB + "5802BR",
+		"sem conta Pix":     brCode("000201", "26170013outro.arranjo", "5303986", "5802BR"),
+		"sem chave":         brCode("000201", "26180014br.gov.bcb.pix", "5303986", "5802BR"),
+		"moeda":             brCode("000201", "26250014br.gov.bcb.pix0103abc", "5303840", "5802BR"),
+		"valor com vírgula": brCode("000201", "26250014br.gov.bcb.pix0103abc", "5303986", "540410,5", "5802BR"),
+		"tamanho negativo":  "000201" + "01-1X" + "6304ABCD",
+		"tamanho com sinal": "000201" + "01+1X" + "6304ABCD",
+	}
+	for name, payload := range invalid {
+		if _, err := Parse(payload); err == nil {
+			t.Errorf("%s: deveria falhar", name)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	// A camada de texto quebra o payload e pode conter 000201 antes dele
+	text := "09. VALOR TOTAL R$ 1.234,56\nPix copia e cola:\n" + exemploBCB[:50] + "\n" + exemploBCB[50:100] + "\r\n" + exemploBCB[100:] + "\nAutenticação 0002010"
+	if found := Find("Guia 000201 " + text); found != exemploBCB {
+		t.Errorf("Find = %q, esperado o exemplo", found)
+	}
+	if found := Find("09. VALOR TOTAL R$ 1.234,56\n" + exemploBCB[:len(exemploBCB)-1] + "E"); found != "" {
+		t.Errorf("BR Code com CRC inválido não deveria ser aceito: %q", found)
+	}
+	if found := Find("000201" + "01-1X" + "6304ABCD"); found != "" {
+		t.Errorf("tamanho negativo não deveria ser aceito: %q", found)
+	}
+}
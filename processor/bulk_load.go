@@ -0,0 +1,36 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/sqlgen"
+)
+
+// generateBulkLoad grava LOAD_TODOS_DARMs.tsv e LOAD_TODOS_DARMs.sql com as
+// mesmas linhas (e SQ_DOC) do INSERT_TODOS_DARMs.sql
+func (dp *DarmProcessor) generateBulkLoad(rows []sqlgen.DarmRow) error {
+	opts, err := dp.Config.BulkLoadOptions()
+	if err != nil {
+		return err
+	}
+
+	data, script, err := sqlgen.RenderBulkLoad(rows, opts)
+	if err != nil {
+		return fmt.Errorf("erro ao montar carga em massa: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dp.OutputDir, sqlgen.BulkDataFile), data, 0644); err != nil {
+		return fmt.Errorf("erro ao gerar %s: %v", sqlgen.BulkDataFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(dp.OutputDir, sqlgen.BulkScriptFile), []byte(script), 0644); err != nil {
+		return fmt.Errorf("erro ao gerar %s: %v", sqlgen.BulkScriptFile, err)
+	}
+
+	logrus.Infof("🚚 Carga em massa gerada: %s + %s (%d registros, %s)", sqlgen.BulkScriptFile, sqlgen.BulkDataFile, len(rows), opts.Charset)
+	logrus.Infof("IMPORTANTE: Execute %s a partir da pasta %s com mysql --local-infile=1", sqlgen.BulkScriptFile, filepath.Base(dp.OutputDir))
+	return nil
+}
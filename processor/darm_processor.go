@@ -0,0 +1,617 @@
+// Package processor processa documentos de DARM (do diretório darms ou em
+// memória): extrai cada PDF em um pool de workers, gera os scripts SQL e grava
+// as exportações e o relatório.
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/config"
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/output"
+	"gerador-query-darm-go/overrides"
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/validation"
+)
+
+// DarmProcessor é o processador principal de DARMs
+type DarmProcessor struct {
+	BaseDir          string
+	DarmsDir         string
+	OutputDir        string
+	Config           *config.Config
+	ProcessedGuias   map[string]bool
+	Resultados       []output.ResultadoDarm
+	GuiasProcessadas []string // Derivado de Resultados, na mesma ordem
+	AllSQLInserts    []string // Derivado de Resultados, na mesma ordem
+	Falhas           []output.FalhaProcessamento
+	Documentos       []output.ResultadoDocumento // DARFs e GNREs, exportados em DARFs.csv e GNREs.csv
+	Relatorio        *output.Relatorio           // Relatório da última execução
+	Overrides        *overrides.Set              // Correções manuais (paths.overrides_file), carregadas em Init
+	mu               sync.RWMutex                // Mutex para thread safety
+
+	// extract extrai os dados de um documento (substituível em testes)
+	extract func(doc Documento) (*extraction.Extracao, error)
+}
+
+// NewDarmProcessor cria uma nova instância do processador
+func NewDarmProcessor() *DarmProcessor {
+	return NewDarmProcessorWithConfig(config.Default())
+}
+
+// NewDarmProcessorWithConfig cria o processador com a configuração e os
+// diretórios de cfg.Paths (relativos ao diretório base)
+func NewDarmProcessorWithConfig(cfg *config.Config) *DarmProcessor {
+	dp := &DarmProcessor{
+		BaseDir:          cfg.BaseDir(),
+		DarmsDir:         cfg.DarmsDir(),
+		OutputDir:        cfg.OutputDir(),
+		Config:           cfg,
+		ProcessedGuias:   make(map[string]bool),
+		Resultados:       []output.ResultadoDarm{},
+		GuiasProcessadas: []string{},
+		AllSQLInserts:    []string{},
+		Falhas:           []output.FalhaProcessamento{},
+		Documentos:       []output.ResultadoDocumento{},
+	}
+	dp.extract = extractDocumento
+
+	return dp
+}
+
+// Init inicializa o processador
+func (dp *DarmProcessor) Init() error {
+	logrus.Info("🔧 Inicializando processador de DARMs...")
+
+	// Criar diretórios se não existirem
+	if err := os.MkdirAll(dp.DarmsDir, 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório darms: %v", err)
+	}
+
+	if err := os.MkdirAll(dp.OutputDir, 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório inserts: %v", err)
+	}
+
+	logrus.Infof("📁 Diretório base: %s", dp.BaseDir)
+	logrus.Infof("📁 Diretório DARMs: %s", dp.DarmsDir)
+	logrus.Infof("📁 Diretório saída: %s", dp.OutputDir)
+
+	// Carregar correções manuais
+	if path := dp.Config.Paths.OverridesFile; path != "" {
+		set, err := overrides.Load(path)
+		if err != nil {
+			return err
+		}
+		dp.Overrides = set
+		logrus.Infof("✏️ Correções manuais: %d PDF(s) em %s", set.Len(), path)
+	}
+
+	// Carregar guias já processadas
+	dp.loadProcessedGuias()
+
+	return nil
+}
+
+// loadProcessedGuias carrega guias já processadas
+func (dp *DarmProcessor) loadProcessedGuias() {
+	logrus.Info("🔄 Modo de reprocessamento ativado - todos os arquivos serão sobrescritos")
+}
+
+// checkGuiaExists gera o arquivo de verificação individual de uma guia
+func (dp *DarmProcessor) checkGuiaExists(numeroGuia string, row sqlgen.DarmRow) error {
+	checkFilename := fmt.Sprintf("CHECK_GUIA_%s.sql", numeroGuia)
+	checkPath := filepath.Join(dp.OutputDir, checkFilename)
+
+	opts, err := dp.Config.InsertOptions()
+	if err != nil {
+		return err
+	}
+
+	// Escrever arquivo em encoding latin1
+	if err := os.WriteFile(checkPath, []byte(sqlgen.RenderCheckQuery([]sqlgen.DarmRow{row}, opts.EffectiveDialect())), 0644); err != nil {
+		return fmt.Errorf("erro ao criar arquivo de verificação: %v", err)
+	}
+
+	logrus.Infof("Arquivo de verificação criado: %s", checkFilename)
+	return nil
+}
+
+// generateCheckFile gera CHECK_GUIAS.sql, que verifica todas as guias da execução em uma única consulta
+func (dp *DarmProcessor) generateCheckFile() error {
+	if len(dp.Resultados) == 0 {
+		return nil
+	}
+
+	rows := make([]sqlgen.DarmRow, 0, len(dp.Resultados))
+	for _, resultado := range dp.Resultados {
+		rows = append(rows, resultado.Linha)
+	}
+
+	opts, err := dp.Config.InsertOptions()
+	if err != nil {
+		return err
+	}
+
+	checkPath := filepath.Join(dp.OutputDir, "CHECK_GUIAS.sql")
+	if err := os.WriteFile(checkPath, []byte(sqlgen.RenderCheckQuery(rows, opts.EffectiveDialect())), 0644); err != nil {
+		return fmt.Errorf("erro ao criar arquivo de verificação: %v", err)
+	}
+
+	logrus.Infof("🔎 Arquivo de verificação gerado: CHECK_GUIAS.sql (%d guias)", len(rows))
+	logrus.Info("IMPORTANTE: Execute CHECK_GUIAS.sql para listar as guias que já existem no banco")
+	return nil
+}
+
+// generateSingleSQLFile gera arquivo SQL único com todos os INSERTs
+func (dp *DarmProcessor) generateSingleSQLFile() error {
+	if len(dp.Resultados) == 0 {
+		logrus.Info("📭 Nenhum INSERT para gerar no arquivo único.")
+		return nil
+	}
+
+	// Gerar SQ_DOC únicos, calculados a partir da guia de cada linha
+	rows := make([]sqlgen.DarmRow, 0, len(dp.Resultados))
+	for _, resultado := range dp.Resultados {
+		rows = append(rows, resultado.Linha.Clone())
+	}
+	sqDocsInfo := []string{}
+	for index, sqDoc := range sqlgen.AssignSQDocs(rows, time.Now()) {
+		sqDocsInfo = append(sqDocsInfo, fmt.Sprintf("Guia %s = %d", dp.Resultados[index].Dados.NumeroGuia, sqDoc))
+	}
+
+	opts, err := dp.Config.ScriptOptions()
+	if err != nil {
+		return err
+	}
+	singleSQLContent, err := sqlgen.Generate(rows, opts)
+	if err != nil {
+		return fmt.Errorf("erro ao montar INSERT do arquivo SQL único: %v", err)
+	}
+
+	singleSQLPath := filepath.Join(dp.OutputDir, "INSERT_TODOS_DARMs.sql")
+
+	// Escrever arquivo em encoding latin1
+	if err := os.WriteFile(singleSQLPath, singleSQLContent, 0644); err != nil {
+		return fmt.Errorf("erro ao gerar arquivo SQL único: %v", err)
+	}
+
+	// Gerar script de rollback correspondente
+	rollbackPath := filepath.Join(dp.OutputDir, "ROLLBACK_TODOS_DARMs.sql")
+	if err := sqlgen.WriteRollbackScript(rollbackPath, sqlgen.RollbackKeysFromRows(rows), opts); err != nil {
+		return err
+	}
+
+	// Gerar carga em massa (LOAD DATA) com as mesmas linhas
+	if dp.Config.SQL.BulkLoad {
+		if err := dp.generateBulkLoad(rows); err != nil {
+			return err
+		}
+	}
+
+	logrus.Info("📄 Arquivo SQL único gerado: INSERT_TODOS_DARMs.sql")
+	logrus.Infof("📊 Contém %d registros em %d lote(s)", len(rows), len(sqlgen.SplitBatches(rows, opts.EffectiveBatchSize())))
+	if opts.Comments {
+		logrus.Info("🔧 Formato: ISO 8859-1 (Latin-1) com comentários")
+	} else {
+		logrus.Info("🔧 Formato: ISO 8859-1 (Latin-1) - Compatível com Control-M")
+	}
+	if opts.UseTransaction {
+		logrus.Info("⚡ Versão: Transacional (START TRANSACTION/COMMIT, SQ_DOC calculado no Go)")
+	} else {
+		logrus.Info("⚡ Versão: Simples (sem transação, SQ_DOC calculado no Go)")
+	}
+
+	// Mostrar SQ_DOC gerados
+	logrus.Infof("🔢 SQ_DOC gerados: %s", strings.Join(sqDocsInfo, ", "))
+
+	return nil
+}
+
+// getUniqueGuias retorna guias únicas
+func (dp *DarmProcessor) getUniqueGuias() []string {
+	unique := make(map[string]bool)
+	for _, guia := range dp.GuiasProcessadas {
+		unique[guia] = true
+	}
+
+	result := []string{}
+	for guia := range unique {
+		result = append(result, guia)
+	}
+	return result
+}
+
+// ProcessDarms processa todos os DARMs no diretório.
+// O cancelamento do contexto interrompe o envio de novos arquivos; o relatório
+// e o arquivo SQL único ainda são gerados para os arquivos já concluídos.
+func (dp *DarmProcessor) ProcessDarms(ctx context.Context) error {
+	// Verificar se o diretório darms existe
+	if _, err := os.Stat(dp.DarmsDir); os.IsNotExist(err) {
+		return fmt.Errorf("diretório darms não encontrado: %s", dp.DarmsDir)
+	}
+
+	// Listar todos os arquivos PDF no diretório darms
+	docs, err := ListDiretorio(dp.DarmsDir)
+	if err != nil {
+		return err
+	}
+
+	if len(docs) == 0 {
+		logrus.Info("📭 Nenhum arquivo PDF encontrado no diretório darms.")
+		return nil
+	}
+
+	return dp.ProcessDocumentos(ctx, docs)
+}
+
+// ProcessDocumentos processa os documentos informados (de disco ou em memória)
+// e grava os scripts SQL, as exportações e o relatório em OutputDir
+func (dp *DarmProcessor) ProcessDocumentos(ctx context.Context, docs []Documento) error {
+	logrus.Info("🚀 Iniciando processamento dos DARMs...")
+	inicio := time.Now()
+
+	logrus.Infof("📁 Encontrados %d arquivos PDF para processar.", len(docs))
+
+	dp.processFiles(ctx, docs)
+	dp.sortResultados()
+
+	for _, chave := range dp.Overrides.NaoAplicadas() {
+		logrus.Warnf("⚠️ Correção manual sem PDF correspondente: %s", chave)
+	}
+
+	// Gerar arquivo SQL único
+	if err := dp.generateSingleSQLFile(); err != nil {
+		logrus.Errorf("❌ Erro ao gerar arquivo SQL único: %v", err)
+	}
+
+	// Gerar verificação consolidada das guias
+	if err := dp.generateCheckFile(); err != nil {
+		logrus.Errorf("❌ Erro ao gerar arquivo de verificação: %v", err)
+	}
+
+	// Exportar dados extraídos (JSON, JSONL, CSV)
+	exportOpts := output.ExportOptions{DecimalComma: dp.Config.Output.CSVDecimalComma}
+	if err := output.WriteExports(dp.OutputDir, dp.Config.Output.Formats, exportOpts, output.ExportRecords(dp.Resultados, dp.Falhas)); err != nil {
+		logrus.Errorf("❌ Erro ao exportar dados: %v", err)
+	}
+	if _, err := output.WriteDocumentos(dp.OutputDir, dp.Documentos, exportOpts); err != nil {
+		logrus.Errorf("❌ Erro ao exportar DARFs e GNREs: %v", err)
+	}
+
+	// Gerar relatório final (após as saídas, para listar os arquivos gerados)
+	if err := dp.generateReport(documentNames(docs), inicio); err != nil {
+		logrus.Errorf("❌ Erro ao gerar relatório: %v", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		logrus.Warnf("⚠️ Processamento interrompido: %d de %d arquivos concluídos", len(dp.GuiasProcessadas), len(docs))
+		return fmt.Errorf("processamento interrompido: %w", err)
+	}
+
+	logrus.Info("✅ Processamento concluído!")
+	logrus.Infof("📊 Total de guias processadas: %d", len(dp.GuiasProcessadas))
+	if len(dp.Documentos) > 0 {
+		logrus.Infof("🧾 DARFs e GNREs exportados: %d", len(dp.Documentos))
+	}
+
+	return nil
+}
+
+// processFiles distribui os documentos entre um pool limitado de workers
+func (dp *DarmProcessor) processFiles(ctx context.Context, docs []Documento) {
+	workers := dp.Config.WorkerCount()
+	if workers > len(docs) {
+		workers = len(docs)
+	}
+	logrus.Infof("⚙️ Processando com %d workers (timeout por arquivo: %s)", workers, dp.Config.FileTimeout())
+
+	jobs := make(chan Documento)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for doc := range jobs {
+				inicio := time.Now()
+				extracao, err := dp.processPDFFile(ctx, doc)
+				if err == nil {
+					continue
+				}
+				if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+					logrus.Warnf("⚠️ Processamento de %s cancelado", doc.Nome)
+					continue
+				}
+
+				logrus.Errorf("❌ Erro ao processar %s: %v", doc.Nome, err)
+				dp.mu.Lock()
+				dp.Falhas = append(dp.Falhas, output.FalhaProcessamento{
+					Arquivo:  doc.Nome,
+					Erro:     err.Error(),
+					Duracao:  time.Since(inicio),
+					Extracao: extracao,
+				})
+				dp.mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, doc := range docs {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- doc:
+		}
+	}
+	close(jobs)
+
+	// Aguardar todos os workers terminarem
+	wg.Wait()
+}
+
+// sortResultados ordena os resultados por arquivo de origem (e guia, em caso
+// de empate) e reconstrói GuiasProcessadas e AllSQLInserts na mesma ordem,
+// tornando as saídas consolidadas independentes do escalonamento das goroutines
+func (dp *DarmProcessor) sortResultados() {
+	dp.mu.Lock()
+	defer dp.mu.Unlock()
+
+	sort.SliceStable(dp.Resultados, func(i, j int) bool {
+		a, b := dp.Resultados[i], dp.Resultados[j]
+		if a.Arquivo != b.Arquivo {
+			return a.Arquivo < b.Arquivo
+		}
+		return a.Dados.NumeroGuia < b.Dados.NumeroGuia
+	})
+	sort.SliceStable(dp.Falhas, func(i, j int) bool {
+		return dp.Falhas[i].Arquivo < dp.Falhas[j].Arquivo
+	})
+	sort.SliceStable(dp.Documentos, func(i, j int) bool {
+		return dp.Documentos[i].Arquivo < dp.Documentos[j].Arquivo
+	})
+
+	dp.GuiasProcessadas = make([]string, 0, len(dp.Resultados))
+	dp.AllSQLInserts = make([]string, 0, len(dp.Resultados))
+	for _, resultado := range dp.Resultados {
+		dp.GuiasProcessadas = append(dp.GuiasProcessadas, resultado.Dados.NumeroGuia)
+		dp.AllSQLInserts = append(dp.AllSQLInserts, resultado.SQL)
+	}
+}
+
+// processPDFFile processa um documento PDF individual, respeitando o tempo
+// limite por arquivo e convertendo panics da extração e da validação em erro.
+// Em caso de falha, retorna também o que foi extraído, para revisão manual.
+func (dp *DarmProcessor) processPDFFile(ctx context.Context, doc Documento) (*extraction.Extracao, error) {
+	logrus.Infof("📄 Processando arquivo: %s", doc.Nome)
+	inicio := time.Now()
+
+	if timeout := dp.Config.FileTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Canal com buffer: a goroutine termina mesmo se o resultado for descartado
+	done := make(chan prepared, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- prepared{err: fmt.Errorf("panic durante a extração: %v", r)}
+			}
+		}()
+		done <- dp.prepare(doc)
+	}()
+
+	select {
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("tempo limite de %s excedido", dp.Config.FileTimeout())
+		}
+		return nil, ctx.Err()
+	case result := <-done:
+		if result.err != nil {
+			return result.extracao, result.err
+		}
+		if tipo := result.extracao.Tipo; tipo == extraction.TipoDARF || tipo == extraction.TipoGNRE {
+			return result.extracao, dp.registerDocumento(doc.Nome, result.extracao, inicio)
+		}
+		return result.extracao, dp.writeDarmSQL(doc.Nome, result.extracao, result.record, result.correcoes, inicio)
+	}
+}
+
+// prepared é o resultado da extração de um documento, já com as correções
+// manuais aplicadas e, para DARMs, o registro validado
+type prepared struct {
+	extracao  *extraction.Extracao
+	correcoes []overrides.Correcao
+	record    *validation.DarmRecord
+	err       error
+}
+
+// prepare extrai o documento, aplica as correções manuais e valida o DARM.
+// Roda dentro do recover de processPDFFile: um payload malformado vindo do
+// PDF, do arquivo de correções ou do painel vira erro do arquivo.
+func (dp *DarmProcessor) prepare(doc Documento) prepared {
+	extracao, err := dp.extract(doc)
+	if err != nil {
+		return prepared{err: err}
+	}
+	if tipo := extracao.Tipo; tipo == extraction.TipoDARF || tipo == extraction.TipoGNRE {
+		return prepared{extracao: extracao}
+	}
+
+	correcoes := doc.Correcoes
+	if !doc.Revisado {
+		extracao, correcoes = dp.applyOverrides(doc.Nome, extracao)
+	}
+	if extracao == nil || extracao.Dados == nil {
+		if extracao != nil && extracao.Tipo == extraction.TipoDesconhecido {
+			logrus.Infof("❌ Tipo de documento não reconhecido: %s", doc.Nome)
+			return prepared{extracao: extracao, err: extraction.ErrTipoDesconhecido}
+		}
+		logrus.Infof("❌ Não foi possível extrair dados do arquivo: %s", doc.Nome)
+		return prepared{extracao: extracao, err: extraction.ErrDadosInsuficientes}
+	}
+
+	record, err := validation.Parse(extracao.Dados, dp.Config.ValidationOptions())
+	if err != nil {
+		return prepared{extracao: extracao, err: fmt.Errorf("guia %s rejeitada: %v", guiaLabel(extracao.Dados), err)}
+	}
+	return prepared{extracao: extracao, correcoes: correcoes, record: record}
+}
+
+// guiaLabel é o número da guia usado em nomes de arquivo e mensagens
+func guiaLabel(darmData *extraction.DarmData) string {
+	if darmData.NumeroGuia == "" {
+		return "SEM_GUIA"
+	}
+	return darmData.NumeroGuia
+}
+
+// applyOverrides aplica a correção manual do documento, se houver, a uma cópia
+// da extração; correções podem completar PDFs com dados insuficientes
+func (dp *DarmProcessor) applyOverrides(arquivo string, extracao *extraction.Extracao) (*extraction.Extracao, []overrides.Correcao) {
+	if extracao == nil {
+		return nil, nil
+	}
+	dados, correcoes := dp.Overrides.Apply(arquivo, extracao.Hash, extracao.Dados)
+	if len(correcoes) == 0 {
+		return extracao, nil
+	}
+
+	for _, c := range correcoes {
+		logrus.Infof("✏️ %s: %s corrigido manualmente (%q → %q)", arquivo, c.Campo, c.Anterior, c.Valor)
+	}
+	corrigida := *extracao
+	corrigida.Dados = dados
+	return &corrigida, correcoes
+}
+
+// writeDarmSQL grava o arquivo SQL individual da guia e registra o resultado
+// (record é o DARM validado por prepare, correcoes são as correções manuais
+// aplicadas e inicio é o começo do processamento do arquivo, para o relatório)
+func (dp *DarmProcessor) writeDarmSQL(arquivo string, extracao *extraction.Extracao, record *validation.DarmRecord, correcoes []overrides.Correcao, inicio time.Time) error {
+	darmData := extracao.Dados
+
+	// Verificar se já existe um arquivo SQL para esta guia
+	numeroGuia := guiaLabel(darmData)
+	sqlFilename := fmt.Sprintf("INSERT_DARM_PAGO_%s.sql", numeroGuia)
+	sqlPath := filepath.Join(dp.OutputDir, sqlFilename)
+
+	// Sempre sobrescrever arquivos existentes
+	if _, err := os.Stat(sqlPath); err == nil {
+		logrus.Infof("🔄 Sobrescrevendo arquivo existente para guia %s", numeroGuia)
+	}
+
+	for _, s := range record.Substituicoes {
+		logrus.Warnf("⚠️ Guia %s: %s", numeroGuia, s)
+	}
+	row := sqlgen.RowFromRecord(record)
+
+	// Verificação individual da guia (opcional; CHECK_GUIAS.sql cobre todas)
+	if dp.Config.SQL.PerGuiaChecks {
+		if err := dp.checkGuiaExists(numeroGuia, row); err != nil {
+			logrus.Errorf("❌ Erro ao verificar guia: %v", err)
+		}
+	}
+	sqlContent, err := dp.renderDarmSQL(row)
+	if err != nil {
+		return fmt.Errorf("erro ao gerar INSERT da guia %s: %v", numeroGuia, err)
+	}
+
+	// Escrever arquivo em encoding latin1
+	if err := os.WriteFile(sqlPath, []byte(sqlContent), 0644); err != nil {
+		return fmt.Errorf("erro ao escrever arquivo SQL: %v", err)
+	}
+
+	// Thread-safe: registrar guia, dados e INSERT em um único registro
+	dp.mu.Lock()
+	dp.ProcessedGuias[darmData.NumeroGuia] = true
+	dp.Resultados = append(dp.Resultados, output.ResultadoDarm{
+		Arquivo:  arquivo,
+		Dados:    darmData,
+		Linha:    row,
+		Registro: record,
+		SQL:      sqlContent,
+		Texto:    extracao.Texto,
+		Proveniencia: output.Proveniencia{
+			Arquivo:    arquivo,
+			Pagina:     extracao.Pagina,
+			HashSHA256: extracao.Hash,
+			ExtraidoEm: time.Now(),
+			Correcoes:  correcoes,
+		},
+		Duracao: time.Since(inicio),
+	})
+	total := len(dp.Resultados)
+	dp.mu.Unlock()
+
+	logrus.Infof("✅ Arquivo SQL gerado: %s", sqlFilename)
+	logrus.Infof("📊 Guias processadas até agora: %d", total)
+
+	return nil
+}
+
+// registerDocumento valida um DARF ou uma GNRE e registra o resultado, que
+// vai para DARFs.csv ou GNREs.csv em vez de gerar INSERT em FarrDarmsPagos
+func (dp *DarmProcessor) registerDocumento(arquivo string, extracao *extraction.Extracao, inicio time.Time) error {
+	documento := output.ResultadoDocumento{
+		Arquivo: arquivo,
+		Tipo:    extracao.Tipo,
+		Proveniencia: output.Proveniencia{
+			Arquivo:    arquivo,
+			Pagina:     extracao.Pagina,
+			HashSHA256: extracao.Hash,
+			ExtraidoEm: time.Now(),
+		},
+	}
+
+	var err error
+	switch {
+	case extracao.Darf != nil:
+		documento.Darf, err = validation.ParseDarf(extracao.Darf)
+	case extracao.Gnre != nil:
+		documento.Gnre, err = validation.ParseGnre(extracao.Gnre)
+	default:
+		logrus.Infof("❌ Não foi possível extrair dados do %s: %s", extracao.Tipo, arquivo)
+		return fmt.Errorf("%s: %w", extracao.Tipo, extraction.ErrDadosInsuficientes)
+	}
+	if err != nil {
+		return fmt.Errorf("%s rejeitado: %v", extracao.Tipo, err)
+	}
+	documento.Duracao = time.Since(inicio)
+
+	dp.mu.Lock()
+	dp.Documentos = append(dp.Documentos, documento)
+	dp.mu.Unlock()
+
+	logrus.Infof("🧾 %s registrado: %s", extracao.Tipo, arquivo)
+	return nil
+}
+
+// renderDarmSQL gera o conteúdo do arquivo SQL individual de uma linha
+func (dp *DarmProcessor) renderDarmSQL(row sqlgen.DarmRow) (string, error) {
+	opts, err := dp.Config.InsertOptions()
+	if err != nil {
+		return "", err
+	}
+
+	insert, err := sqlgen.RenderInsert(row, opts)
+	if err != nil {
+		return "", err
+	}
+
+	// Gerar SQL limpo sem comentários
+	return opts.EffectiveDialect().UseSchema(sqlgen.DefaultSchema) + "\n\n" + insert, nil
+}
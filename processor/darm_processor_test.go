@@ -0,0 +1,274 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/internal/darmtest"
+)
+
+// newTestProcessor cria um processador em diretório temporário com n PDFs vazios
+func newTestProcessor(t *testing.T, n int) *DarmProcessor {
+	t.Helper()
+	logrus.SetLevel(logrus.ErrorLevel)
+
+	tempDir := t.TempDir()
+	processor := NewDarmProcessor()
+	processor.BaseDir = tempDir
+	processor.DarmsDir = filepath.Join(tempDir, "darms")
+	processor.OutputDir = filepath.Join(tempDir, "inserts")
+
+	if err := processor.Init(); err != nil {
+		t.Fatalf("Init falhou: %v", err)
+	}
+
+	for i := 1; i <= n; i++ {
+		name := filepath.Join(processor.DarmsDir, fmt.Sprintf("%04d.pdf", i))
+		if err := os.WriteFile(name, nil, 0644); err != nil {
+			t.Fatalf("erro ao criar PDF de teste: %v", err)
+		}
+	}
+
+	return processor
+}
+
+// TestNewDarmProcessor testa criação do processador
+func TestNewDarmProcessor(t *testing.T) {
+	processor := NewDarmProcessor()
+
+	if processor == nil {
+		t.Fatal("Processador não deveria ser nil")
+	}
+
+	if processor.BaseDir == "" {
+		t.Error("BaseDir não deveria estar vazio")
+	}
+
+	if processor.DarmsDir == "" {
+		t.Error("DarmsDir não deveria estar vazio")
+	}
+
+	if processor.OutputDir == "" {
+		t.Error("OutputDir não deveria estar vazio")
+	}
+
+	if processor.ProcessedGuias == nil {
+		t.Error("ProcessedGuias deveria ser inicializado")
+	}
+
+	if processor.GuiasProcessadas == nil {
+		t.Error("GuiasProcessadas deveria ser inicializado")
+	}
+
+	if processor.AllSQLInserts == nil {
+		t.Error("AllSQLInserts deveria ser inicializado")
+	}
+}
+
+// TestInit testa inicialização do processador
+func TestInit(t *testing.T) {
+	// Criar diretório temporário para teste
+	tempDir := t.TempDir()
+
+	processor := NewDarmProcessor()
+	processor.BaseDir = tempDir
+	processor.DarmsDir = filepath.Join(tempDir, "darms")
+	processor.OutputDir = filepath.Join(tempDir, "inserts")
+
+	err := processor.Init()
+	if err != nil {
+		t.Fatalf("Init falhou: %v", err)
+	}
+
+	// Verificar se diretórios foram criados
+	if _, err := os.Stat(processor.DarmsDir); os.IsNotExist(err) {
+		t.Error("Diretório darms deveria ter sido criado")
+	}
+
+	if _, err := os.Stat(processor.OutputDir); os.IsNotExist(err) {
+		t.Error("Diretório inserts deveria ter sido criado")
+	}
+}
+
+func TestProcessDarmsRecoversPanic(t *testing.T) {
+	processor := newTestProcessor(t, 3)
+	processor.extract = func(doc Documento) (*extraction.Extracao, error) {
+		if strings.HasSuffix(doc.Nome, "0002.pdf") {
+			panic("PDF corrompido")
+		}
+		return &extraction.Extracao{Dados: darmtest.DarmData(doc.Nome)}, nil
+	}
+
+	if err := processor.ProcessDarms(context.Background()); err != nil {
+		t.Fatalf("ProcessDarms falhou: %v", err)
+	}
+
+	if len(processor.GuiasProcessadas) != 2 {
+		t.Errorf("esperadas 2 guias processadas, obtidas %d", len(processor.GuiasProcessadas))
+	}
+	if len(processor.Falhas) != 1 || processor.Falhas[0].Arquivo != "0002.pdf" {
+		t.Fatalf("esperada falha em 0002.pdf, obtido %+v", processor.Falhas)
+	}
+	if !strings.Contains(processor.Falhas[0].Erro, "panic") {
+		t.Errorf("erro deveria mencionar o panic: %s", processor.Falhas[0].Erro)
+	}
+}
+
+func TestProcessDarmsFileTimeout(t *testing.T) {
+	processor := newTestProcessor(t, 2)
+	processor.Config.Processing.TimeoutSeconds = 1
+
+	release := make(chan struct{})
+	defer close(release)
+	processor.extract = func(doc Documento) (*extraction.Extracao, error) {
+		if strings.HasSuffix(doc.Nome, "0001.pdf") {
+			<-release
+		}
+		return &extraction.Extracao{Dados: darmtest.DarmData(doc.Nome)}, nil
+	}
+
+	if err := processor.ProcessDarms(context.Background()); err != nil {
+		t.Fatalf("ProcessDarms falhou: %v", err)
+	}
+
+	if len(processor.Falhas) != 1 || !strings.Contains(processor.Falhas[0].Erro, "tempo limite") {
+		t.Fatalf("esperada falha por tempo limite, obtido %+v", processor.Falhas)
+	}
+	if len(processor.GuiasProcessadas) != 1 {
+		t.Errorf("esperada 1 guia processada, obtidas %d", len(processor.GuiasProcessadas))
+	}
+}
+
+func TestProcessDarmsCancelWritesReport(t *testing.T) {
+	processor := newTestProcessor(t, 10)
+	processor.Config.Processing.Workers = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	processed := 0
+	processor.extract = func(doc Documento) (*extraction.Extracao, error) {
+		processed++
+		if processed == 2 {
+			cancel()
+			time.Sleep(10 * time.Millisecond)
+		}
+		return &extraction.Extracao{Dados: darmtest.DarmData(doc.Nome)}, nil
+	}
+
+	err := processor.ProcessDarms(ctx)
+	if err == nil || !strings.Contains(err.Error(), "interrompido") {
+		t.Fatalf("esperado erro de interrupção, obtido %v", err)
+	}
+
+	if len(processor.GuiasProcessadas) == 0 || len(processor.GuiasProcessadas) >= 10 {
+		t.Errorf("esperado processamento parcial, obtidas %d guias", len(processor.GuiasProcessadas))
+	}
+
+	if _, err := os.Stat(filepath.Join(processor.OutputDir, "RELATORIO_PROCESSAMENTO.md")); err != nil {
+		t.Errorf("relatório deveria ser gerado mesmo após cancelamento: %v", err)
+	}
+}
+
+// TestProcessDarmsDeterministicOrder processa centenas de documentos em
+// paralelo (execute com -race) e verifica que cada linha corresponde ao seu
+// arquivo de origem e que a ordem das saídas consolidadas é determinística
+func TestProcessDarmsDeterministicOrder(t *testing.T) {
+	const total = 300
+
+	processor := newTestProcessor(t, total)
+	processor.Config.Processing.Workers = 16
+	processor.extract = func(doc Documento) (*extraction.Extracao, error) {
+		// Atraso variável para embaralhar a ordem de conclusão
+		n, _ := strconv.Atoi(strings.TrimSuffix(doc.Nome, ".pdf"))
+		time.Sleep(time.Duration((n*7919)%5) * time.Millisecond)
+		return &extraction.Extracao{Dados: darmtest.DarmData(doc.Nome)}, nil
+	}
+
+	if err := processor.ProcessDarms(context.Background()); err != nil {
+		t.Fatalf("ProcessDarms falhou: %v", err)
+	}
+
+	if len(processor.Resultados) != total {
+		t.Fatalf("esperados %d resultados, obtidos %d", total, len(processor.Resultados))
+	}
+
+	arquivos := make([]string, 0, total)
+	for i, resultado := range processor.Resultados {
+		arquivos = append(arquivos, resultado.Arquivo)
+
+		expected := darmtest.DarmData(resultado.Arquivo)
+		if *resultado.Dados != *expected {
+			t.Errorf("resultado %s não corresponde à origem: %+v", resultado.Arquivo, resultado.Dados)
+		}
+		if !strings.Contains(resultado.SQL, "'"+expected.Inscricao+"'") {
+			t.Errorf("INSERT de %s não contém a inscrição %s", resultado.Arquivo, expected.Inscricao)
+		}
+		if processor.GuiasProcessadas[i] != expected.NumeroGuia || processor.AllSQLInserts[i] != resultado.SQL {
+			t.Errorf("GuiasProcessadas/AllSQLInserts fora de ordem na posição %d", i)
+		}
+	}
+	if !sort.StringsAreSorted(arquivos) {
+		t.Error("resultados deveriam estar ordenados por arquivo")
+	}
+
+	content, err := os.ReadFile(filepath.Join(processor.OutputDir, "INSERT_TODOS_DARMs.sql"))
+	if err != nil {
+		t.Fatalf("erro ao ler arquivo SQL único: %v", err)
+	}
+
+	// Cada linha do INSERT consolidado deve trazer inscrição e guia do mesmo arquivo
+	rowRegex := regexp.MustCompile(`'9(\d{4})', (\d+),`)
+	rows := rowRegex.FindAllStringSubmatch(string(content), -1)
+	if len(rows) != total {
+		t.Fatalf("esperadas %d linhas no arquivo SQL único, obtidas %d", total, len(rows))
+	}
+	for i, row := range rows {
+		inscricao, _ := strconv.Atoi(row[1])
+		guia, _ := strconv.Atoi(row[2])
+		if inscricao != i+1 || guia != i+1 {
+			t.Errorf("linha %d: inscrição %s e guia %s não correspondem a %04d.pdf", i, row[1], row[2], i+1)
+		}
+	}
+}
+
+func TestProcessDarmsConsolidatedCheck(t *testing.T) {
+	for _, perGuia := range []bool{false, true} {
+		processor := newTestProcessor(t, 3)
+		processor.Config.SQL.PerGuiaChecks = perGuia
+		processor.extract = func(doc Documento) (*extraction.Extracao, error) {
+			return &extraction.Extracao{Dados: darmtest.DarmData(doc.Nome)}, nil
+		}
+
+		if err := processor.ProcessDarms(context.Background()); err != nil {
+			t.Fatalf("ProcessDarms falhou: %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(processor.OutputDir, "CHECK_GUIAS.sql"))
+		if err != nil {
+			t.Fatalf("CHECK_GUIAS.sql deveria ser gerado: %v", err)
+		}
+		if !strings.Contains(string(content), "NR_GUIA IN (1, 2, 3)") {
+			t.Errorf("CHECK_GUIAS.sql deveria verificar todas as guias:\n%s", content)
+		}
+
+		individual, _ := filepath.Glob(filepath.Join(processor.OutputDir, "CHECK_GUIA_*.sql"))
+		if perGuia && len(individual) != 3 {
+			t.Errorf("per_guia_checks: esperados 3 arquivos individuais, obtidos %d", len(individual))
+		}
+		if !perGuia && len(individual) != 0 {
+			t.Errorf("arquivos individuais não deveriam ser gerados por padrão, obtidos %d", len(individual))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/overrides"
+)
+
+// Documento é um PDF de DARM a processar, lido do disco ou já em memória
+// (upload HTTP, arquivo de um ZIP, anexo de e-mail)
+type Documento struct {
+	Nome     string      // Nome exibido nos resultados, falhas e relatório
+	Caminho  string      // Caminho no disco (vazio para documentos em memória)
+	Conteudo io.ReaderAt // Conteúdo do PDF; quando nil, Caminho é lido
+	Tamanho  int64       // Tamanho de Conteudo em bytes
+
+	// Extracao traz dados já extraídos (revisados no painel, importados);
+	// quando informada, o PDF não é lido
+	Extracao *extraction.Extracao
+	// Revisado indica dados já revisados no painel: o arquivo de correções não
+	// é reaplicado e Correcoes (feitas na revisão) vai para a proveniência
+	Revisado  bool
+	Correcoes []overrides.Correcao
+}
+
+// NewDocumento cria um documento em memória a partir do conteúdo do PDF
+func NewDocumento(nome string, content []byte) Documento {
+	return Documento{
+		Nome:     nome,
+		Conteudo: bytes.NewReader(content),
+		Tamanho:  int64(len(content)),
+	}
+}
+
+// ListDiretorio lista os PDFs de dir como documentos, em ordem alfabética
+func ListDiretorio(dir string) ([]Documento, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler diretório darms: %v", err)
+	}
+
+	docs := []Documento{}
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(strings.ToLower(file.Name()), ".pdf") {
+			docs = append(docs, Documento{Nome: file.Name(), Caminho: filepath.Join(dir, file.Name())})
+		}
+	}
+	return docs, nil
+}
+
+// extractDocumento extrai os dados de um documento do conteúdo em memória ou
+// do arquivo em disco
+func extractDocumento(doc Documento) (*extraction.Extracao, error) {
+	if doc.Extracao != nil {
+		return doc.Extracao, nil
+	}
+	if doc.Conteudo != nil {
+		return extraction.ExtractReaderAt(doc.Conteudo, doc.Tamanho)
+	}
+	return extraction.ExtractFile(doc.Caminho)
+}
+
+// documentNames retorna os nomes dos documentos, na mesma ordem
+func documentNames(docs []Documento) []string {
+	names := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		names = append(names, doc.Nome)
+	}
+	return names
+}
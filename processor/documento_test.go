@@ -0,0 +1,160 @@
+package processor
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/internal/darmtest"
+	"gerador-query-darm-go/output"
+)
+
+func TestListDiretorio(t *testing.T) {
+	processor := newTestProcessor(t, 2)
+	if err := os.WriteFile(filepath.Join(processor.DarmsDir, "leia-me.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := ListDiretorio(processor.DarmsDir)
+	if err != nil {
+		t.Fatalf("ListDiretorio falhou: %v", err)
+	}
+	if len(docs) != 2 || docs[0].Nome != "0001.pdf" || docs[1].Caminho != filepath.Join(processor.DarmsDir, "0002.pdf") {
+		t.Errorf("documentos inesperados: %+v", docs)
+	}
+	if _, err := ListDiretorio(filepath.Join(processor.BaseDir, "inexistente")); err == nil {
+		t.Error("diretório inexistente deveria falhar")
+	}
+}
+
+func TestProcessDocumentosInMemory(t *testing.T) {
+	processor := newTestProcessor(t, 0)
+	processor.extract = func(doc Documento) (*extraction.Extracao, error) {
+		if doc.Caminho != "" {
+			t.Errorf("documento em memória não deveria ter caminho: %s", doc.Caminho)
+		}
+		content, err := io.ReadAll(io.NewSectionReader(doc.Conteudo, 0, doc.Tamanho))
+		if err != nil {
+			return nil, err
+		}
+		return &extraction.Extracao{Dados: darmtest.DarmData(string(content))}, nil
+	}
+
+	docs := []Documento{
+		NewDocumento("upload-b.pdf", []byte("0002")),
+		NewDocumento("upload-a.pdf", []byte("0001")),
+	}
+	if err := processor.ProcessDocumentos(context.Background(), docs); err != nil {
+		t.Fatalf("ProcessDocumentos falhou: %v", err)
+	}
+
+	if len(processor.Resultados) != 2 || processor.Resultados[0].Arquivo != "upload-a.pdf" {
+		t.Fatalf("resultados inesperados: %+v", processor.Resultados)
+	}
+	if processor.GuiasProcessadas[0] != "1" || processor.GuiasProcessadas[1] != "2" {
+		t.Errorf("guias inesperadas: %v", processor.GuiasProcessadas)
+	}
+	if _, err := os.Stat(filepath.Join(processor.OutputDir, "INSERT_TODOS_DARMs.sql")); err != nil {
+		t.Errorf("arquivo SQL único deveria ser gerado: %v", err)
+	}
+}
+
+func TestProcessDocumentosInvalidContent(t *testing.T) {
+	processor := newTestProcessor(t, 0)
+
+	docs := []Documento{NewDocumento("anexo.pdf", []byte("não é um PDF"))}
+	if err := processor.ProcessDocumentos(context.Background(), docs); err != nil {
+		t.Fatalf("ProcessDocumentos falhou: %v", err)
+	}
+
+	if len(processor.Falhas) != 1 || processor.Falhas[0].Arquivo != "anexo.pdf" {
+		t.Errorf("esperada falha em anexo.pdf, obtido %+v", processor.Falhas)
+	}
+}
+
+func TestProcessDocumentosPreExtracted(t *testing.T) {
+	processor := newTestProcessor(t, 0)
+	processor.extract = extractDocumento
+
+	docs := []Documento{
+		{Nome: "revisado.pdf", Extracao: &extraction.Extracao{Dados: darmtest.DarmData("0007.pdf"), Texto: "texto"}},
+		{Nome: "ilegivel.pdf", Extracao: &extraction.Extracao{Texto: "sem campos"}},
+	}
+	if err := processor.ProcessDocumentos(context.Background(), docs); err != nil {
+		t.Fatalf("ProcessDocumentos falhou: %v", err)
+	}
+
+	if len(processor.Resultados) != 1 || processor.Resultados[0].Dados.NumeroGuia != "7" {
+		t.Errorf("resultados inesperados: %+v", processor.Resultados)
+	}
+	// A falha mantém o texto extraído, para revisão manual
+	if len(processor.Falhas) != 1 || processor.Falhas[0].Extracao == nil || processor.Falhas[0].Extracao.Texto != "sem campos" {
+		t.Errorf("falha deveria manter o texto extraído: %+v", processor.Falhas)
+	}
+}
+
+func TestProcessDocumentosPorTipo(t *testing.T) {
+	processor := newTestProcessor(t, 0)
+	processor.extract = extractDocumento
+
+	darf := &extraction.DarfData{PeriodoApuracao: "31/12/2024", CNPJ: "11.222.333/0001-81", CodigoReceita: "2089",
+		DataVencimento: "31/01/2025", ValorPrincipal: "1.000,00", ValorJuros: "10,50", ValorTotal: "1.010,50"}
+	darfInvalido := *darf
+	darfInvalido.CNPJ = "11.222.333/0001-80"
+	gnre := &extraction.GnreData{UFFavorecida: "SP", CodigoReceita: "100099", PeriodoReferencia: "12/2024",
+		ValorPrincipal: "500,00", ValorTotal: "500,00", NumeroControle: "2025010100000017"}
+
+	docs := []Documento{
+		{Nome: "darm.pdf", Extracao: &extraction.Extracao{Tipo: extraction.TipoDARM, Dados: darmtest.DarmData("0001.pdf")}},
+		{Nome: "darf.pdf", Extracao: &extraction.Extracao{Tipo: extraction.TipoDARF, Darf: darf, Pagina: 1, Hash: "hash-darf"}},
+		{Nome: "darf-invalido.pdf", Extracao: &extraction.Extracao{Tipo: extraction.TipoDARF, Darf: &darfInvalido}},
+		{Nome: "gnre.pdf", Extracao: &extraction.Extracao{Tipo: extraction.TipoGNRE, Gnre: gnre}},
+		{Nome: "boleto.pdf", Extracao: &extraction.Extracao{Tipo: extraction.TipoDesconhecido, Texto: "Boleto bancário"}},
+	}
+	if err := processor.ProcessDocumentos(context.Background(), docs); err != nil {
+		t.Fatalf("ProcessDocumentos falhou: %v", err)
+	}
+
+	// DARF e GNRE não geram INSERT em FarrDarmsPagos
+	if len(processor.Resultados) != 1 || len(processor.Documentos) != 2 || processor.Documentos[0].Tipo != extraction.TipoDARF {
+		t.Fatalf("resultados inesperados: %d DARM(s), documentos %+v", len(processor.Resultados), processor.Documentos)
+	}
+	erros := map[string]string{}
+	for _, falha := range processor.Falhas {
+		erros[falha.Arquivo] = falha.Erro
+	}
+	if erros["boleto.pdf"] != extraction.ErrTipoDesconhecido.Error() || !strings.Contains(erros["darf-invalido.pdf"], "DARF rejeitado") {
+		t.Errorf("falhas inesperadas: %v", erros)
+	}
+
+	darfs, err := os.ReadFile(filepath.Join(processor.OutputDir, output.DarfFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(darfs), "darf.pdf;1;hash-darf;31/12/2024;11222333000181;2089;;31/01/2025;1000.00;0.00;10.50;1010.50") {
+		t.Errorf("DARFs.csv inesperado:\n%s", darfs)
+	}
+	gnres, err := os.ReadFile(filepath.Join(processor.OutputDir, output.GnreFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(gnres), "gnre.pdf;;;SP;100099;;;12/2024;;500.00;500.00;2025010100000017") {
+		t.Errorf("GNREs.csv inesperado:\n%s", gnres)
+	}
+
+	relatorio := processor.Relatorio
+	if relatorio.PorTipo[extraction.TipoDARF] != 1 || relatorio.PorTipo[extraction.TipoGNRE] != 1 || relatorio.Validos != 3 || relatorio.ComErro != 2 {
+		t.Errorf("relatório inesperado: %+v", relatorio)
+	}
+	md, err := os.ReadFile(filepath.Join(processor.OutputDir, output.RelatorioBase+".md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(md), "Documentos por tipo: DARM 1, DARF 1, GNRE 1") || !strings.Contains(string(md), "DARF exportado em DARFs.csv") || !strings.Contains(string(md), "- **GNREs.csv**") {
+		t.Errorf("relatório Markdown inesperado:\n%s", md)
+	}
+}
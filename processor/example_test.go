@@ -0,0 +1,46 @@
+package processor_test
+
+import (
+	"context"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/processor"
+)
+
+// Processa os PDFs de ./darms e grava os scripts e o relatório em ./inserts
+func ExampleDarmProcessor_ProcessDarms() {
+	// 1. Criar processador
+	dp := processor.NewDarmProcessor()
+
+	// 2. Inicializar
+	if err := dp.Init(); err != nil {
+		logrus.Errorf("❌ Erro ao inicializar: %v", err)
+		return
+	}
+
+	// 3. Processar DARMs
+	if err := dp.ProcessDarms(context.Background()); err != nil {
+		logrus.Errorf("❌ Erro durante o processamento: %v", err)
+		return
+	}
+
+	// 4. Mostrar resultados
+	logrus.Infof("📊 Total de guias processadas: %d", len(dp.GuiasProcessadas))
+	for i, guia := range dp.GuiasProcessadas {
+		logrus.Infof("  %d. Guia %s", i+1, guia)
+	}
+
+	outputFiles, err := os.ReadDir(dp.OutputDir)
+	if err != nil {
+		logrus.Errorf("❌ Erro ao ler diretório de saída: %v", err)
+		return
+	}
+	logrus.Info("📄 Arquivos gerados:")
+	for _, file := range outputFiles {
+		if !file.IsDir() {
+			logrus.Infof("  - %s", file.Name())
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/internal/darmtest"
+	"gerador-query-darm-go/output"
+)
+
+func TestGenerateExports(t *testing.T) {
+	dp := newTestProcessor(t, 3)
+	dp.Config.Output.Formats = []string{"json", "jsonl", "csv"}
+	dp.extract = func(doc Documento) (*extraction.Extracao, error) {
+		if strings.HasSuffix(doc.Nome, "0002.pdf") {
+			return &extraction.Extracao{}, nil
+		}
+		return &extraction.Extracao{Dados: darmtest.DarmData(doc.Nome), Pagina: 1, Hash: "hash"}, nil
+	}
+
+	if err := dp.ProcessDarms(context.Background()); err != nil {
+		t.Fatalf("ProcessDarms falhou: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dp.OutputDir, "DARMs.json"))
+	if err != nil {
+		t.Fatalf("DARMs.json não gerado: %v", err)
+	}
+	var records []output.ExportRecord
+	if err := json.Unmarshal(content, &records); err != nil {
+		t.Fatalf("DARMs.json inválido: %v", err)
+	}
+
+	statuses := []string{}
+	for _, record := range records {
+		statuses = append(statuses, record.Arquivo+":"+record.Status)
+	}
+	if got := strings.Join(statuses, ","); got != "0001.pdf:valido,0002.pdf:erro,0003.pdf:valido" {
+		t.Errorf("registros exportados inesperados: %s", got)
+	}
+
+	for _, name := range []string{"DARMs.jsonl", "DARMs.csv"} {
+		if _, err := os.Stat(filepath.Join(dp.OutputDir, name)); err != nil {
+			t.Errorf("%s não gerado: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/internal/darmtest"
+)
+
+func TestProcessDarmsAppliesOverrides(t *testing.T) {
+	processor := newTestProcessor(t, 3)
+	processor.Config.Output.Formats = []string{"csv"}
+	processor.extract = func(doc Documento) (*extraction.Extracao, error) {
+		if doc.Nome == "0003.pdf" {
+			return &extraction.Extracao{Texto: "ilegível", Hash: "hash-0003"}, nil
+		}
+		return &extraction.Extracao{Dados: darmtest.DarmData(doc.Nome), Hash: "hash-" + doc.Nome}, nil
+	}
+
+	// 0001.pdf pelo nome; 0003.pdf (sem dados extraídos) preenchido integralmente
+	overridesPath := filepath.Join(processor.BaseDir, "correcoes.csv")
+	content := "chave;campo;valor;motivo\n0001.pdf;valorTotal;99,99;valor borrado\n"
+	for campo, valor := range map[string]string{"inscricao": "93", "codigoReceita": "2623", "valorPrincipal": "3,00", "valorTotal": "3,00", "numeroGuia": "3", "exercicio": "2025"} {
+		content += "0003.pdf;" + campo + ";" + valor + ";digitado\n"
+	}
+	content += "inexistente.pdf;exercicio;2024;\n"
+	if err := os.WriteFile(overridesPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	processor.Config.Paths.OverridesFile = overridesPath
+	if err := processor.Init(); err != nil {
+		t.Fatalf("Init falhou: %v", err)
+	}
+
+	// As correções valem em toda execução, inclusive no reprocessamento
+	for run := 1; run <= 2; run++ {
+		processor.Resultados, processor.Falhas = nil, nil
+		if err := processor.ProcessDarms(context.Background()); err != nil {
+			t.Fatalf("execução %d: ProcessDarms falhou: %v", run, err)
+		}
+		if len(processor.Resultados) != 3 || len(processor.Falhas) != 0 {
+			t.Fatalf("execução %d: esperados 3 resultados, obtidos %d (falhas: %+v)", run, len(processor.Resultados), processor.Falhas)
+		}
+
+		corrigido := processor.Resultados[0]
+		if corrigido.Dados.ValorTotal != "99,99" || !corrigido.Proveniencia.CorrigidoManualmente() || corrigido.Proveniencia.Correcoes[0].Anterior != "1.234,56" {
+			t.Errorf("execução %d: correção de 0001.pdf não registrada: %+v", run, corrigido.Proveniencia)
+		}
+		if processor.Resultados[1].Proveniencia.CorrigidoManualmente() {
+			t.Errorf("execução %d: 0002.pdf não deveria estar corrigido", run)
+		}
+		if processor.Resultados[2].Dados.NumeroGuia != "3" || len(processor.Resultados[2].Proveniencia.Correcoes) != 6 {
+			t.Errorf("execução %d: 0003.pdf deveria ser preenchido pelas correções: %+v", run, processor.Resultados[2].Dados)
+		}
+		if processor.Relatorio.Corrigidos != 2 {
+			t.Errorf("execução %d: relatório deveria contar 2 corrigidos, obtido %d", run, processor.Relatorio.Corrigidos)
+		}
+	}
+
+	sql, err := os.ReadFile(filepath.Join(processor.OutputDir, "INSERT_DARM_PAGO_1.sql"))
+	if err != nil || !strings.Contains(string(sql), "99.99") {
+		t.Errorf("INSERT de 0001.pdf deveria usar o valor corrigido: %s", sql)
+	}
+	markdown, err := os.ReadFile(filepath.Join(processor.OutputDir, "RELATORIO_PROCESSAMENTO.md"))
+	if err != nil || !strings.Contains(string(markdown), `corrigido manualmente: valorTotal: "1.234,56" → "99,99"`) || !strings.Contains(string(markdown), "- Corrigidos manualmente: 2 arquivo(s)") {
+		t.Errorf("relatório deveria registrar as correções manuais:\n%s", markdown)
+	}
+	csv, err := os.ReadFile(filepath.Join(processor.OutputDir, "DARMs.csv"))
+	if err != nil || !strings.Contains(string(csv), `valorTotal: ""1.234,56"" → ""99,99""`) {
+		t.Errorf("exportação CSV deveria registrar as correções:\n%s", csv)
+	}
+}
+
+func TestInitRejectsInvalidOverrides(t *testing.T) {
+	processor := newTestProcessor(t, 0)
+	processor.Config.Paths.OverridesFile = filepath.Join(processor.BaseDir, "inexistente.json")
+	if err := processor.Init(); err == nil {
+		t.Error("arquivo de correções inexistente deveria falhar")
+	}
+}
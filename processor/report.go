@@ -0,0 +1,69 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/output"
+	"gerador-query-darm-go/sqlgen"
+)
+
+// generatedFiles lista os arquivos de saída gerados nesta execução
+func (dp *DarmProcessor) generatedFiles() []string {
+	candidates := []string{}
+	if len(dp.Resultados) > 0 {
+		candidates = append(candidates, "INSERT_TODOS_DARMs.sql", "ROLLBACK_TODOS_DARMs.sql", "CHECK_GUIAS.sql")
+		if dp.Config.SQL.BulkLoad {
+			candidates = append(candidates, sqlgen.BulkScriptFile, sqlgen.BulkDataFile)
+		}
+	}
+	tipos := map[string]bool{}
+	for _, documento := range dp.Documentos {
+		tipos[documento.Tipo] = true
+	}
+	if tipos[extraction.TipoDARF] {
+		candidates = append(candidates, output.DarfFile)
+	}
+	if tipos[extraction.TipoGNRE] {
+		candidates = append(candidates, output.GnreFile)
+	}
+	for _, format := range dp.Config.Output.Formats {
+		if writer, err := output.NewOutputWriter(format, output.ExportOptions{}); err == nil {
+			candidates = append(candidates, "DARMs."+writer.Extension())
+		}
+	}
+
+	files := []string{}
+	for _, name := range candidates {
+		if _, err := os.Stat(filepath.Join(dp.OutputDir, name)); err == nil {
+			files = append(files, name)
+		}
+	}
+	if len(dp.Resultados) > 0 {
+		files = append(files, fmt.Sprintf("INSERT_DARM_PAGO_*.sql (%d arquivos)", len(dp.Resultados)))
+	}
+	return files
+}
+
+// generateReport gera o relatório da execução em Markdown, HTML e JSON
+func (dp *DarmProcessor) generateReport(arquivos []string, inicio time.Time) error {
+	opts, err := dp.Config.ScriptOptions()
+	if err != nil {
+		return err
+	}
+
+	dp.Relatorio = output.BuildRelatorio(output.Execucao{
+		Resultados:      dp.Resultados,
+		Documentos:      dp.Documentos,
+		Falhas:          dp.Falhas,
+		Arquivos:        arquivos,
+		Inicio:          inicio,
+		Script:          opts,
+		ModoValidacao:   dp.Config.ValidationMode(),
+		ArquivosGerados: dp.generatedFiles(),
+	})
+	return output.WriteRelatorio(dp.OutputDir, dp.Relatorio)
+}
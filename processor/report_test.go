@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/internal/darmtest"
+	"gerador-query-darm-go/output"
+	"gerador-query-darm-go/validation"
+)
+
+func TestGenerateReport(t *testing.T) {
+	processor := newTestProcessor(t, 4)
+	processor.extract = func(doc Documento) (*extraction.Extracao, error) {
+		data := darmtest.DarmData(doc.Nome)
+		hash := "hash-" + doc.Nome
+		switch doc.Nome {
+		case "0002.pdf":
+			data.CodigoReceita = ""
+			data.ValorPrincipal = "100,00"
+			data.ValorTotal = ""
+		case "0003.pdf":
+			return &extraction.Extracao{}, nil
+		case "0004.pdf":
+			data.NumeroGuia = "1"
+			data.DataVencimento = "<sem data>"
+			hash = "hash-0001.pdf"
+		}
+		return &extraction.Extracao{Dados: data, Pagina: 1, Hash: hash}, nil
+	}
+
+	if err := processor.ProcessDarms(context.Background()); err != nil {
+		t.Fatalf("ProcessDarms falhou: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(processor.OutputDir, "RELATORIO_PROCESSAMENTO.json"))
+	if err != nil {
+		t.Fatalf("relatório JSON não gerado: %v", err)
+	}
+	var relatorio struct {
+		Validos         int                       `json:"validos"`
+		ModoValidacao   string                    `json:"modoValidacao"`
+		ComAvisos       int                       `json:"comAvisos"`
+		ComErro         int                       `json:"comErro"`
+		Arquivos        []output.ArquivoRelatorio `json:"arquivos"`
+		CamposAusentes  map[string]int            `json:"camposAusentes"`
+		Total           json.RawMessage           `json:"total"`
+		PorReceita      []json.RawMessage         `json:"porReceita"`
+		PorVencimento   []json.RawMessage         `json:"porVencimento"`
+		GuiasDuplicadas []output.DuplicataRelatorio
+		PDFsDuplicados  []output.DuplicataRelatorio
+	}
+	if err := json.Unmarshal(content, &relatorio); err != nil {
+		t.Fatalf("relatório JSON inválido: %v", err)
+	}
+
+	if relatorio.Validos != 3 || relatorio.ComErro != 1 || len(relatorio.Arquivos) != 4 {
+		t.Errorf("contagens inesperadas: %d válidos, %d com erro, %d arquivos", relatorio.Validos, relatorio.ComErro, len(relatorio.Arquivos))
+	}
+	campos := []string{}
+	for _, aviso := range relatorio.Arquivos[1].Avisos {
+		campos = append(campos, aviso.Campo+" = "+aviso.Valor)
+	}
+	if padrao := strings.Join(campos, ", "); padrao != "VL_PAGO = 100.00, CD_RECEITA = 2585" {
+		t.Errorf("avisos de 0002.pdf inesperados: %q", padrao)
+	}
+	if relatorio.ComAvisos != 2 || relatorio.ModoValidacao != validation.ModeLenient {
+		t.Errorf("resumo de validação inesperado: %d arquivo(s) com avisos, modo %q", relatorio.ComAvisos, relatorio.ModoValidacao)
+	}
+	if relatorio.CamposAusentes["codigoReceita"] != 1 {
+		t.Errorf("codigoReceita deveria estar ausente em 1 arquivo: %v", relatorio.CamposAusentes)
+	}
+	if total := string(relatorio.Total); !strings.Contains(total, `"vlPrincipal": 2569.12`) || !strings.Contains(total, `"vlPago": 2569.12`) {
+		t.Errorf("total inesperado: %s", total)
+	}
+	if len(relatorio.PorReceita) != 2 || !strings.Contains(string(relatorio.PorReceita[0]), `"chave": "2585"`) {
+		t.Errorf("totais por receita inesperados: %s", relatorio.PorReceita)
+	}
+	if len(relatorio.PorVencimento) != 2 || !strings.Contains(string(relatorio.PorVencimento[1]), `"chave": "sem vencimento"`) {
+		t.Errorf("totais por vencimento inesperados: %s", relatorio.PorVencimento)
+	}
+	if len(relatorio.GuiasDuplicadas) != 1 || strings.Join(relatorio.GuiasDuplicadas[0].Arquivos, ",") != "0001.pdf,0004.pdf" {
+		t.Errorf("guia 1 deveria estar duplicada em 0001.pdf e 0004.pdf: %+v", relatorio.GuiasDuplicadas)
+	}
+	if len(relatorio.PDFsDuplicados) != 1 || relatorio.PDFsDuplicados[0].Chave != "hash-0001.pdf" {
+		t.Errorf("PDFs com o mesmo hash deveriam ser listados: %+v", relatorio.PDFsDuplicados)
+	}
+
+	markdown, err := os.ReadFile(filepath.Join(processor.OutputDir, "RELATORIO_PROCESSAMENTO.md"))
+	if err != nil {
+		t.Fatalf("relatório Markdown não gerado: %v", err)
+	}
+	for _, expected := range []string{
+		"| 3 | 0003.pdf | ❌ erro |  |",
+		"| 2585 | 1 | 100,00 | 100,00 |",
+		"| 15/12/2024 | 2 | 1.334,56 | 1.334,56 |",
+		"| **Total** | **3** | **2.569,12** | **2.569,12** |",
+		"- Guia 1 em: 0001.pdf, 0004.pdf",
+	} {
+		if !strings.Contains(string(markdown), expected) {
+			t.Errorf("relatório Markdown deveria conter %q:\n%s", expected, markdown)
+		}
+	}
+	if strings.Contains(string(markdown), "alternativo") {
+		t.Error("relatório não deveria conter texto fixo que não reflete a execução")
+	}
+
+	html, err := os.ReadFile(filepath.Join(processor.OutputDir, "RELATORIO_PROCESSAMENTO.html"))
+	if err != nil {
+		t.Fatalf("relatório HTML não gerado: %v", err)
+	}
+	if !strings.Contains(string(html), "<td>0004.pdf</td>") || !strings.Contains(string(html), "DT_VENCTO = NULL") {
+		t.Errorf("relatório HTML deveria listar os valores padrão:\n%s", html)
+	}
+}
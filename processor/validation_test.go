@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/internal/darmtest"
+	"gerador-query-darm-go/validation"
+)
+
+func TestProcessDarmsValidationModes(t *testing.T) {
+	for _, mode := range []string{validation.ModeLenient, validation.ModeStrict} {
+		processor := newTestProcessor(t, 2)
+		processor.Config.Validation.Mode = mode
+		processor.Config.Output.Formats = []string{"json"}
+		processor.extract = func(doc Documento) (*extraction.Extracao, error) {
+			data := darmtest.DarmData(doc.Nome)
+			if doc.Nome == "0002.pdf" {
+				data.CodigoReceita = ""
+			}
+			return &extraction.Extracao{Dados: data, Pagina: 1}, nil
+		}
+
+		if err := processor.ProcessDarms(context.Background()); err != nil {
+			t.Fatalf("%s: ProcessDarms falhou: %v", mode, err)
+		}
+
+		export, err := os.ReadFile(filepath.Join(processor.OutputDir, "DARMs.json"))
+		if err != nil {
+			t.Fatalf("%s: exportação JSON não gerada: %v", mode, err)
+		}
+
+		switch mode {
+		case validation.ModeLenient:
+			if len(processor.Resultados) != 2 || len(processor.Falhas) != 0 {
+				t.Fatalf("lenient: esperados 2 resultados, obtidos %d (%d falhas)", len(processor.Resultados), len(processor.Falhas))
+			}
+			if avisos := processor.Resultados[1].Registro.Substituicoes; len(avisos) != 1 || avisos[0].Campo != "CD_RECEITA" || avisos[0].Valor != "2585" {
+				t.Errorf("lenient: avisos de 0002.pdf inesperados: %v", avisos)
+			}
+			if !strings.Contains(string(export), `"campo": "CD_RECEITA"`) {
+				t.Errorf("lenient: exportação JSON deveria listar os avisos:\n%s", export)
+			}
+		case validation.ModeStrict:
+			if len(processor.Resultados) != 1 || len(processor.Falhas) != 1 {
+				t.Fatalf("strict: esperados 1 resultado e 1 falha, obtidos %d e %d", len(processor.Resultados), len(processor.Falhas))
+			}
+			if erro := processor.Falhas[0].Erro; !strings.Contains(erro, "validação estrita: CD_RECEITA = 2585") {
+				t.Errorf("strict: erro inesperado: %s", erro)
+			}
+			if !strings.Contains(string(export), `"status": "erro"`) {
+				t.Errorf("strict: exportação JSON deveria marcar 0002.pdf com erro:\n%s", export)
+			}
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package retorno
+
+import (
+	"fmt"
+
+	"gerador-query-darm-go/validation"
+)
+
+// Tamanho dos registros do CNAB 240
+const cnab240Size = 240
+
+// Tipos de registro do CNAB 240 (posição 8)
+const (
+	cnabHeaderArquivo  = "0"
+	cnabHeaderLote     = "1"
+	cnabDetalhe        = "3"
+	cnabTrailerLote    = "5"
+	cnabTrailerArquivo = "9"
+)
+
+// loteCNAB acumula os totais de um lote para conferir com o trailer do lote
+type loteCNAB struct {
+	numero    string
+	registros int
+	soma      validation.Money
+}
+
+// parseCNAB240 lê o CNAB 240 de pagamento de contas e tributos com código de
+// barras: segmento O (pagamento) seguido do segmento Z opcional (autenticação)
+func parseCNAB240(lines []line) (*Arquivo, error) {
+	arquivo := &Arquivo{Layout: LayoutCNAB240, Pagamentos: []Pagamento{}}
+	var lote *loteCNAB
+	var soma validation.Money
+	lotes := 0
+	trailer := false
+
+	for i, l := range lines {
+		r, err := newRecord(l, cnab240Size)
+		if err != nil {
+			return nil, err
+		}
+		if trailer {
+			return nil, fmt.Errorf("linha %d: registro após o trailer do arquivo", r.number)
+		}
+		if lote != nil {
+			lote.registros++
+		}
+
+		switch tipo := r.campo(8, 8); {
+		case i == 0:
+			if err := parseHeaderArquivo240(r, arquivo); err != nil {
+				return nil, err
+			}
+		case tipo == cnabHeaderLote:
+			if lote != nil {
+				return nil, fmt.Errorf("linha %d: lote %s sem trailer", r.number, lote.numero)
+			}
+			lote = &loteCNAB{numero: r.campo(4, 7), registros: 1}
+			lotes++
+		case tipo == cnabDetalhe:
+			if lote == nil {
+				return nil, fmt.Errorf("linha %d: detalhe fora de lote", r.number)
+			}
+			switch r.campo(14, 14) {
+			case "O":
+				pagamento, err := parseSegmentoO(r)
+				if err != nil {
+					return nil, err
+				}
+				lote.soma += pagamento.ValorPago
+				arquivo.Pagamentos = append(arquivo.Pagamentos, pagamento)
+			case "Z":
+				// Autenticação bancária do pagamento anterior
+				if n := len(arquivo.Pagamentos); n > 0 {
+					arquivo.Pagamentos[n-1].Autenticacao = r.campo(15, 78)
+				}
+			}
+		case tipo == cnabTrailerLote:
+			if lote == nil {
+				return nil, fmt.Errorf("linha %d: trailer de lote sem header", r.number)
+			}
+			if err := conferirLote240(r, lote); err != nil {
+				return nil, err
+			}
+			soma += lote.soma
+			lote = nil
+		case tipo == cnabTrailerArquivo:
+			if lote != nil {
+				return nil, fmt.Errorf("linha %d: lote %s sem trailer", r.number, lote.numero)
+			}
+			qtdLotes, err := r.numero(18, 23)
+			if err != nil {
+				return nil, err
+			}
+			qtdRegistros, err := r.numero(24, 29)
+			if err != nil {
+				return nil, err
+			}
+			if int(qtdLotes) != lotes {
+				return nil, divergencia("%d lotes no arquivo, %d no trailer", lotes, qtdLotes)
+			}
+			arquivo.TotalRegistros = int(qtdRegistros)
+			trailer = true
+		default:
+			return nil, fmt.Errorf("linha %d: tipo de registro desconhecido: %q", r.number, tipo)
+		}
+	}
+
+	if !trailer {
+		return nil, fmt.Errorf("trailer do arquivo não encontrado (arquivo incompleto)")
+	}
+	if arquivo.TotalRegistros != len(lines) {
+		return nil, divergencia("%d registros no arquivo, %d no trailer", len(lines), arquivo.TotalRegistros)
+	}
+	// O trailer do arquivo não traz valores: o total é a soma dos lotes conferidos
+	arquivo.ValorTotal = soma
+	return arquivo, nil
+}
+
+// parseHeaderArquivo240 lê o header do arquivo: banco, convênio, data de geração e NSA
+func parseHeaderArquivo240(r record, arquivo *Arquivo) error {
+	if remessa := r.campo(143, 143); remessa != "2" {
+		return fmt.Errorf("linha %d: código de remessa %q, esperado 2 (retorno)", r.number, remessa)
+	}
+
+	banco, err := r.numero(1, 3)
+	if err != nil {
+		return err
+	}
+	nsa, err := r.numero(158, 163)
+	if err != nil {
+		return err
+	}
+	if arquivo.DataGeracao, err = r.dataObrigatoria(144, 151, "02012006", "data de geração"); err != nil {
+		return err
+	}
+
+	arquivo.Banco = int(banco)
+	arquivo.Convenio = r.campo(33, 52)
+	arquivo.NomeBanco = r.campo(103, 132)
+	arquivo.NSA = int(nsa)
+	arquivo.Versao = r.campo(164, 166)
+	return nil
+}
+
+// parseSegmentoO lê o segmento O: pagamento de conta ou tributo com código de barras
+func parseSegmentoO(r record) (Pagamento, error) {
+	pagamento := Pagamento{
+		Linha:       r.number,
+		Ocorrencias: r.campo(231, 240),
+	}
+
+	var err error
+	if pagamento.CodigoBarras, err = r.codigoBarras(18, 61); err != nil {
+		return Pagamento{}, err
+	}
+	if pagamento.DataPagamento, err = r.dataObrigatoria(100, 107, "02012006", "data de pagamento"); err != nil {
+		return Pagamento{}, err
+	}
+	if pagamento.ValorPago, err = r.valor(108, 122); err != nil {
+		return Pagamento{}, err
+	}
+	return pagamento, nil
+}
+
+// conferirLote240 confere a quantidade de registros e a soma dos valores do lote
+func conferirLote240(r record, lote *loteCNAB) error {
+	registros, err := r.numero(18, 23)
+	if err != nil {
+		return err
+	}
+	soma, err := r.valor(24, 41)
+	if err != nil {
+		return err
+	}
+	if int(registros) != lote.registros {
+		return divergencia("lote %s com %d registros, %d no trailer do lote", lote.numero, lote.registros, registros)
+	}
+	if soma != lote.soma {
+		return divergencia("lote %s com soma R$ %s, trailer do lote R$ %s", lote.numero, lote.soma, soma)
+	}
+	return nil
+}
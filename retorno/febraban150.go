@@ -0,0 +1,123 @@
+package retorno
+
+import (
+	"fmt"
+
+	"gerador-query-darm-go/validation"
+)
+
+// Tamanho dos registros do layout FEBRABAN de arrecadação
+const febraban150Size = 150
+
+// parseFebraban150 lê o layout FEBRABAN de arrecadação com código de barras:
+// registro A (header), G (pagamento) e Z (trailer)
+func parseFebraban150(lines []line) (*Arquivo, error) {
+	arquivo := &Arquivo{Layout: LayoutFebraban150, Pagamentos: []Pagamento{}}
+	var soma validation.Money
+	trailer := false
+
+	for i, l := range lines {
+		r, err := newRecord(l, febraban150Size)
+		if err != nil {
+			return nil, err
+		}
+		if trailer {
+			return nil, fmt.Errorf("linha %d: registro após o trailer Z", r.number)
+		}
+
+		switch tipo := r.campo(1, 1); {
+		case i == 0:
+			if tipo != "A" {
+				return nil, fmt.Errorf("linha %d: header A esperado", r.number)
+			}
+			if err := parseHeaderA(r, arquivo); err != nil {
+				return nil, err
+			}
+		case tipo == "G":
+			pagamento, err := parseDetalheG(r)
+			if err != nil {
+				return nil, err
+			}
+			soma += pagamento.ValorPago
+			arquivo.Pagamentos = append(arquivo.Pagamentos, pagamento)
+		case tipo == "Z":
+			total, err := r.numero(2, 7)
+			if err != nil {
+				return nil, err
+			}
+			if arquivo.ValorTotal, err = r.valor(8, 24); err != nil {
+				return nil, err
+			}
+			arquivo.TotalRegistros = int(total)
+			trailer = true
+		case tipo == "A":
+			return nil, fmt.Errorf("linha %d: header A repetido", r.number)
+		default:
+			// Registros de outros serviços (ex.: débito automático) não trazem pagamentos
+		}
+	}
+
+	if !trailer {
+		return nil, fmt.Errorf("trailer Z não encontrado (arquivo incompleto)")
+	}
+	if arquivo.TotalRegistros != len(lines) {
+		return nil, divergencia("%d registros no arquivo, %d no trailer", len(lines), arquivo.TotalRegistros)
+	}
+	if soma != arquivo.ValorTotal {
+		return nil, divergencia("soma dos pagamentos R$ %s, trailer R$ %s", soma, arquivo.ValorTotal)
+	}
+	return arquivo, nil
+}
+
+// parseHeaderA lê o header do arquivo: convênio, banco, data de geração e NSA
+func parseHeaderA(r record, arquivo *Arquivo) error {
+	if remessa := r.campo(2, 2); remessa != "2" {
+		return fmt.Errorf("linha %d: código de remessa %q, esperado 2 (retorno)", r.number, remessa)
+	}
+
+	banco, err := r.numero(43, 45)
+	if err != nil {
+		return err
+	}
+	nsa, err := r.numero(74, 79)
+	if err != nil {
+		return err
+	}
+	if arquivo.DataGeracao, err = r.dataObrigatoria(66, 73, "20060102", "data de geração"); err != nil {
+		return err
+	}
+
+	arquivo.Convenio = r.campo(3, 22)
+	arquivo.Banco = int(banco)
+	arquivo.NomeBanco = r.campo(46, 65)
+	arquivo.NSA = int(nsa)
+	arquivo.Versao = r.campo(80, 81)
+	return nil
+}
+
+// parseDetalheG lê o registro G: pagamento com código de barras
+func parseDetalheG(r record) (Pagamento, error) {
+	pagamento := Pagamento{
+		Linha:        r.number,
+		Agencia:      r.campo(109, 116),
+		Autenticacao: r.campo(118, 140),
+	}
+
+	var err error
+	if pagamento.DataPagamento, err = r.dataObrigatoria(22, 29, "20060102", "data de pagamento"); err != nil {
+		return Pagamento{}, err
+	}
+	if pagamento.DataCredito, err = r.data(30, 37, "20060102"); err != nil {
+		return Pagamento{}, err
+	}
+	if pagamento.CodigoBarras, err = r.codigoBarras(38, 81); err != nil {
+		return Pagamento{}, err
+	}
+	if pagamento.ValorPago, err = r.valor(82, 93); err != nil {
+		return Pagamento{}, err
+	}
+	if pagamento.Tarifa, err = r.valor(94, 100); err != nil {
+		return Pagamento{}, err
+	}
+	return pagamento, nil
+}
@@ -0,0 +1,296 @@
+// Package retorno lê os arquivos de retorno de arrecadação enviados pelos
+// bancos (layout FEBRABAN de 150 posições e CNAB 240) e converte os
+// pagamentos em registros tipados, com o lote de arrecadação do cabeçalho e os
+// totais conferidos com o trailer.
+package retorno
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/validation"
+)
+
+// Layouts de arquivo de retorno suportados
+const (
+	// LayoutFebraban150 é o layout padrão de arrecadação com código de barras
+	// (registros A, G e Z de 150 posições)
+	LayoutFebraban150 = "febraban150"
+	// LayoutCNAB240 é o CNAB 240 de pagamento de contas e tributos (segmentos O e Z)
+	LayoutCNAB240 = "cnab240"
+)
+
+// ErrTotaisDivergentes indica que a quantidade de registros ou a soma dos
+// valores não confere com o trailer (arquivo truncado ou corrompido)
+var ErrTotaisDivergentes = errors.New("totais do arquivo não conferem com o trailer")
+
+// cleanDigitsRegex remove os caracteres não numéricos
+var cleanDigitsRegex = regexp.MustCompile(`\D`)
+
+// Pagamento é um DARM pago informado no arquivo de retorno
+type Pagamento struct {
+	// Linha é a linha do detalhe no arquivo
+	Linha int `json:"linha"`
+	// CodigoBarras são os 44 dígitos do código de barras lido no caixa
+	CodigoBarras string `json:"codigoBarras"`
+	// Guia é o número da guia extraído do código de barras (0 sem Options.GuiaTamanho)
+	Guia          int              `json:"guia,omitempty"`
+	ValorPago     validation.Money `json:"valorPago"`
+	Tarifa        validation.Money `json:"tarifa"`
+	DataPagamento time.Time        `json:"dataPagamento"`
+	DataCredito   *time.Time       `json:"dataCredito,omitempty"`
+	Autenticacao  string           `json:"autenticacao"`
+	Agencia       string           `json:"agencia,omitempty"`
+	// Ocorrencias são os códigos de ocorrência do CNAB 240 ("00" = efetivado)
+	Ocorrencias string `json:"ocorrencias,omitempty"`
+}
+
+// Efetivado indica se o banco confirmou o pagamento (sem ocorrência de rejeição)
+func (p Pagamento) Efetivado() bool {
+	ocorrencias := strings.TrimSpace(p.Ocorrencias)
+	return ocorrencias == "" || ocorrencias == "00"
+}
+
+// Arquivo é um arquivo de retorno lido e conferido
+type Arquivo struct {
+	Nome        string    `json:"nome"`
+	Layout      string    `json:"layout"`
+	Banco       int       `json:"banco"`
+	NomeBanco   string    `json:"nomeBanco"`
+	Convenio    string    `json:"convenio"`
+	DataGeracao time.Time `json:"dataGeracao"`
+	// NSA é o número sequencial do arquivo, gravado em NR_LOTE_NSA
+	NSA        int         `json:"nsa"`
+	Versao     string      `json:"versao"`
+	Pagamentos []Pagamento `json:"pagamentos"`
+	// TotalRegistros e ValorTotal são os totais informados no trailer
+	TotalRegistros int              `json:"totalRegistros"`
+	ValorTotal     validation.Money `json:"valorTotal"`
+
+	numeroBDA int
+}
+
+// Lote retorna o lote de arrecadação de FarrDarmsPagos do arquivo: banco e NSA
+// do cabeçalho; o BDA não consta do layout e vem de Options.NumeroBDA
+func (a *Arquivo) Lote() sqlgen.LoteArrecadacao {
+	lote := sqlgen.DefaultLote()
+	lote.CodigoBanco = a.Banco
+	lote.NSA = a.NSA
+	if a.numeroBDA > 0 {
+		lote.NumeroBDA = a.numeroBDA
+	}
+	return lote
+}
+
+// Options controla a interpretação dos pagamentos
+type Options struct {
+	// NumeroBDA é o boletim diário de arrecadação do lote (0 = o do lote padrão)
+	NumeroBDA int
+	// GuiaInicio e GuiaTamanho localizam o número da guia no código de barras
+	// (posição a partir de 1 nos 44 dígitos); GuiaTamanho 0 não extrai a guia
+	GuiaInicio  int
+	GuiaTamanho int
+}
+
+// Validate verifica se a posição da guia cabe no código de barras
+func (o Options) Validate() error {
+	if o.NumeroBDA < 0 {
+		return fmt.Errorf("número do BDA não pode ser negativo: %d", o.NumeroBDA)
+	}
+	if o.GuiaTamanho == 0 {
+		return nil
+	}
+	if o.GuiaInicio < 1 || o.GuiaTamanho < 0 || o.GuiaInicio+o.GuiaTamanho-1 > 44 {
+		return fmt.Errorf("posição da guia fora do código de barras: início %d, tamanho %d", o.GuiaInicio, o.GuiaTamanho)
+	}
+	return nil
+}
+
+// guia extrai o número da guia do código de barras
+func (o Options) guia(codigoBarras string) int {
+	if o.GuiaTamanho == 0 || len(codigoBarras) < o.GuiaInicio+o.GuiaTamanho-1 {
+		return 0
+	}
+	guia, _ := strconv.Atoi(codigoBarras[o.GuiaInicio-1 : o.GuiaInicio-1+o.GuiaTamanho])
+	return guia
+}
+
+// NormalizarCodigoBarras reduz o código de barras aos 44 dígitos lidos no
+// caixa; a linha digitável (48 dígitos, um DV a cada 12) perde os DVs dos blocos
+func NormalizarCodigoBarras(codigo string) string {
+	digits := cleanDigitsRegex.ReplaceAllString(codigo, "")
+	if len(digits) != 48 {
+		return digits
+	}
+	var b strings.Builder
+	for bloco := 0; bloco < 4; bloco++ {
+		b.WriteString(digits[bloco*12 : bloco*12+11])
+	}
+	return b.String()
+}
+
+// ReadFile lê e confere um arquivo de retorno, identificando o layout
+func ReadFile(path string, opts Options) (*Arquivo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de retorno: %v", err)
+	}
+	defer file.Close()
+
+	arquivo, err := Parse(file, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar %s: %w", filepath.Base(path), err)
+	}
+	arquivo.Nome = filepath.Base(path)
+	return arquivo, nil
+}
+
+// Parse lê um arquivo de retorno, identifica o layout pelo cabeçalho e
+// confere os totais do trailer (ErrTotaisDivergentes)
+func Parse(r io.Reader, opts Options) (*Arquivo, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("arquivo vazio")
+	}
+
+	var arquivo *Arquivo
+	header := lines[0].text
+	switch {
+	case len(header) >= 8 && header[3:8] == "00000":
+		// Header de arquivo CNAB 240: lote 0000, registro 0
+		arquivo, err = parseCNAB240(lines)
+	case strings.HasPrefix(header, "A"):
+		arquivo, err = parseFebraban150(lines)
+	case strings.HasPrefix(header, "02RETORNO"):
+		return nil, fmt.Errorf("layout CNAB 400 (cobrança) não suportado: use o retorno de arrecadação FEBRABAN (150 posições) ou CNAB 240")
+	default:
+		return nil, fmt.Errorf("layout não reconhecido na linha %d: %.20q", lines[0].number, header)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	arquivo.numeroBDA = opts.NumeroBDA
+	for i := range arquivo.Pagamentos {
+		arquivo.Pagamentos[i].Guia = opts.guia(arquivo.Pagamentos[i].CodigoBarras)
+	}
+	return arquivo, nil
+}
+
+// line é uma linha não vazia do arquivo, com o número original
+type line struct {
+	number int
+	text   string
+}
+
+// readLines lê as linhas não vazias, sem CR/LF e espaços finais
+func readLines(r io.Reader) ([]line, error) {
+	scanner := bufio.NewScanner(r)
+	lines := []line{}
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimRight(scanner.Text(), " \r\x1a")
+		if text == "" {
+			continue
+		}
+		lines = append(lines, line{number: number, text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// record é um registro de largura fixa, com os campos lidos pelas posições
+// do layout (a partir de 1, inclusivas); espaços finais removidos são repostos
+type record struct {
+	line
+	size int
+}
+
+// newRecord valida o tamanho da linha no layout
+func newRecord(l line, size int) (record, error) {
+	if len(l.text) > size {
+		return record{}, fmt.Errorf("linha %d: %d posições, esperadas %d", l.number, len(l.text), size)
+	}
+	return record{line: l, size: size}, nil
+}
+
+// campo retorna as posições ini a fim do registro, sem espaços nas pontas
+func (r record) campo(ini, fim int) string {
+	text := r.text + strings.Repeat(" ", r.size-len(r.text))
+	return strings.TrimSpace(text[ini-1 : fim])
+}
+
+// numero lê um campo numérico (vazio = 0)
+func (r record) numero(ini, fim int) (int64, error) {
+	value := r.campo(ini, fim)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("linha %d: campo numérico inválido nas posições %d-%d: %q", r.number, ini, fim, value)
+	}
+	return n, nil
+}
+
+// valor lê um valor com duas casas decimais implícitas
+func (r record) valor(ini, fim int) (validation.Money, error) {
+	n, err := r.numero(ini, fim)
+	return validation.Money(n), err
+}
+
+// data lê uma data no formato informado; zeros ou brancos retornam nil
+func (r record) data(ini, fim int, layout string) (*time.Time, error) {
+	value := r.campo(ini, fim)
+	if strings.Trim(value, "0") == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(layout, value)
+	if err != nil {
+		return nil, fmt.Errorf("linha %d: data inválida nas posições %d-%d: %q", r.number, ini, fim, value)
+	}
+	return &date, nil
+}
+
+// dataObrigatoria lê uma data que não pode estar em branco
+func (r record) dataObrigatoria(ini, fim int, layout, nome string) (time.Time, error) {
+	date, err := r.data(ini, fim, layout)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if date == nil {
+		return time.Time{}, fmt.Errorf("linha %d: %s em branco", r.number, nome)
+	}
+	return *date, nil
+}
+
+// codigoBarras valida os 44 dígitos do código de barras
+func (r record) codigoBarras(ini, fim int) (string, error) {
+	codigo := r.campo(ini, fim)
+	if len(codigo) != 44 || cleanDigitsRegex.MatchString(codigo) {
+		return "", fmt.Errorf("linha %d: código de barras inválido: %q", r.number, codigo)
+	}
+	return codigo, nil
+}
+
+// divergencia monta o erro de totais divergentes
+func divergencia(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrTotaisDivergentes, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,153 @@
+package retorno
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gerador-query-darm-go/internal/darmtest"
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/validation"
+)
+
+var pagamentosTeste = []darmtest.PagamentoRetorno{
+	{CodigoBarras: darmtest.CodigoBarras(2025001229, 123456), Centavos: 123456, Data: time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC), Autenticacao: "AUT0001"},
+	{CodigoBarras: darmtest.CodigoBarras(7, 1000), Centavos: 1000, Data: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), Autenticacao: "AUT0002"},
+}
+
+func TestParse(t *testing.T) {
+	layouts := map[string]string{
+		LayoutFebraban150: darmtest.RetornoFebraban150(104, 731, pagamentosTeste),
+		LayoutCNAB240:     darmtest.RetornoCNAB240(1, 42, pagamentosTeste),
+	}
+	for layout, content := range layouts {
+		arquivo, err := Parse(strings.NewReader(content), Options{NumeroBDA: 12, GuiaInicio: 20, GuiaTamanho: 10})
+		if err != nil {
+			t.Fatalf("%s: Parse falhou: %v", layout, err)
+		}
+		if arquivo.Layout != layout || arquivo.NomeBanco != "BANCO TESTE" || arquivo.Convenio != "CONVENIO123" {
+			t.Errorf("%s: cabeçalho inesperado: %+v", layout, arquivo)
+		}
+		if !arquivo.DataGeracao.Equal(time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("%s: data de geração inesperada: %v", layout, arquivo.DataGeracao)
+		}
+		if arquivo.ValorTotal != validation.Money(124456) || len(arquivo.Pagamentos) != 2 {
+			t.Fatalf("%s: totais inesperados: %s, %d pagamento(s)", layout, arquivo.ValorTotal, len(arquivo.Pagamentos))
+		}
+
+		p := arquivo.Pagamentos[0]
+		if p.CodigoBarras != pagamentosTeste[0].CodigoBarras || p.Guia != 2025001229 || p.ValorPago != validation.Money(123456) {
+			t.Errorf("%s: pagamento inesperado: %+v", layout, p)
+		}
+		if !p.DataPagamento.Equal(pagamentosTeste[0].Data) || p.Autenticacao != "AUT0001" || !p.Efetivado() {
+			t.Errorf("%s: pagamento inesperado: %+v", layout, p)
+		}
+		if arquivo.Pagamentos[1].Guia != 7 || arquivo.Pagamentos[1].Autenticacao != "AUT0002" {
+			t.Errorf("%s: segundo pagamento inesperado: %+v", layout, arquivo.Pagamentos[1])
+		}
+	}
+
+	arquivo, err := Parse(strings.NewReader(layouts[LayoutFebraban150]), Options{NumeroBDA: 12})
+	if err != nil {
+		t.Fatalf("Parse falhou: %v", err)
+	}
+	expected := sqlgen.LoteArrecadacao{CodigoBanco: 104, NumeroBDA: 12, Complemento: 0, NSA: 731, Tipo: 1}
+	if arquivo.Lote() != expected {
+		t.Errorf("lote inesperado: %+v", arquivo.Lote())
+	}
+	if arquivo.Pagamentos[0].Guia != 0 {
+		t.Errorf("sem posição da guia, a guia não deveria ser extraída: %d", arquivo.Pagamentos[0].Guia)
+	}
+	if dataCredito := arquivo.Pagamentos[0].DataCredito; dataCredito == nil || dataCredito.Day() != 10 {
+		t.Errorf("data de crédito inesperada: %v", dataCredito)
+	}
+
+	// Sem BDA configurado, o lote usa o BDA padrão
+	arquivo, err = Parse(strings.NewReader(layouts[LayoutCNAB240]), Options{})
+	if err != nil {
+		t.Fatalf("Parse falhou: %v", err)
+	}
+	if lote := arquivo.Lote(); lote.CodigoBanco != 1 || lote.NSA != 42 || lote.NumeroBDA != sqlgen.DefaultLote().NumeroBDA {
+		t.Errorf("lote inesperado: %+v", lote)
+	}
+}
+
+func TestParseTotaisDivergentes(t *testing.T) {
+	febraban := strings.Split(darmtest.RetornoFebraban150(104, 731, pagamentosTeste), "\r\n")
+	cnab := strings.Split(darmtest.RetornoCNAB240(1, 42, pagamentosTeste), "\n")
+
+	alterado := append([]string{}, febraban...)
+	alterado[2] = alterado[2][:81] + "000000002000" + alterado[2][93:]
+
+	cases := map[string]string{
+		// Pagamento removido: quantidade e soma divergem do trailer Z
+		"febraban sem detalhe": strings.Join(append(febraban[:1:1], febraban[2:]...), "\n"),
+		// Valor alterado: a soma diverge do trailer Z
+		"febraban valor": strings.Join(alterado, "\n"),
+		// Valor alterado: a soma diverge do trailer do lote
+		"cnab valor": strings.Replace(strings.Join(cnab, "\n"), "000000000001000", "000000000002000", 1),
+	}
+	for name, content := range cases {
+		_, err := Parse(strings.NewReader(content), Options{})
+		if !errors.Is(err, ErrTotaisDivergentes) {
+			t.Errorf("%s: esperado ErrTotaisDivergentes, obtido %v", name, err)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	febraban := darmtest.RetornoFebraban150(104, 731, pagamentosTeste)
+	lines := strings.Split(febraban, "\r\n")
+
+	invalid := map[string]string{
+		"vazio":          "\n\n",
+		"cnab400":        "02RETORNO01COBRANCA",
+		"desconhecido":   "XYZ",
+		"remessa":        "A1" + lines[0][2:],
+		"sem trailer":    strings.Join(lines[:len(lines)-2], "\n"),
+		"apos trailer":   febraban + lines[1],
+		"codigo barras":  strings.Replace(febraban, pagamentosTeste[0].CodigoBarras, "8160ABC", 1),
+		"linha longa":    lines[0] + "X",
+		"data pagamento": strings.Replace(febraban, "20250109", "20251309", 1),
+	}
+	for name, content := range invalid {
+		if _, err := Parse(strings.NewReader(content), Options{}); err == nil {
+			t.Errorf("%s: deveria falhar", name)
+		}
+	}
+
+	if _, err := Parse(strings.NewReader(febraban), Options{GuiaInicio: 40, GuiaTamanho: 10}); err == nil {
+		t.Error("posição da guia fora do código de barras deveria falhar")
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "RET0731.ret")
+	if err := os.WriteFile(path, []byte(darmtest.RetornoFebraban150(104, 731, pagamentosTeste)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	arquivo, err := ReadFile(path, Options{})
+	if err != nil {
+		t.Fatalf("ReadFile falhou: %v", err)
+	}
+	if arquivo.Nome != "RET0731.ret" || arquivo.NSA != 731 {
+		t.Errorf("arquivo inesperado: %+v", arquivo)
+	}
+	if _, err := ReadFile(filepath.Join(t.TempDir(), "inexistente.ret"), Options{}); err == nil {
+		t.Error("arquivo inexistente deveria falhar")
+	}
+}
+
+func TestNormalizarCodigoBarras(t *testing.T) {
+	codigo := darmtest.CodigoBarras(7, 1000)
+	linhaDigitavel := codigo[0:11] + "1 " + codigo[11:22] + "2." + codigo[22:33] + "3-" + codigo[33:44] + "4"
+	if got := NormalizarCodigoBarras(linhaDigitavel); got != codigo {
+		t.Errorf("linha digitável normalizada: %s, esperado %s", got, codigo)
+	}
+	if got := NormalizarCodigoBarras(codigo); got != codigo {
+		t.Errorf("código de barras deveria ser mantido: %s", got)
+	}
+}
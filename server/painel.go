@@ -0,0 +1,30 @@
+package server
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// PainelPath é o endereço do painel de revisão
+const PainelPath = "/ui/"
+
+// painelHTML é o painel de revisão, com CSS e JavaScript embutidos (sem
+// recursos externos)
+//
+//go:embed painel.html
+var painelHTML []byte
+
+// handlePainel serve o painel de revisão
+func (s *Server) handlePainel(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != PainelPath {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "método não permitido: %s", r.Method)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; style-src 'unsafe-inline'; script-src 'unsafe-inline'")
+	w.Write(painelHTML)
+}
@@ -0,0 +1,415 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/config"
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/output"
+	"gerador-query-darm-go/overrides"
+	"gerador-query-darm-go/processor"
+	"gerador-query-darm-go/validation"
+)
+
+// RevisaoPath é a raiz dos endpoints do painel de revisão
+const RevisaoPath = "/v1/revisao"
+
+// MotivoPainel é o motivo registrado nas correções feitas no painel
+const MotivoPainel = "corrigido no painel de revisão"
+
+// StatusNaoRevisavel indica um DARF ou uma GNRE: listados no painel, mas sem
+// correção nem aprovação (não geram INSERT em FarrDarmsPagos)
+const StatusNaoRevisavel = "nao_revisavel"
+
+// DocumentoRevisao é um documento da execução em revisão no painel
+type DocumentoRevisao struct {
+	Arquivo string `json:"arquivo"`
+	// Tipo é o tipo do documento (DARM, DARF ou GNRE; vazio se não reconhecido)
+	Tipo       string                    `json:"tipo,omitempty"`
+	Status     string                    `json:"status"`
+	Erro       string                    `json:"erro,omitempty"`
+	Avisos     []validation.Substituicao `json:"avisos,omitempty"`
+	Dados      extraction.DarmData       `json:"dados"`
+	Texto      string                    `json:"texto"`
+	Pagina     int                       `json:"pagina,omitempty"`
+	HashSHA256 string                    `json:"hashSha256,omitempty"`
+	Corrigido  bool                      `json:"corrigido"`
+	// Correcoes acumula as correções do arquivo de correções e do painel,
+	// sempre em relação ao valor extraído do PDF
+	Correcoes []overrides.Correcao `json:"correcoes,omitempty"`
+	Aprovado  bool                 `json:"aprovado"`
+}
+
+// validar revalida os dados do documento, atualizando status, erro e avisos
+func (d *DocumentoRevisao) validar(opts validation.Options) {
+	d.Erro, d.Avisos = "", nil
+	record, err := validation.Parse(&d.Dados, opts)
+	if err != nil {
+		d.Status, d.Erro = output.StatusErro, err.Error()
+		return
+	}
+	d.Status, d.Avisos = output.StatusValido, record.Substituicoes
+}
+
+// Revisao é a execução em revisão: os documentos extraídos do diretório
+// darms, corrigidos e aprovados pelo operador
+type Revisao struct {
+	mu         sync.Mutex
+	documentos []*DocumentoRevisao // Ordenados por arquivo
+	// processando serializa o processamento e a geração dos scripts
+	processando sync.Mutex
+}
+
+// Documentos retorna uma cópia dos documentos em revisão
+func (rv *Revisao) Documentos() []DocumentoRevisao {
+	rv.mu.Lock()
+	defer rv.mu.Unlock()
+
+	docs := make([]DocumentoRevisao, 0, len(rv.documentos))
+	for _, doc := range rv.documentos {
+		docs = append(docs, *doc)
+	}
+	return docs
+}
+
+// carregar substitui os documentos em revisão pelos resultados e falhas de
+// um processamento; falhas com texto extraído podem ser completadas no painel.
+// DARFs e GNREs são listados como não revisáveis.
+func (rv *Revisao) carregar(dp *processor.DarmProcessor) {
+	docs := []*DocumentoRevisao{}
+	for _, resultado := range dp.Resultados {
+		doc := &DocumentoRevisao{
+			Arquivo:    resultado.Arquivo,
+			Tipo:       extraction.TipoDARM,
+			Status:     output.StatusValido,
+			Avisos:     resultado.Registro.Substituicoes,
+			Dados:      *resultado.Dados,
+			Texto:      resultado.Texto,
+			Pagina:     resultado.Proveniencia.Pagina,
+			HashSHA256: resultado.Proveniencia.HashSHA256,
+			Corrigido:  resultado.Proveniencia.CorrigidoManualmente(),
+			Correcoes:  resultado.Proveniencia.Correcoes,
+		}
+		docs = append(docs, doc)
+	}
+	for _, falha := range dp.Falhas {
+		doc := &DocumentoRevisao{Arquivo: falha.Arquivo, Status: output.StatusErro, Erro: falha.Erro}
+		if extracao := falha.Extracao; extracao != nil {
+			doc.Tipo, doc.Texto, doc.Pagina, doc.HashSHA256 = extracao.Tipo, extracao.Texto, extracao.Pagina, extracao.Hash
+			if extracao.Dados != nil {
+				doc.Dados = *extracao.Dados
+			}
+		}
+		docs = append(docs, doc)
+	}
+	for _, documento := range dp.Documentos {
+		arquivo := output.DarfFile
+		if documento.Tipo == extraction.TipoGNRE {
+			arquivo = output.GnreFile
+		}
+		docs = append(docs, &DocumentoRevisao{
+			Arquivo:    documento.Arquivo,
+			Tipo:       documento.Tipo,
+			Status:     StatusNaoRevisavel,
+			Erro:       fmt.Sprintf("%s não passa pela revisão nem entra na geração dos aprovados; é exportado em %s pelo comando process ou por POST %s", documento.Tipo, arquivo, DarmsPath),
+			Pagina:     documento.Proveniencia.Pagina,
+			HashSHA256: documento.Proveniencia.HashSHA256,
+		})
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Arquivo < docs[j].Arquivo })
+
+	rv.mu.Lock()
+	rv.documentos = docs
+	rv.mu.Unlock()
+}
+
+// find retorna o documento em revisão pelo nome do arquivo (chamar com mu travado)
+func (rv *Revisao) find(arquivo string) *DocumentoRevisao {
+	for _, doc := range rv.documentos {
+		if doc.Arquivo == arquivo {
+			return doc
+		}
+	}
+	return nil
+}
+
+// corrigir aplica aos dados de um documento os campos presentes no corpo JSON
+// enviado pelo operador (os ausentes são mantidos), registra as correções e
+// revalida; a aprovação anterior é desfeita
+func (rv *Revisao) corrigir(arquivo string, corpo []byte, opts validation.Options) (*DocumentoRevisao, error) {
+	rv.mu.Lock()
+	defer rv.mu.Unlock()
+
+	doc := rv.find(arquivo)
+	if doc == nil {
+		return nil, errDocumentoNaoEncontrado
+	}
+	if doc.Status == StatusNaoRevisavel {
+		return nil, fmt.Errorf("documento %s não pode ser corrigido: %s", arquivo, doc.Erro)
+	}
+	dados := doc.Dados
+	if err := json.Unmarshal(corpo, &dados); err != nil {
+		return nil, fmt.Errorf("%w: %v", errDadosInvalidos, err)
+	}
+	if dados.NumeroGuia != doc.Dados.NumeroGuia {
+		// O número completo lido do PDF deixa de valer para conferir o Pix
+		dados.NumeroGuiaCompleto = ""
+	}
+	if dados != doc.Dados {
+		doc.Correcoes = mesclarCorrecoes(doc.Correcoes, overrides.Compare(&doc.Dados, &dados, MotivoPainel))
+		doc.Dados = dados
+		doc.Corrigido = len(doc.Correcoes) > 0
+	}
+	doc.Aprovado = false
+	doc.validar(opts)
+
+	copia := *doc
+	return &copia, nil
+}
+
+// mesclarCorrecoes acrescenta as novas correções às existentes: um campo já
+// corrigido mantém o valor extraído como anterior e deixa de ser corrigido
+// se voltar a ele
+func mesclarCorrecoes(existentes, novas []overrides.Correcao) []overrides.Correcao {
+	correcoes := append([]overrides.Correcao{}, existentes...)
+	for _, nova := range novas {
+		i := sort.Search(len(correcoes), func(i int) bool { return correcoes[i].Campo >= nova.Campo })
+		switch {
+		case i == len(correcoes) || correcoes[i].Campo != nova.Campo:
+			correcoes = append(correcoes[:i], append([]overrides.Correcao{nova}, correcoes[i:]...)...)
+		case correcoes[i].Anterior == nova.Valor:
+			correcoes = append(correcoes[:i], correcoes[i+1:]...)
+		default:
+			correcoes[i].Valor, correcoes[i].Motivo = nova.Valor, nova.Motivo
+		}
+	}
+	return correcoes
+}
+
+// aprovar marca um documento válido como aprovado para a geração dos scripts
+func (rv *Revisao) aprovar(arquivo string, aprovado bool) (*DocumentoRevisao, error) {
+	rv.mu.Lock()
+	defer rv.mu.Unlock()
+
+	doc := rv.find(arquivo)
+	if doc == nil {
+		return nil, errDocumentoNaoEncontrado
+	}
+	if doc.Status == StatusNaoRevisavel {
+		return nil, fmt.Errorf("documento %s não pode ser aprovado: %s", arquivo, doc.Erro)
+	}
+	if aprovado && doc.Status != output.StatusValido {
+		return nil, fmt.Errorf("documento %s com erro não pode ser aprovado: %s", arquivo, doc.Erro)
+	}
+	doc.Aprovado = aprovado
+
+	copia := *doc
+	return &copia, nil
+}
+
+// naoRevisaveis conta os DARFs e GNREs listados na revisão
+func (rv *Revisao) naoRevisaveis() int {
+	rv.mu.Lock()
+	defer rv.mu.Unlock()
+
+	count := 0
+	for _, doc := range rv.documentos {
+		if doc.Status == StatusNaoRevisavel {
+			count++
+		}
+	}
+	return count
+}
+
+// aprovados retorna os documentos aprovados como documentos já extraídos e
+// revisados: o arquivo de correções, aplicado na extração, não é reaplicado
+func (rv *Revisao) aprovados() []processor.Documento {
+	rv.mu.Lock()
+	defer rv.mu.Unlock()
+
+	docs := []processor.Documento{}
+	for _, doc := range rv.documentos {
+		if !doc.Aprovado {
+			continue
+		}
+		dados := doc.Dados
+		docs = append(docs, processor.Documento{
+			Nome: doc.Arquivo,
+			Extracao: &extraction.Extracao{
+				Dados:  &dados,
+				Texto:  doc.Texto,
+				Pagina: doc.Pagina,
+				Hash:   doc.HashSHA256,
+			},
+			Revisado:  true,
+			Correcoes: append([]overrides.Correcao{}, doc.Correcoes...),
+		})
+	}
+	return docs
+}
+
+// errDocumentoNaoEncontrado indica um arquivo ausente da execução em revisão
+var errDocumentoNaoEncontrado = errors.New("documento não encontrado na revisão")
+
+// errDadosInvalidos indica um corpo de correção que não é DarmData em JSON
+var errDadosInvalidos = errors.New("dados inválidos")
+
+// handleRevisao atende os endpoints do painel:
+//
+//	GET  /v1/revisao                              documentos em revisão
+//	POST /v1/revisao/processar                    extrai os PDFs do diretório darms
+//	PUT  /v1/revisao/documentos/{arquivo}         corrige os campos (corpo: DarmData)
+//	POST /v1/revisao/documentos/{arquivo}/aprovar aprova (ou desaprova com ?aprovado=false)
+//	POST /v1/revisao/gerar                        gera os scripts SQL dos aprovados
+//
+// As rotas que alteram a revisão passam por authorize.
+func (s *Server) handleRevisao(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.EscapedPath(), RevisaoPath), "/")
+	if r.Method != http.MethodGet && r.Method != http.MethodHead && !s.authorize(w, r) {
+		return
+	}
+
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"darmsDir":   s.DarmsDir,
+			"outputDir":  s.OutputDir,
+			"documentos": s.revisao.Documentos(),
+		})
+	case path == "/processar" && r.Method == http.MethodPost:
+		s.handleRevisaoProcessar(w, r)
+	case path == "/gerar" && r.Method == http.MethodPost:
+		s.handleRevisaoGerar(w, r)
+	case strings.HasPrefix(path, "/documentos/"):
+		s.handleRevisaoDocumento(w, r, strings.TrimPrefix(path, "/documentos/"))
+	case path == "" || path == "/processar" || path == "/gerar":
+		writeError(w, http.StatusMethodNotAllowed, "método não permitido: %s", r.Method)
+	default:
+		writeError(w, http.StatusNotFound, "rota não encontrada: %s", r.URL.Path)
+	}
+}
+
+// handleRevisaoDocumento corrige ou aprova um documento
+func (s *Server) handleRevisaoDocumento(w http.ResponseWriter, r *http.Request, path string) {
+	escaped, acao, _ := strings.Cut(path, "/")
+	arquivo, err := url.PathUnescape(escaped)
+	if err != nil || arquivo == "" {
+		writeError(w, http.StatusBadRequest, "nome de arquivo inválido: %q", escaped)
+		return
+	}
+
+	var doc *DocumentoRevisao
+	switch {
+	case acao == "" && r.Method == http.MethodPut:
+		corpo, lerErr := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+		if lerErr != nil {
+			writeError(w, http.StatusBadRequest, "dados inválidos: %v", lerErr)
+			return
+		}
+		doc, err = s.revisao.corrigir(arquivo, corpo, s.Config.ValidationOptions())
+	case acao == "aprovar" && r.Method == http.MethodPost:
+		doc, err = s.revisao.aprovar(arquivo, r.URL.Query().Get("aprovado") != "false")
+	case acao == "" || acao == "aprovar":
+		writeError(w, http.StatusMethodNotAllowed, "método não permitido: %s", r.Method)
+		return
+	default:
+		writeError(w, http.StatusNotFound, "rota não encontrada: %s", r.URL.Path)
+		return
+	}
+
+	if errors.Is(err, errDocumentoNaoEncontrado) {
+		writeError(w, http.StatusNotFound, "%s: %v", arquivo, err)
+		return
+	}
+	if errors.Is(err, errDadosInvalidos) {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusConflict, "%v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+// handleRevisaoProcessar extrai os PDFs do diretório darms para revisão, sem
+// gravar scripts em OutputDir (eles são gerados apenas a partir dos aprovados)
+func (s *Server) handleRevisaoProcessar(w http.ResponseWriter, r *http.Request) {
+	s.revisao.processando.Lock()
+	defer s.revisao.processando.Unlock()
+
+	docs, err := processor.ListDiretorio(s.DarmsDir)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+
+	tempDir, err := os.MkdirTemp("", "darm-revisao-")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "erro ao criar diretório temporário: %v", err)
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := s.revisaoConfig()
+	cfg.Output.Formats = nil
+	cfg.SQL.BulkLoad = false
+	dp, err := runProcessor(r.Context(), cfg, s.DarmsDir, tempDir, docs)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	s.revisao.carregar(dp)
+
+	logrus.Infof("🔎 Revisão carregada: %d documento(s) de %s", len(docs), s.DarmsDir)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"documentos": s.revisao.Documentos()})
+}
+
+// handleRevisaoGerar gera os scripts SQL, as exportações e o relatório em
+// OutputDir apenas com os documentos aprovados, usando os dados corrigidos
+func (s *Server) handleRevisaoGerar(w http.ResponseWriter, r *http.Request) {
+	s.revisao.processando.Lock()
+	defer s.revisao.processando.Unlock()
+
+	docs := s.revisao.aprovados()
+	if len(docs) == 0 {
+		writeError(w, http.StatusConflict, "nenhum documento aprovado")
+		return
+	}
+
+	dp, err := runProcessor(context.WithoutCancel(r.Context()), s.revisaoConfig(), s.DarmsDir, s.OutputDir, docs)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+
+	resposta, err := newResposta(dp)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	logrus.Infof("✅ Scripts gerados a partir de %d documento(s) aprovado(s) em %s", len(docs), filepath.Base(s.OutputDir))
+	if n := s.revisao.naoRevisaveis(); n > 0 {
+		logrus.Warnf("⚠️ %d DARF(s)/GNRE(s) em revisão não entram na geração dos aprovados", n)
+	}
+	writeJSON(w, http.StatusOK, resposta)
+}
+
+// revisaoConfig retorna uma cópia da configuração do servidor para a revisão
+func (s *Server) revisaoConfig() *config.Config {
+	cfg := *s.Config
+	return &cfg
+}
@@ -0,0 +1,361 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/internal/darmtest"
+	"gerador-query-darm-go/output"
+	"gerador-query-darm-go/overrides"
+	"gerador-query-darm-go/processor"
+)
+
+// newRevisaoServer cria um servidor com a revisão carregada a partir de
+// documentos já extraídos: 0001.pdf válido e ilegivel.pdf sem dados
+func newRevisaoServer(t *testing.T) *Server {
+	t.Helper()
+	server := newTestServer()
+	tempDir := t.TempDir()
+	server.DarmsDir = filepath.Join(tempDir, "darms")
+	server.OutputDir = filepath.Join(tempDir, "inserts")
+
+	docs := []processor.Documento{
+		{Nome: "0001.pdf", Extracao: &extraction.Extracao{Dados: darmtest.DarmData("0001.pdf"), Texto: "texto 0001", Hash: "hash"}},
+		{Nome: "ilegivel.pdf", Extracao: &extraction.Extracao{Texto: "texto ilegível"}},
+	}
+	dp, err := runProcessor(context.Background(), server.Config, server.DarmsDir, filepath.Join(tempDir, "revisao"), docs)
+	if err != nil {
+		t.Fatalf("runProcessor falhou: %v", err)
+	}
+	server.revisao.carregar(dp)
+	return server
+}
+
+func serve(handler http.Handler, method, target string, body interface{}) *httptest.ResponseRecorder {
+	var content bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&content).Encode(body)
+	}
+	req := httptest.NewRequest(method, target, &content)
+	req.RemoteAddr = "127.0.0.1:50000"
+	req.Host = "localhost:8080"
+	req.Header.Set(PainelHeader, "1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRevisaoCorrigirAprovarGerar(t *testing.T) {
+	server := newRevisaoServer(t)
+	handler := server.Handler()
+
+	docs := server.revisao.Documentos()
+	if len(docs) != 2 || docs[0].Status != output.StatusValido || docs[1].Status != output.StatusErro || docs[1].Texto != "texto ilegível" {
+		t.Fatalf("revisão carregada incorretamente: %+v", docs)
+	}
+
+	if rec := serve(handler, http.MethodPost, RevisaoPath+"/gerar", nil); rec.Code != http.StatusConflict {
+		t.Errorf("gerar sem aprovados deveria falhar, obtido %d", rec.Code)
+	}
+	if rec := serve(handler, http.MethodPost, RevisaoPath+"/documentos/ilegivel.pdf/aprovar", nil); rec.Code != http.StatusConflict {
+		t.Errorf("documento com erro não deveria ser aprovado, obtido %d", rec.Code)
+	}
+
+	// Completar os campos do documento ilegível e aprovar os dois
+	dados := *darmtest.DarmData("0002.pdf")
+	dados.ValorTotal = "77,70"
+	rec := serve(handler, http.MethodPut, RevisaoPath+"/documentos/ilegivel.pdf", dados)
+	var doc DocumentoRevisao
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("correção falhou: %d %s", rec.Code, rec.Body)
+	}
+	if doc.Status != output.StatusValido || !doc.Corrigido || doc.Aprovado {
+		t.Errorf("documento corrigido inesperado: %+v", doc)
+	}
+	for _, arquivo := range []string{"0001.pdf", "ilegivel.pdf"} {
+		if rec := serve(handler, http.MethodPost, RevisaoPath+"/documentos/"+arquivo+"/aprovar", nil); rec.Code != http.StatusOK {
+			t.Fatalf("aprovação de %s falhou: %d %s", arquivo, rec.Code, rec.Body)
+		}
+	}
+
+	rec = serve(handler, http.MethodPost, RevisaoPath+"/gerar", nil)
+	var resposta Resposta
+	if err := json.Unmarshal(rec.Body.Bytes(), &resposta); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("gerar falhou: %d %s", rec.Code, rec.Body)
+	}
+	if resposta.Relatorio.Validos != 2 || !strings.Contains(resposta.SQL, "77.70") {
+		t.Errorf("scripts deveriam usar os dados corrigidos: %s", resposta.SQL)
+	}
+	if _, err := os.Stat(filepath.Join(server.OutputDir, "INSERT_TODOS_DARMs.sql")); err != nil {
+		t.Errorf("INSERT_TODOS_DARMs.sql deveria ser gravado em OutputDir: %v", err)
+	}
+
+	// Uma nova correção desfaz a aprovação
+	dados.ValorTotal = "abc"
+	rec = serve(handler, http.MethodPut, RevisaoPath+"/documentos/ilegivel.pdf", dados)
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil || doc.Aprovado {
+		t.Errorf("correção deveria desfazer a aprovação: %+v", doc)
+	}
+}
+
+func TestRevisaoPreservaCorrecoesDoPainel(t *testing.T) {
+	server := newTestServer()
+	tempDir := t.TempDir()
+	server.DarmsDir = filepath.Join(tempDir, "darms")
+	server.OutputDir = filepath.Join(tempDir, "inserts")
+	server.Config.Paths.OverridesFile = filepath.Join(tempDir, "correcoes.csv")
+	content := "chave;campo;valor;motivo\n0001.pdf;valorTotal;99,99;valor borrado\n0001.pdf;exercicio;2024;ano errado\n"
+	if err := os.WriteFile(server.Config.Paths.OverridesFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	docs := []processor.Documento{{Nome: "0001.pdf", Extracao: &extraction.Extracao{Dados: darmtest.DarmData("0001.pdf"), Hash: "hash"}}}
+	dp, err := runProcessor(context.Background(), server.Config, server.DarmsDir, filepath.Join(tempDir, "revisao"), docs)
+	if err != nil {
+		t.Fatalf("runProcessor falhou: %v", err)
+	}
+	server.revisao.carregar(dp)
+	handler := server.Handler()
+
+	// O operador corrige de novo o valor já corrigido pelo arquivo e devolve o exercício ao extraído
+	dados := server.revisao.Documentos()[0].Dados
+	dados.ValorTotal, dados.Exercicio = "88,88", "2025"
+	rec := serve(handler, http.MethodPut, RevisaoPath+"/documentos/0001.pdf", dados)
+	var doc DocumentoRevisao
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("correção falhou: %d %s", rec.Code, rec.Body)
+	}
+	want := []overrides.Correcao{{Campo: "valorTotal", Anterior: "1.234,56", Valor: "88,88", Motivo: MotivoPainel}}
+	if !reflect.DeepEqual(doc.Correcoes, want) || !doc.Corrigido {
+		t.Errorf("correções do painel inesperadas: %+v", doc.Correcoes)
+	}
+
+	if rec := serve(handler, http.MethodPost, RevisaoPath+"/documentos/0001.pdf/aprovar", nil); rec.Code != http.StatusOK {
+		t.Fatalf("aprovação falhou: %d %s", rec.Code, rec.Body)
+	}
+	rec = serve(handler, http.MethodPost, RevisaoPath+"/gerar", nil)
+	var resposta Resposta
+	if err := json.Unmarshal(rec.Body.Bytes(), &resposta); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("gerar falhou: %d %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(resposta.SQL, "88.88") || strings.Contains(resposta.SQL, "99.99") || resposta.Documentos[0].Registro.Exercicio != 2025 {
+		t.Errorf("arquivo de correções não deveria sobrescrever o painel: %s", resposta.SQL)
+	}
+	if got := resposta.Documentos[0].Correcoes; !reflect.DeepEqual(got, want) || resposta.Relatorio.Corrigidos != 1 {
+		t.Errorf("correções do painel deveriam chegar à proveniência: %+v", got)
+	}
+}
+
+func TestRevisaoFormularioSemAlteracoes(t *testing.T) {
+	server := newTestServer()
+	tempDir := t.TempDir()
+	server.DarmsDir = filepath.Join(tempDir, "darms")
+	server.OutputDir = filepath.Join(tempDir, "inserts")
+
+	dados := darmtest.DarmData("0001.pdf")
+	dados.NumeroGuia, dados.NumeroGuiaCompleto = "123", "123456789"
+	dados.PixCopiaECola = darmtest.PixBRCode("pix@rio.rj.gov.br", "1234.56", "DARM123456789")
+	docs := []processor.Documento{{Nome: "0001.pdf", Extracao: &extraction.Extracao{Dados: dados, Hash: "hash"}}}
+	dp, err := runProcessor(context.Background(), server.Config, server.DarmsDir, filepath.Join(tempDir, "revisao"), docs)
+	if err != nil {
+		t.Fatalf("runProcessor falhou: %v", err)
+	}
+	server.revisao.carregar(dp)
+
+	// O formulário antigo do painel envia apenas os campos exibidos
+	formulario := map[string]string{
+		"inscricao": dados.Inscricao, "codigoReceita": dados.CodigoReceita, "valorPrincipal": dados.ValorPrincipal,
+		"valorTotal": dados.ValorTotal, "dataVencimento": dados.DataVencimento, "exercicio": dados.Exercicio,
+		"numeroGuia": dados.NumeroGuia, "competencia": dados.Competencia, "codigoBarras": dados.CodigoBarras,
+	}
+	rec := serve(server.Handler(), http.MethodPut, RevisaoPath+"/documentos/0001.pdf", formulario)
+	var doc DocumentoRevisao
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("correção falhou: %d %s", rec.Code, rec.Body)
+	}
+	if doc.Corrigido || len(doc.Correcoes) != 0 {
+		t.Errorf("formulário sem alterações não deveria gerar correções: %+v", doc.Correcoes)
+	}
+	if doc.Dados != *dados || doc.Status != output.StatusValido || len(doc.Avisos) != 0 {
+		t.Errorf("Pix e número completo da guia deveriam ser mantidos: %+v %+v", doc.Dados, doc.Avisos)
+	}
+}
+
+func TestRevisaoListaDarfGnre(t *testing.T) {
+	server := newTestServer()
+	tempDir := t.TempDir()
+	server.DarmsDir = filepath.Join(tempDir, "darms")
+	server.OutputDir = filepath.Join(tempDir, "inserts")
+
+	darf := &extraction.DarfData{PeriodoApuracao: "31/12/2024", CNPJ: "11.222.333/0001-81", CodigoReceita: "2089",
+		DataVencimento: "31/01/2025", ValorPrincipal: "1.000,00", ValorTotal: "1.000,00"}
+	docs := []processor.Documento{
+		{Nome: "0001.pdf", Extracao: &extraction.Extracao{Tipo: extraction.TipoDARM, Dados: darmtest.DarmData("0001.pdf")}},
+		{Nome: "darf.pdf", Extracao: &extraction.Extracao{Tipo: extraction.TipoDARF, Darf: darf, Hash: "hash-darf"}},
+	}
+	dp, err := runProcessor(context.Background(), server.Config, server.DarmsDir, filepath.Join(tempDir, "revisao"), docs)
+	if err != nil {
+		t.Fatalf("runProcessor falhou: %v", err)
+	}
+	server.revisao.carregar(dp)
+	handler := server.Handler()
+
+	documentos := server.revisao.Documentos()
+	if len(documentos) != 2 || documentos[0].Tipo != extraction.TipoDARM || documentos[1].Arquivo != "darf.pdf" {
+		t.Fatalf("documentos em revisão inesperados: %+v", documentos)
+	}
+	if darfDoc := documentos[1]; darfDoc.Tipo != extraction.TipoDARF || darfDoc.Status != StatusNaoRevisavel ||
+		!strings.Contains(darfDoc.Erro, output.DarfFile) || darfDoc.HashSHA256 != "hash-darf" {
+		t.Errorf("DARF deveria ser listado como não revisável: %+v", darfDoc)
+	}
+
+	if rec := serve(handler, http.MethodPost, RevisaoPath+"/documentos/darf.pdf/aprovar", nil); rec.Code != http.StatusConflict {
+		t.Errorf("aprovar DARF: status esperado 409, obtido %d (%s)", rec.Code, rec.Body)
+	}
+	if rec := serve(handler, http.MethodPut, RevisaoPath+"/documentos/darf.pdf", map[string]string{"valorTotal": "1,00"}); rec.Code != http.StatusConflict {
+		t.Errorf("corrigir DARF: status esperado 409, obtido %d (%s)", rec.Code, rec.Body)
+	}
+}
+
+func TestRevisaoRotas(t *testing.T) {
+	handler := newRevisaoServer(t).Handler()
+
+	tests := map[string]struct {
+		method, target string
+		status         int
+	}{
+		"listar":              {http.MethodGet, RevisaoPath, http.StatusOK},
+		"método":              {http.MethodDelete, RevisaoPath, http.StatusMethodNotAllowed},
+		"documento ausente":   {http.MethodPost, RevisaoPath + "/documentos/x.pdf/aprovar", http.StatusNotFound},
+		"ação desconhecida":   {http.MethodPost, RevisaoPath + "/documentos/0001.pdf/apagar", http.StatusNotFound},
+		"rota desconhecida":   {http.MethodGet, RevisaoPath + "/outra", http.StatusNotFound},
+		"corpo inválido":      {http.MethodPut, RevisaoPath + "/documentos/0001.pdf", http.StatusBadRequest},
+		"painel":              {http.MethodGet, PainelPath, http.StatusOK},
+		"raiz":                {http.MethodGet, "/", http.StatusFound},
+		"arquivo inexistente": {http.MethodGet, "/nada", http.StatusNotFound},
+	}
+	for name, tt := range tests {
+		rec := serve(handler, tt.method, tt.target, nil)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status esperado %d, obtido %d (%s)", name, tt.status, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestRevisaoAutorizacao(t *testing.T) {
+	server := newRevisaoServer(t)
+	handler := server.Handler()
+	aprovar := RevisaoPath + "/documentos/0001.pdf/aprovar"
+
+	request := func(remoteAddr, authorization string) int {
+		req := httptest.NewRequest(http.MethodPost, aprovar, nil)
+		req.RemoteAddr = remoteAddr
+		req.Host = "127.0.0.1:8080"
+		req.Header.Set(PainelHeader, "1")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// sem token: apenas a máquina local altera a revisão; a leitura continua aberta
+	if code := request("192.0.2.10:50000", ""); code != http.StatusForbidden {
+		t.Errorf("acesso remoto sem token: status esperado 403, obtido %d", code)
+	}
+	if code := request("[::1]:50000", ""); code != http.StatusOK {
+		t.Errorf("acesso local sem token: status esperado 200, obtido %d", code)
+	}
+	req := httptest.NewRequest(http.MethodGet, RevisaoPath, nil)
+	req.RemoteAddr = "192.0.2.10:50000"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("leitura remota: status esperado 200, obtido %d", rec.Code)
+	}
+
+	// formulário de outro site aberto no navegador local (CSRF): sem o
+	// cabeçalho do painel nem corpo JSON, a alteração é recusada
+	for _, target := range []string{aprovar, RevisaoPath + "/gerar", RevisaoPath + "/processar"} {
+		req = httptest.NewRequest(http.MethodPost, target, strings.NewReader("a=1"))
+		req.RemoteAddr = "127.0.0.1:50000"
+		req.Host = "localhost:8080"
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("formulário local em %s: status esperado 403, obtido %d", target, rec.Code)
+		}
+	}
+	req = httptest.NewRequest(http.MethodPost, aprovar, strings.NewReader("{}"))
+	req.RemoteAddr = "127.0.0.1:50000"
+	req.Host = "[::1]:8080"
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("POST JSON local: status esperado 200, obtido %d (%s)", rec.Code, rec.Body)
+	}
+
+	// DNS rebinding: a página de outro domínio resolvido para 127.0.0.1 envia
+	// o cabeçalho do painel, mas o Host é o domínio dela
+	for _, host := range []string{"rebind.example.com:8080", "rebind.example.com", "localhost.example.com"} {
+		req = httptest.NewRequest(http.MethodPost, aprovar, nil)
+		req.RemoteAddr = "127.0.0.1:50000"
+		req.Host = host
+		req.Header.Set(PainelHeader, "1")
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Host %s: status esperado 403, obtido %d", host, rec.Code)
+		}
+	}
+
+	// com token: exigido inclusive da máquina local
+	server.Config.Server.Token = "segredo"
+	tests := map[string]struct {
+		remoteAddr, authorization string
+		status                    int
+	}{
+		"local sem token":  {"127.0.0.1:50000", "", http.StatusUnauthorized},
+		"token inválido":   {"192.0.2.10:50000", "Bearer outro", http.StatusUnauthorized},
+		"esquema inválido": {"192.0.2.10:50000", "segredo", http.StatusUnauthorized},
+		"token válido":     {"192.0.2.10:50000", "Bearer segredo", http.StatusOK},
+	}
+	for name, tt := range tests {
+		if code := request(tt.remoteAddr, tt.authorization); code != tt.status {
+			t.Errorf("%s: status esperado %d, obtido %d", name, tt.status, code)
+		}
+	}
+}
+
+func TestRevisaoProcessar(t *testing.T) {
+	server := newTestServer()
+	server.DarmsDir = t.TempDir()
+	server.OutputDir = filepath.Join(t.TempDir(), "inserts")
+	if err := os.WriteFile(filepath.Join(server.DarmsDir, "corrompido.pdf"), []byte("não é um PDF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serve(server.Handler(), http.MethodPost, RevisaoPath+"/processar", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("processar falhou: %d %s", rec.Code, rec.Body)
+	}
+	docs := server.revisao.Documentos()
+	if len(docs) != 1 || docs[0].Arquivo != "corrompido.pdf" || docs[0].Status != output.StatusErro {
+		t.Errorf("documentos inesperados: %+v", docs)
+	}
+	if _, err := os.Stat(filepath.Join(server.OutputDir, "RELATORIO_PROCESSAMENTO.md")); !os.IsNotExist(err) {
+		t.Error("a extração para revisão não deveria gravar saídas em OutputDir")
+	}
+}
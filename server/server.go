@@ -0,0 +1,375 @@
+// Package server expõe o processamento de DARMs como API HTTP: recebe PDFs por
+// upload e devolve os dados extraídos, o SQL e o relatório da requisição, e
+// serve o painel de revisão, em que um operador corrige e aprova as extrações
+// antes de gerar os scripts SQL.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/config"
+	"gerador-query-darm-go/output"
+	"gerador-query-darm-go/processor"
+	"gerador-query-darm-go/validation"
+)
+
+// HealthPath é o endpoint de verificação de saúde da API
+const HealthPath = "/healthz"
+
+// DarmsPath é o endpoint de upload e processamento de PDFs
+const DarmsPath = "/v1/darms"
+
+// PainelHeader é o cabeçalho enviado pelo painel nas alterações da revisão.
+// Um formulário de outro site não consegue enviá-lo sem preflight CORS, que
+// o servidor não autoriza.
+const PainelHeader = "X-Darm-Painel"
+
+// Resposta é o corpo devolvido por POST /v1/darms
+type Resposta struct {
+	// Documentos traz, para cada PDF, os dados extraídos, o registro validado,
+	// os avisos de validação ou o erro
+	Documentos []output.ExportRecord `json:"documentos"`
+	Dialeto    string                `json:"dialeto"`
+	// SQL é o INSERT_TODOS_DARMs.sql das guias válidas (vazio se nenhuma)
+	SQL       string            `json:"sql"`
+	Relatorio *output.Relatorio `json:"relatorio"`
+}
+
+// Server atende a API HTTP com a configuração informada
+type Server struct {
+	Config  *config.Config
+	Version string
+	// DarmsDir e OutputDir são os diretórios da execução revisada no painel
+	DarmsDir  string
+	OutputDir string
+
+	revisao *Revisao
+}
+
+// New cria o servidor da API, com os diretórios de cfg.Paths
+func New(cfg *config.Config, version string) *Server {
+	return &Server{
+		Config:    cfg,
+		Version:   version,
+		DarmsDir:  cfg.DarmsDir(),
+		OutputDir: cfg.OutputDir(),
+		revisao:   &Revisao{},
+	}
+}
+
+// Handler retorna as rotas da API e do painel de revisão
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthPath, s.handleHealth)
+	mux.HandleFunc(DarmsPath, s.handleDarms)
+	mux.HandleFunc(RevisaoPath, s.handleRevisao)
+	mux.HandleFunc(RevisaoPath+"/", s.handleRevisao)
+	mux.HandleFunc(PainelPath, s.handlePainel)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Redirect(w, r, PainelPath, http.StatusFound)
+	})
+	return mux
+}
+
+// authorize protege as rotas que alteram a revisão: com Config.Server.Token
+// configurado exige o cabeçalho "Authorization: Bearer <token>"; sem token,
+// aceita apenas requisições da própria máquina (loopback), endereçadas a
+// localhost (barra páginas de DNS rebinding, cujo Host é o domínio delas) e
+// que tragam PainelHeader ou corpo JSON, barrando formulários enviados por
+// outros sites abertos no navegador local (CSRF)
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if token := s.Config.Server.Token; token != "" {
+		informado, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(informado), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "token de acesso ausente ou inválido")
+			return false
+		}
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+		writeError(w, http.StatusForbidden, "alterações na revisão exigem server.token quando acessadas de fora da máquina local")
+		return false
+	}
+	if !localHost(r.Host) {
+		writeError(w, http.StatusForbidden, "alterações na revisão sem server.token exigem o acesso por localhost, 127.0.0.1 ou [::1]")
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if r.Header.Get(PainelHeader) == "" && mediaType != "application/json" {
+		writeError(w, http.StatusForbidden, "alterações na revisão exigem o cabeçalho "+PainelHeader+" ou corpo application/json")
+		return false
+	}
+	return true
+}
+
+// localHost indica se o cabeçalho Host (com ou sem porta) nomeia a própria máquina
+func localHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = strings.TrimSuffix(strings.TrimPrefix(hostport, "["), "]")
+	}
+	switch strings.ToLower(host) {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
+
+// ListenAndServe atende a API em Config.Server.Addr até o cancelamento do
+// contexto, aguardando as requisições em andamento no encerramento
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	httpServer := &http.Server{
+		Addr:              s.Config.Server.Addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		logrus.Infof("🌐 API HTTP escutando em %s", s.Config.Server.Addr)
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("erro ao iniciar API HTTP: %v", err)
+	case <-ctx.Done():
+	}
+
+	logrus.Info("🛑 Encerrando API HTTP...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.Config.RequestTimeout()+5*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("erro ao encerrar API HTTP: %v", err)
+	}
+	return nil
+}
+
+// handleHealth responde GET /healthz
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "método não permitido: %s", r.Method)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "versao": s.Version})
+}
+
+// handleDarms responde POST /v1/darms: campo multipart "files" com um ou mais
+// PDFs; parâmetros opcionais dialect (mysql, postgres, sqlserver, oracle) e strict
+func (s *Server) handleDarms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "método não permitido: %s", r.Method)
+		return
+	}
+
+	cfg, err := s.requestConfig(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	docs, status, err := s.readDocumentos(w, r)
+	if err != nil {
+		writeError(w, status, "%v", err)
+		return
+	}
+
+	ctx := r.Context()
+	if timeout := cfg.RequestTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	resposta, err := processDocumentos(ctx, cfg, docs)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			writeError(w, http.StatusGatewayTimeout, "tempo limite de %s excedido", cfg.RequestTimeout())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+
+	logrus.Infof("🌐 %s %s: %d PDF(s), %d válido(s), %d com erro", r.Method, r.URL.Path, len(docs), resposta.Relatorio.Validos, resposta.Relatorio.ComErro)
+	writeJSON(w, http.StatusOK, resposta)
+}
+
+// requestConfig copia a configuração do servidor aplicando os parâmetros da
+// requisição; exportações e carga em massa não se aplicam à API
+func (s *Server) requestConfig(r *http.Request) (*config.Config, error) {
+	cfg := *s.Config
+	cfg.Output.Formats = nil
+	cfg.SQL.BulkLoad = false
+	cfg.SQL.PerGuiaChecks = false
+
+	query := r.URL.Query()
+	if dialect := query.Get("dialect"); dialect != "" {
+		cfg.SQL.Dialect = dialect
+	}
+	if strict := query.Get("strict"); strict != "" {
+		enabled, err := strconv.ParseBool(strict)
+		if err != nil {
+			return nil, fmt.Errorf("parâmetro strict inválido: %q", strict)
+		}
+		if enabled {
+			cfg.Validation.Mode = validation.ModeStrict
+		} else {
+			cfg.Validation.Mode = validation.ModeLenient
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// readDocumentos lê os PDFs enviados no campo multipart "files", respeitando
+// os limites de tamanho e de quantidade; retorna o status HTTP em caso de erro
+func (s *Server) readDocumentos(w http.ResponseWriter, r *http.Request) ([]processor.Documento, int, error) {
+	if limit := s.Config.MaxUploadBytes(); limit > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+	}
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("requisição excede o limite de %d MB", s.Config.Server.MaxUploadMB)
+		}
+		return nil, http.StatusBadRequest, fmt.Errorf("erro ao ler upload multipart: %v", err)
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	files := r.MultipartForm.File["files"]
+	if len(files) == 0 {
+		return nil, http.StatusBadRequest, fmt.Errorf("nenhum PDF enviado no campo \"files\"")
+	}
+	if max := s.Config.Server.MaxFiles; max > 0 && len(files) > max {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("requisição com %d PDFs excede o limite de %d", len(files), max)
+	}
+
+	docs := make([]processor.Documento, 0, len(files))
+	for _, header := range files {
+		file, err := header.Open()
+		if err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("erro ao ler %s: %v", header.Filename, err)
+		}
+		content, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("erro ao ler %s: %v", header.Filename, err)
+		}
+		docs = append(docs, processor.NewDocumento(filepath.Base(header.Filename), content))
+	}
+	return docs, http.StatusOK, nil
+}
+
+// processDocumentos processa os documentos em um diretório temporário e
+// monta a resposta com os registros, o script consolidado e o relatório
+func processDocumentos(ctx context.Context, cfg *config.Config, docs []processor.Documento) (*Resposta, error) {
+	tempDir, err := os.MkdirTemp("", "darm-serve-")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório temporário: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	dp, err := runProcessor(ctx, cfg, filepath.Join(tempDir, "darms"), filepath.Join(tempDir, "inserts"), docs)
+	if err != nil {
+		return nil, err
+	}
+	return newResposta(dp)
+}
+
+// runProcessor processa os documentos com um novo processador, gravando as
+// saídas em outputDir
+func runProcessor(ctx context.Context, cfg *config.Config, darmsDir, outputDir string, docs []processor.Documento) (*processor.DarmProcessor, error) {
+	dp := processor.NewDarmProcessorWithConfig(cfg)
+	dp.DarmsDir = darmsDir
+	dp.OutputDir = outputDir
+	if err := dp.Init(); err != nil {
+		return nil, err
+	}
+
+	if err := dp.ProcessDocumentos(ctx, docs); err != nil {
+		return nil, err
+	}
+	return dp, nil
+}
+
+// newResposta monta a resposta com os registros, o script consolidado e o
+// relatório de um processamento concluído
+func newResposta(dp *processor.DarmProcessor) (*Resposta, error) {
+	script, err := os.ReadFile(filepath.Join(dp.OutputDir, "INSERT_TODOS_DARMs.sql"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("erro ao ler arquivo SQL único: %v", err)
+	}
+
+	return &Resposta{
+		Documentos: output.ExportRecords(dp.Resultados, dp.Falhas),
+		Dialeto:    dp.Relatorio.Dialeto,
+		SQL:        string(script),
+		Relatorio:  dp.Relatorio,
+	}, nil
+}
+
+// writeJSON grava o corpo da resposta em JSON
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(body); err != nil {
+		logrus.Errorf("❌ Erro ao gravar resposta HTTP: %v", err)
+	}
+}
+
+// writeError grava uma resposta de erro {"erro": "..."}
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, map[string]string{"erro": fmt.Sprintf(format, args...)})
+}
+
+// HealthCheck consulta /healthz da API em addr (ex.: :8080) e retorna erro se
+// ela não responder com sucesso; usado por darm-processor --health-check
+func HealthCheck(addr string, timeout time.Duration) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("endereço inválido: %q", addr)
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get("http://" + net.JoinHostPort(host, port) + HealthPath)
+	if err != nil {
+		return fmt.Errorf("API indisponível: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API respondeu %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/config"
+	"gerador-query-darm-go/output"
+)
+
+// uploadRequest monta um POST multipart com os arquivos no campo "files"
+func uploadRequest(t *testing.T, target string, files map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, content := range files {
+		part, err := writer.CreateFormFile("files", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(content))
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func newTestServer() *Server {
+	logrus.SetLevel(logrus.ErrorLevel)
+	return New(config.Default(), "test")
+}
+
+func TestHealth(t *testing.T) {
+	handler := newTestServer().Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, HealthPath, nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"ok"`) {
+		t.Errorf("healthz inesperado: %d %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, HealthPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST em healthz deveria ser rejeitado, obtido %d", rec.Code)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	ts := httptest.NewServer(newTestServer().Handler())
+	defer ts.Close()
+
+	if err := HealthCheck(strings.TrimPrefix(ts.URL, "http://"), time.Second); err != nil {
+		t.Errorf("HealthCheck falhou: %v", err)
+	}
+	ts.Close()
+	if err := HealthCheck(strings.TrimPrefix(ts.URL, "http://"), time.Second); err == nil {
+		t.Error("API encerrada deveria falhar no health check")
+	}
+	if err := HealthCheck("sem-porta", time.Second); err == nil {
+		t.Error("endereço inválido deveria falhar")
+	}
+}
+
+func TestDarmsRejectsInvalidRequests(t *testing.T) {
+	server := newTestServer()
+	server.Config.Server.MaxFiles = 1
+	server.Config.Server.MaxUploadMB = 1
+	handler := server.Handler()
+
+	tests := map[string]struct {
+		req    *http.Request
+		status int
+	}{
+		"método":        {httptest.NewRequest(http.MethodGet, DarmsPath, nil), http.StatusMethodNotAllowed},
+		"sem arquivos":  {uploadRequest(t, DarmsPath, nil), http.StatusBadRequest},
+		"não multipart": {httptest.NewRequest(http.MethodPost, DarmsPath, strings.NewReader("x")), http.StatusBadRequest},
+		"dialeto":       {uploadRequest(t, DarmsPath+"?dialect=db2", map[string]string{"a.pdf": "x"}), http.StatusBadRequest},
+		"strict":        {uploadRequest(t, DarmsPath+"?strict=talvez", map[string]string{"a.pdf": "x"}), http.StatusBadRequest},
+		"muitos PDFs":   {uploadRequest(t, DarmsPath, map[string]string{"a.pdf": "x", "b.pdf": "y"}), http.StatusRequestEntityTooLarge},
+		"upload grande": {uploadRequest(t, DarmsPath, map[string]string{"a.pdf": strings.Repeat("x", 2<<20)}), http.StatusRequestEntityTooLarge},
+	}
+	for name, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, tt.req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status esperado %d, obtido %d (%s)", name, tt.status, rec.Code, rec.Body)
+		}
+		if !strings.Contains(rec.Body.String(), `"erro"`) {
+			t.Errorf("%s: resposta deveria trazer o erro: %s", name, rec.Body)
+		}
+	}
+}
+
+func TestDarmsReportsDocumentErrors(t *testing.T) {
+	handler := newTestServer().Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, uploadRequest(t, DarmsPath+"?dialect=postgres", map[string]string{"../anexo.pdf": "não é um PDF"}))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status inesperado: %d %s", rec.Code, rec.Body)
+	}
+
+	var resposta Resposta
+	if err := json.Unmarshal(rec.Body.Bytes(), &resposta); err != nil {
+		t.Fatalf("resposta inválida: %v", err)
+	}
+	if len(resposta.Documentos) != 1 || resposta.Documentos[0].Arquivo != "anexo.pdf" || resposta.Documentos[0].Status != output.StatusErro {
+		t.Errorf("documentos inesperados: %+v", resposta.Documentos)
+	}
+	if resposta.Dialeto != "postgres" || resposta.SQL != "" {
+		t.Errorf("dialeto ou SQL inesperados: %q %q", resposta.Dialeto, resposta.SQL)
+	}
+	if resposta.Relatorio == nil || resposta.Relatorio.ComErro != 1 {
+		t.Errorf("relatório inesperado: %+v", resposta.Relatorio)
+	}
+}
@@ -0,0 +1,165 @@
+package sqlgen
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gerador-query-darm-go/validation"
+)
+
+// Arquivos da carga em massa, gerados ao lado de INSERT_TODOS_DARMs.sql
+const (
+	BulkDataFile   = "LOAD_TODOS_DARMs.tsv"
+	BulkScriptFile = "LOAD_TODOS_DARMs.sql"
+)
+
+// bulkNull é o marcador de NULL do LOAD DATA
+const bulkNull = `\N`
+
+// bulkEscaper escapa os caracteres especiais do LOAD DATA (ESCAPED BY '\\')
+var bulkEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`, "\x00", `\0`)
+
+// BulkLoadOptions controla a saída LOAD DATA LOCAL INFILE
+type BulkLoadOptions struct {
+	Insert InsertOptions
+	// Charset é o charset do MySQL usado no arquivo de dados (latin1 ou utf8mb4)
+	Charset string
+	// DataFile é o caminho do TSV referenciado pelo script
+	DataFile string
+}
+
+// MySQLCharset converte sql.encoding no nome de charset do MySQL
+func MySQLCharset(encoding string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "latin1", "iso-8859-1", "iso8859-1":
+		return "latin1", nil
+	case "utf8", "utf-8", "utf8mb4":
+		return "utf8mb4", nil
+	}
+	return "", fmt.Errorf("encoding não suportado na carga em massa: %q (use latin1 ou utf8mb4)", encoding)
+}
+
+// encodeCharset codifica o texto no charset do arquivo de dados.
+// Em latin1, caracteres fora do ISO 8859-1 viram '?', como faz o MySQL.
+func encodeCharset(text, charset string) []byte {
+	if charset != "latin1" {
+		return []byte(text)
+	}
+
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r > 0xFF {
+			encoded = append(encoded, '?')
+			continue
+		}
+		encoded = append(encoded, byte(r))
+	}
+	return encoded
+}
+
+// bulkField converte um valor da linha no campo do TSV. Expressões calculadas
+// pelo banco (NOW(), SQ_DOC dinâmico) não cabem no arquivo: o campo recebe
+// NULL e a expressão é retornada para a cláusula SET.
+func bulkField(value interface{}) (field string, expression string) {
+	switch v := value.(type) {
+	case nil:
+		return bulkNull, ""
+	case string:
+		if v == "" {
+			return bulkNull, ""
+		}
+		return bulkEscaper.Replace(v), ""
+	case validation.Money:
+		return v.Decimal(), ""
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05"), ""
+	case SQLNow, SQLDocSequence, SQLRaw:
+		return bulkNull, MySQLDialect{}.FormatValue(v)
+	case bool:
+		if v {
+			return "1", ""
+		}
+		return "0", ""
+	case float32:
+		return validation.MoneyFromFloat(float64(v)).Decimal(), ""
+	case float64:
+		return validation.MoneyFromFloat(v).Decimal(), ""
+	default:
+		return bulkEscaper.Replace(fmt.Sprintf("%v", v)), ""
+	}
+}
+
+// quoteBulkPath coloca o caminho do arquivo entre aspas no script
+func quoteBulkPath(path string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(filepath.ToSlash(path)) + "'"
+}
+
+// RenderBulkLoad gera o TSV (cabeçalho + uma linha por registro, na ordem de
+// farrDarmsPagosColumns) e o script LOAD DATA LOCAL INFILE correspondente
+func RenderBulkLoad(rows []DarmRow, opts BulkLoadOptions) ([]byte, string, error) {
+	if _, ok := opts.Insert.EffectiveDialect().(MySQLDialect); !ok {
+		return nil, "", fmt.Errorf("carga em massa disponível apenas para o dialeto mysql")
+	}
+
+	modifier := ""
+	switch opts.Insert.Conflict {
+	case ConflictIgnore:
+		modifier = "IGNORE "
+	case ConflictUpdate:
+		return nil, "", fmt.Errorf("a estratégia update não é suportada pelo LOAD DATA (REPLACE substituiria a linha inteira)")
+	case ConflictNotExists:
+		return nil, "", fmt.Errorf("a estratégia not_exists não é suportada pelo LOAD DATA (IGNORE depende da chave única, não da guia)")
+	}
+
+	var data strings.Builder
+	data.WriteString(strings.Join(farrDarmsPagosColumns, "\t") + "\n")
+
+	expressions := map[string]string{}
+	for i, row := range rows {
+		if _, err := row.SQLValues(); err != nil {
+			return nil, "", fmt.Errorf("linha %d: %v", i+1, err)
+		}
+
+		fields := make([]string, 0, len(farrDarmsPagosColumns))
+		for _, column := range farrDarmsPagosColumns {
+			field, expression := bulkField(row[column])
+			// A cláusula SET vale para todas as linhas: a primeira define a expressão
+			if i == 0 {
+				expressions[column] = expression
+			} else if expressions[column] != expression {
+				return nil, "", fmt.Errorf("linha %d: coluna %s mistura valores e expressões SQL", i+1, column)
+			}
+			fields = append(fields, field)
+		}
+		data.WriteString(strings.Join(fields, "\t") + "\n")
+	}
+
+	targets := make([]string, 0, len(farrDarmsPagosColumns))
+	assignments := []string{}
+	for _, column := range farrDarmsPagosColumns {
+		if expression := expressions[column]; expression != "" {
+			targets = append(targets, "@"+column)
+			assignments = append(assignments, fmt.Sprintf("    %s = %s", column, expression))
+			continue
+		}
+		targets = append(targets, column)
+	}
+
+	load := fmt.Sprintf("LOAD DATA LOCAL INFILE %s\n%sINTO TABLE FarrDarmsPagos\nCHARACTER SET %s\nFIELDS TERMINATED BY '\\t' ESCAPED BY '\\\\'\nLINES TERMINATED BY '\\n'\nIGNORE 1 LINES\n(\n%s\n)",
+		quoteBulkPath(opts.DataFile), modifier, opts.Charset, formatGroups(targets, columnLineGroups, "    "))
+	if len(assignments) > 0 {
+		load += "\nSET\n" + strings.Join(assignments, ",\n")
+	}
+
+	d := opts.Insert.EffectiveDialect()
+	script := strings.Join([]string{
+		d.UseSchema(DefaultSchema),
+		renderCountQuery(rows, "total_antes", d),
+		load + ";",
+		renderCountQuery(rows, "total_depois", d),
+	}, "\n\n") + "\n"
+
+	return encodeCharset(data.String(), opts.Charset), script, nil
+}
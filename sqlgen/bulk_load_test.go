@@ -0,0 +1,94 @@
+package sqlgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderBulkLoad(t *testing.T) {
+	rows := goldenRows(t, 2)
+	rows[0]["NR_INSCRICAO"] = "São\tPaulo\\n\n"
+	rows[0]["DT_INCL"] = SQLNow{}
+	rows[1]["DT_INCL"] = SQLNow{}
+
+	opts := BulkLoadOptions{
+		Insert:   InsertOptions{Conflict: ConflictIgnore},
+		Charset:  "latin1",
+		DataFile: BulkDataFile,
+	}
+	data, script, err := RenderBulkLoad(rows, opts)
+	if err != nil {
+		t.Fatalf("RenderBulkLoad falhou: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("esperadas 3 linhas no TSV (cabeçalho + 2), obtidas %d", len(lines))
+	}
+	if lines[0] != strings.Join(farrDarmsPagosColumns, "\t") {
+		t.Errorf("cabeçalho fora da ordem de FarrDarmsPagos: %q", lines[0])
+	}
+
+	fields := strings.Split(lines[1], "\t")
+	if len(fields) != len(farrDarmsPagosColumns) {
+		t.Fatalf("linha com %d campos, esperados %d", len(fields), len(farrDarmsPagosColumns))
+	}
+	values := map[string]string{}
+	for i, column := range farrDarmsPagosColumns {
+		values[column] = fields[i]
+	}
+
+	expected := map[string]string{
+		"id":           `\N`,
+		"NR_INSCRICAO": "S\xe3o\\tPaulo\\\\n\\n",
+		"DT_VENCTO":    "2024-12-15 00:00:00",
+		"DT_INCL":      `\N`,
+		"VL_PAGO":      "1234.56",
+		"SQ_DOC":       "1000",
+		"ST_DOC_D":     "13",
+	}
+	for column, value := range expected {
+		if values[column] != value {
+			t.Errorf("%s = %q, esperado %q", column, values[column], value)
+		}
+	}
+
+	for _, part := range []string{
+		"use silfae;",
+		"AS total_antes",
+		"LOAD DATA LOCAL INFILE 'LOAD_TODOS_DARMs.tsv'\nIGNORE INTO TABLE FarrDarmsPagos\nCHARACTER SET latin1\n",
+		"FIELDS TERMINATED BY '\\t' ESCAPED BY '\\\\'\nLINES TERMINATED BY '\\n'\nIGNORE 1 LINES\n(\n    id, AA_EXERCICIO,",
+		"SET\n    DT_INCL = NOW(),\n    DT_PAGTO = NOW();",
+		"AS total_depois",
+	} {
+		if !strings.Contains(script, part) {
+			t.Errorf("script deveria conter %q:\n%s", part, script)
+		}
+	}
+	if !strings.Contains(script, "@DT_INCL, DT_VENCTO,") {
+		t.Errorf("colunas com expressão deveriam ser lidas em variáveis:\n%s", script)
+	}
+}
+
+func TestRenderBulkLoadRejects(t *testing.T) {
+	rows := goldenRows(t, 2)
+
+	if _, _, err := RenderBulkLoad(rows, BulkLoadOptions{Insert: InsertOptions{Conflict: ConflictUpdate}, Charset: "latin1"}); err == nil {
+		t.Error("estratégia update deveria ser rejeitada")
+	}
+	if _, _, err := RenderBulkLoad(rows, BulkLoadOptions{Insert: InsertOptions{Conflict: ConflictNotExists}, Charset: "latin1"}); err == nil {
+		t.Error("estratégia not_exists deveria ser rejeitada")
+	}
+	if _, _, err := RenderBulkLoad(rows, BulkLoadOptions{Insert: InsertOptions{Conflict: ConflictInsert, Dialect: PostgresDialect{}}, Charset: "latin1"}); err == nil {
+		t.Error("dialeto diferente de mysql deveria ser rejeitado")
+	}
+
+	rows[1]["SQ_DOC"] = SQLDocSequence{Guia: 2}
+	if _, _, err := RenderBulkLoad(rows, BulkLoadOptions{Insert: InsertOptions{Conflict: ConflictInsert}, Charset: "latin1"}); err == nil || !strings.Contains(err.Error(), "SQ_DOC") {
+		t.Errorf("coluna misturando valores e expressões deveria ser rejeitada, obtido %v", err)
+	}
+
+	if _, err := MySQLCharset("ebcdic"); err == nil {
+		t.Error("encoding desconhecido deveria ser rejeitado")
+	}
+}
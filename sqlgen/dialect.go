@@ -0,0 +1,254 @@
+package sqlgen
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultSchema é o schema de FarrDarmsPagos
+const DefaultSchema = "silfae"
+
+// Dialect define as diferenças de sintaxe SQL entre os bancos suportados
+type Dialect interface {
+	// Name retorna o nome do dialeto usado em sql.dialect
+	Name() string
+	// UseSchema retorna o comando que seleciona o schema
+	UseSchema(schema string) string
+	// BeginTransaction retorna o comando que inicia a transação ("" quando implícita)
+	BeginTransaction() string
+	// FormatValue formata um valor da linha como literal SQL
+	FormatValue(value interface{}) string
+	// MaxRowsPerInsert é o limite de linhas de um INSERT multi-linha (0 = sem limite)
+	MaxRowsPerInsert() int
+	// IncludeNullID indica se a coluna id (autoincremento) é enviada como NULL ou omitida
+	IncludeNullID() bool
+	// SelectFrom retorna a tabela usada em SELECT sem tabela (" FROM DUAL" ou "")
+	SelectFrom() string
+	// IgnoreClause retorna o verbo e o sufixo do INSERT que ignora duplicatas;
+	// ok falso indica que o dialeto usa INSERT ... WHERE NOT EXISTS
+	IgnoreClause() (verb, suffix string, ok bool)
+	// UpsertClause retorna o sufixo do INSERT que atualiza duplicatas;
+	// ok falso indica que o dialeto usa MERGE
+	UpsertClause(keyColumns, updateColumns []string) (clause string, ok bool)
+	// QuoteIdentifier delimita o nome de tabela ou coluna no dialeto
+	QuoteIdentifier(name string) string
+}
+
+// ParseDialect retorna o dialeto pelo nome (vazio = mysql)
+func ParseDialect(name string) (Dialect, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "mysql":
+		return MySQLDialect{}, nil
+	case "postgres", "postgresql":
+		return PostgresDialect{}, nil
+	case "sqlserver", "mssql":
+		return SQLServerDialect{}, nil
+	case "oracle":
+		return OracleDialect{}, nil
+	}
+	return nil, fmt.Errorf("dialeto SQL inválido: %q (use mysql, postgres, sqlserver ou oracle)", name)
+}
+
+// quoteIdentifiers delimita cada nome no dialeto
+func quoteIdentifiers(d Dialect, names []string) []string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, d.QuoteIdentifier(name))
+	}
+	return quoted
+}
+
+// formatDialectValue formata os valores comuns a todos os dialetos, delegando
+// datas, data/hora atual e SQ_DOC dinâmico às funções do dialeto
+func formatDialectValue(value interface{}, timestamp func(time.Time) string, now string, docSequence func(int) string) string {
+	switch v := value.(type) {
+	case time.Time:
+		return timestamp(v)
+	case SQLNow:
+		return now
+	case SQLDocSequence:
+		return docSequence(v.Guia)
+	default:
+		return NewSQLUtils().FormatSQLValue(value)
+	}
+}
+
+// MySQLDialect é o dialeto padrão (formato Control-M original)
+type MySQLDialect struct{}
+
+// Name retorna o nome do dialeto
+func (MySQLDialect) Name() string { return "mysql" }
+
+// UseSchema seleciona o banco com USE
+func (MySQLDialect) UseSchema(schema string) string { return fmt.Sprintf("use %s;", schema) }
+
+// BeginTransaction inicia a transação explicitamente
+func (MySQLDialect) BeginTransaction() string { return "START TRANSACTION;" }
+
+// FormatValue formata valores com as regras de SQLUtils
+func (MySQLDialect) FormatValue(value interface{}) string {
+	return NewSQLUtils().FormatSQLValue(value)
+}
+
+// MaxRowsPerInsert não limita o INSERT multi-linha (use sql.batch_size)
+func (MySQLDialect) MaxRowsPerInsert() int { return 0 }
+
+// IncludeNullID envia id = NULL para o AUTO_INCREMENT
+func (MySQLDialect) IncludeNullID() bool { return true }
+
+// SelectFrom usa a tabela DUAL
+func (MySQLDialect) SelectFrom() string { return " FROM DUAL" }
+
+// IgnoreClause usa INSERT IGNORE
+func (MySQLDialect) IgnoreClause() (string, string, bool) { return "INSERT IGNORE INTO", "", true }
+
+// UpsertClause usa ON DUPLICATE KEY UPDATE
+func (MySQLDialect) UpsertClause(keyColumns, updateColumns []string) (string, bool) {
+	assignments := []string{}
+	for _, column := range updateColumns {
+		assignments = append(assignments, fmt.Sprintf("    %s = VALUES(%s)", column, column))
+	}
+	return "\nON DUPLICATE KEY UPDATE\n" + strings.Join(assignments, ",\n"), true
+}
+
+// QuoteIdentifier mantém o nome sem delimitador (formato Control-M original)
+func (MySQLDialect) QuoteIdentifier(name string) string { return name }
+
+// PostgresDialect gera SQL para PostgreSQL
+type PostgresDialect struct{}
+
+// Name retorna o nome do dialeto
+func (PostgresDialect) Name() string { return "postgres" }
+
+// UseSchema seleciona o schema pelo search_path
+func (PostgresDialect) UseSchema(schema string) string {
+	return fmt.Sprintf("SET search_path TO %s;", schema)
+}
+
+// BeginTransaction inicia a transação explicitamente
+func (PostgresDialect) BeginTransaction() string { return "START TRANSACTION;" }
+
+// FormatValue formata datas como TIMESTAMP e usa CURRENT_TIMESTAMP/EXTRACT(EPOCH)
+func (PostgresDialect) FormatValue(value interface{}) string {
+	return formatDialectValue(value,
+		func(t time.Time) string { return fmt.Sprintf("TIMESTAMP '%s'", t.Format("2006-01-02 15:04:05")) },
+		"CURRENT_TIMESTAMP",
+		func(guia int) string {
+			return fmt.Sprintf("(((%d %% 1000) * 1000) + (CAST(EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) AS BIGINT) %% 1000)) %% 1000000", guia)
+		})
+}
+
+// MaxRowsPerInsert não limita o INSERT multi-linha
+func (PostgresDialect) MaxRowsPerInsert() int { return 0 }
+
+// IncludeNullID omite id (serial/identity não aceita NULL)
+func (PostgresDialect) IncludeNullID() bool { return false }
+
+// SelectFrom dispensa tabela no SELECT
+func (PostgresDialect) SelectFrom() string { return "" }
+
+// IgnoreClause usa ON CONFLICT DO NOTHING
+func (PostgresDialect) IgnoreClause() (string, string, bool) {
+	return "INSERT INTO", "\nON CONFLICT DO NOTHING", true
+}
+
+// UpsertClause usa ON CONFLICT (chave) DO UPDATE; exige índice único na chave da guia
+func (PostgresDialect) UpsertClause(keyColumns, updateColumns []string) (string, bool) {
+	assignments := []string{}
+	for _, column := range quoteIdentifiers(PostgresDialect{}, updateColumns) {
+		assignments = append(assignments, fmt.Sprintf("    %s = EXCLUDED.%s", column, column))
+	}
+	return fmt.Sprintf("\nON CONFLICT (%s) DO UPDATE SET\n%s", strings.Join(quoteIdentifiers(PostgresDialect{}, keyColumns), ", "), strings.Join(assignments, ",\n")), true
+}
+
+// QuoteIdentifier usa aspas duplas, preservando maiúsculas e minúsculas do nome
+func (PostgresDialect) QuoteIdentifier(name string) string { return `"` + name + `"` }
+
+// SQLServerDialect gera SQL para Microsoft SQL Server
+type SQLServerDialect struct{}
+
+// Name retorna o nome do dialeto
+func (SQLServerDialect) Name() string { return "sqlserver" }
+
+// UseSchema seleciona o banco com USE
+func (SQLServerDialect) UseSchema(schema string) string { return fmt.Sprintf("USE %s;", schema) }
+
+// BeginTransaction inicia a transação explicitamente
+func (SQLServerDialect) BeginTransaction() string { return "BEGIN TRANSACTION;" }
+
+// FormatValue formata datas em ISO 8601 (independente de DATEFORMAT) e usa GETDATE()
+func (SQLServerDialect) FormatValue(value interface{}) string {
+	return formatDialectValue(value,
+		func(t time.Time) string { return fmt.Sprintf("'%s'", t.Format("2006-01-02T15:04:05")) },
+		"GETDATE()",
+		func(guia int) string {
+			return fmt.Sprintf("(((%d %% 1000) * 1000) + (DATEDIFF(SECOND, '1970-01-01', GETUTCDATE()) %% 1000)) %% 1000000", guia)
+		})
+}
+
+// MaxRowsPerInsert respeita o limite de 1000 linhas do construtor VALUES
+func (SQLServerDialect) MaxRowsPerInsert() int { return 1000 }
+
+// IncludeNullID omite id (IDENTITY não aceita valor explícito)
+func (SQLServerDialect) IncludeNullID() bool { return false }
+
+// SelectFrom dispensa tabela no SELECT
+func (SQLServerDialect) SelectFrom() string { return "" }
+
+// IgnoreClause não existe no SQL Server: usa WHERE NOT EXISTS
+func (SQLServerDialect) IgnoreClause() (string, string, bool) { return "", "", false }
+
+// UpsertClause não existe no SQL Server: usa MERGE
+func (SQLServerDialect) UpsertClause(keyColumns, updateColumns []string) (string, bool) {
+	return "", false
+}
+
+// QuoteIdentifier usa colchetes (independente de QUOTED_IDENTIFIER)
+func (SQLServerDialect) QuoteIdentifier(name string) string { return "[" + name + "]" }
+
+// OracleDialect gera SQL para Oracle
+type OracleDialect struct{}
+
+// Name retorna o nome do dialeto
+func (OracleDialect) Name() string { return "oracle" }
+
+// UseSchema altera o schema corrente da sessão
+func (OracleDialect) UseSchema(schema string) string {
+	return fmt.Sprintf("ALTER SESSION SET CURRENT_SCHEMA = %s;", schema)
+}
+
+// BeginTransaction não é necessário: a transação do Oracle é implícita
+func (OracleDialect) BeginTransaction() string { return "" }
+
+// FormatValue formata datas com TO_DATE e usa SYSDATE/MOD
+func (OracleDialect) FormatValue(value interface{}) string {
+	return formatDialectValue(value,
+		func(t time.Time) string {
+			return fmt.Sprintf("TO_DATE('%s', 'YYYY-MM-DD HH24:MI:SS')", t.Format("2006-01-02 15:04:05"))
+		},
+		"SYSDATE",
+		func(guia int) string {
+			return fmt.Sprintf("MOD((MOD(%d, 1000) * 1000) + MOD(TRUNC((CAST(SYS_EXTRACT_UTC(SYSTIMESTAMP) AS DATE) - DATE '1970-01-01') * 86400), 1000), 1000000)", guia)
+		})
+}
+
+// MaxRowsPerInsert é 1: o Oracle não aceita INSERT ... VALUES com várias linhas
+func (OracleDialect) MaxRowsPerInsert() int { return 1 }
+
+// IncludeNullID omite id (identity/sequence preenche o valor)
+func (OracleDialect) IncludeNullID() bool { return false }
+
+// SelectFrom usa a tabela DUAL
+func (OracleDialect) SelectFrom() string { return " FROM DUAL" }
+
+// IgnoreClause não existe no Oracle: usa WHERE NOT EXISTS
+func (OracleDialect) IgnoreClause() (string, string, bool) { return "", "", false }
+
+// UpsertClause não existe no Oracle: usa MERGE
+func (OracleDialect) UpsertClause(keyColumns, updateColumns []string) (string, bool) {
+	return "", false
+}
+
+// QuoteIdentifier usa aspas duplas, preservando maiúsculas e minúsculas do nome
+func (OracleDialect) QuoteIdentifier(name string) string { return `"` + name + `"` }
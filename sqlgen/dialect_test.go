@@ -0,0 +1,140 @@
+package sqlgen
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "atualiza os arquivos golden em testdata/")
+
+var testDialects = []Dialect{MySQLDialect{}, PostgresDialect{}, SQLServerDialect{}, OracleDialect{}}
+
+// goldenRows retorna linhas com valores fixos (sem dependência do relógio)
+func goldenRows(t *testing.T, n int) []DarmRow {
+	t.Helper()
+	rows := testRows(t, n)
+	for _, row := range rows {
+		row["NR_COMPETENCIA"] = 2025
+	}
+	return rows
+}
+
+func assertGolden(t *testing.T, name, content string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name)
+
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("erro ao criar diretório golden: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("erro ao gravar %s: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("erro ao ler %s (rode go test -run TestDialectGolden -update): %v", path, err)
+	}
+	if string(expected) != content {
+		t.Errorf("%s divergente do golden:\n%s", name, content)
+	}
+}
+
+func TestDialectGolden(t *testing.T) {
+	for _, d := range testDialects {
+		single := goldenRows(t, 1)[0]
+		single["SQ_DOC"] = SQLDocSequence{Guia: 1}
+		insert, err := RenderInsert(single, InsertOptions{Conflict: ConflictIgnore, Dialect: d})
+		if err != nil {
+			t.Fatalf("%s: RenderInsert falhou: %v", d.Name(), err)
+		}
+		assertGolden(t, filepath.Join(d.Name(), "single.sql"), d.UseSchema(DefaultSchema)+"\n\n"+insert)
+
+		rows := goldenRows(t, 3)
+		for _, strategy := range []ConflictStrategy{ConflictInsert, ConflictIgnore, ConflictUpdate, ConflictNotExists} {
+			opts := ScriptOptions{
+				Insert:         InsertOptions{Conflict: strategy, Dialect: d},
+				BatchSize:      2,
+				UseTransaction: true,
+				Comments:       true,
+			}
+			script, err := renderConsolidatedScript(rows, opts)
+			if err != nil {
+				t.Fatalf("%s/%s: renderConsolidatedScript falhou: %v", d.Name(), strategy, err)
+			}
+			assertGolden(t, filepath.Join(d.Name(), "consolidated_"+string(strategy)+".sql"), script)
+		}
+
+		opts := ScriptOptions{Insert: InsertOptions{Dialect: d}, UseTransaction: true}
+		assertGolden(t, filepath.Join(d.Name(), "rollback.sql"), RenderRollbackScript(RollbackKeysFromRows(rows), opts))
+		assertGolden(t, filepath.Join(d.Name(), "check.sql"), RenderCheckQuery(rows, d))
+	}
+}
+
+func TestDialectScriptsRoundTrip(t *testing.T) {
+	rows := goldenRows(t, 4)
+	rows[1]["NR_INSCRICAO"] = "O'Connor AS (filial)"
+
+	for _, d := range testDialects {
+		for _, strategy := range []ConflictStrategy{ConflictInsert, ConflictIgnore, ConflictUpdate, ConflictNotExists} {
+			opts := ScriptOptions{Insert: InsertOptions{Conflict: strategy, Dialect: d}, BatchSize: 3}
+			script, err := renderConsolidatedScript(rows, opts)
+			if err != nil {
+				t.Fatalf("%s/%s: renderConsolidatedScript falhou: %v", d.Name(), strategy, err)
+			}
+
+			keys, err := RollbackKeysFromScript(script)
+			if err != nil {
+				t.Fatalf("%s/%s: RollbackKeysFromScript falhou: %v", d.Name(), strategy, err)
+			}
+			if rollback := RenderRollbackScript(keys, opts); rollback != RenderRollbackScript(RollbackKeysFromRows(rows), opts) {
+				t.Errorf("%s/%s: rollback do script difere do rollback das linhas:\n%s", d.Name(), strategy, rollback)
+			}
+
+			inserts, err := parseInsertScript(script)
+			if err != nil {
+				t.Fatalf("%s/%s: parseInsertScript falhou: %v", d.Name(), strategy, err)
+			}
+			parsed := []ParsedRow{}
+			for _, insert := range inserts {
+				parsed = append(parsed, insert.Rows...)
+			}
+			if len(parsed) != len(rows) {
+				t.Fatalf("%s/%s: esperadas %d linhas, obtidas %d", d.Name(), strategy, len(rows), len(parsed))
+			}
+			if parsed[1]["NR_INSCRICAO"] != "'O''Connor AS (filial)'" {
+				t.Errorf("%s/%s: NR_INSCRICAO = %q", d.Name(), strategy, parsed[1]["NR_INSCRICAO"])
+			}
+		}
+	}
+}
+
+func TestDialectBatchLimits(t *testing.T) {
+	rows := goldenRows(t, 3)
+
+	script, err := renderConsolidatedScript(rows, ScriptOptions{Insert: InsertOptions{Conflict: ConflictInsert, Dialect: OracleDialect{}}})
+	if err != nil {
+		t.Fatalf("renderConsolidatedScript falhou: %v", err)
+	}
+	if n := strings.Count(script, `INSERT INTO "FarrDarmsPagos"`); n != 3 {
+		t.Errorf("Oracle deveria gerar um INSERT por linha, obtidos %d", n)
+	}
+
+	opts := ScriptOptions{Insert: InsertOptions{Dialect: SQLServerDialect{}}, BatchSize: 5000}
+	if size := opts.EffectiveBatchSize(); size != 1000 {
+		t.Errorf("SQL Server deveria limitar lotes a 1000 linhas, obtido %d", size)
+	}
+	opts.Insert.Dialect = MySQLDialect{}
+	if size := opts.EffectiveBatchSize(); size != 5000 {
+		t.Errorf("MySQL não deveria limitar lotes, obtido %d", size)
+	}
+
+	if _, err := ParseDialect("sqlite"); err == nil {
+		t.Error("dialeto desconhecido deveria ser rejeitado")
+	}
+}
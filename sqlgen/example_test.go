@@ -0,0 +1,39 @@
+package sqlgen_test
+
+import (
+	"fmt"
+	"log"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/validation"
+)
+
+func ExampleGenerate() {
+	record, err := validation.Parse(&extraction.DarmData{
+		Inscricao:      "123456",
+		CodigoReceita:  "2623",
+		ValorPrincipal: "1.234,56",
+		ValorTotal:     "1.234,56",
+		DataVencimento: "15/12/2024",
+		Exercicio:      "2025",
+		NumeroGuia:     "123",
+		Competencia:    "11/2024",
+	}, validation.Options{Strict: true})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// SQ_DOC fixo permite gerar o rollback exato do script
+	row := sqlgen.RowFromRecord(record)
+	row["SQ_DOC"] = 123001
+
+	script, err := sqlgen.Generate([]sqlgen.DarmRow{row}, sqlgen.ScriptOptions{
+		Insert:         sqlgen.InsertOptions{Conflict: sqlgen.ConflictIgnore, Dialect: sqlgen.PostgresDialect{}},
+		UseTransaction: true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(string(script))
+}
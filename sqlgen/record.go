@@ -0,0 +1,57 @@
+package sqlgen
+
+import (
+	"time"
+
+	"gerador-query-darm-go/validation"
+)
+
+// RowFromRecord monta a linha de FarrDarmsPagos a partir do DARM validado
+func RowFromRecord(r *validation.DarmRecord) DarmRow {
+	var dataVencimento interface{}
+	if r.Vencimento != nil {
+		dataVencimento = *r.Vencimento
+	}
+
+	// NR_COMPETENCIA é o ano da competência (ano corrente quando ausente)
+	competencia := time.Now().Year()
+	if r.Competencia != nil {
+		competencia = r.Competencia.Ano
+	}
+
+	return DarmRow{
+		"id":                   nil,
+		"AA_EXERCICIO":         r.Exercicio,
+		"CD_BANCO":             defaultLote.CodigoBanco,
+		"NR_BDA":               defaultLote.NumeroBDA,
+		"NR_COMPLEMENTO":       defaultLote.Complemento,
+		"NR_LOTE_NSA":          defaultLote.NSA,
+		"TP_LOTE_D":            defaultLote.Tipo,
+		"SQ_DOC":               SQLDocSequence{Guia: r.Guia}, // SQ_DOC dinâmico (o arquivo único usa valor calculado no Go)
+		"CD_RECEITA":           r.Receita.Numero(),
+		"CD_USU_ALT":           nil,
+		"CD_USU_INCL":          "FARR",
+		"DT_ALT":               nil,
+		"DT_INCL":              SQLNow{},
+		"DT_VENCTO":            dataVencimento,
+		"DT_PAGTO":             SQLNow{},
+		"NR_INSCRICAO":         r.Inscricao,
+		"NR_GUIA":              r.Guia,
+		"NR_COMPETENCIA":       competencia,
+		"NR_CODIGO_BARRAS":     r.CodigoBarras,
+		"NR_LOTE_IPTU":         nil,
+		"ST_DOC_D":             "13",
+		"TP_IMPOSTO":           nil,
+		"VL_PAGO":              r.ValorTotal,
+		"VL_RECEITA":           r.ValorTotal,
+		"VL_PRINCIPAL":         r.ValorPrincipal,
+		"VL_MORA":              validation.Money(0),
+		"VL_MULTA":             validation.Money(0),
+		"VL_MULTAF_TCDL":       nil,
+		"VL_MULTAP_TSD":        nil,
+		"VL_INSU_TIP":          nil,
+		"VL_JUROS":             validation.Money(0),
+		"processado":           0,
+		"criticaProcessamento": nil,
+	}
+}
@@ -0,0 +1,75 @@
+package sqlgen
+
+import (
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/internal/darmtest"
+	"gerador-query-darm-go/validation"
+)
+
+func TestRowFromRecord(t *testing.T) {
+	data := darmtest.DarmData("0042.pdf")
+	data.Competencia = "11/2024"
+
+	record, err := validation.Parse(data, validation.Options{})
+	if err != nil {
+		t.Fatalf("Parse falhou: %v", err)
+	}
+
+	row := RowFromRecord(record)
+	if row["NR_COMPETENCIA"] != 2024 || row["CD_RECEITA"] != 2623 || row["VL_PAGO"] != validation.Money(123456) {
+		t.Errorf("linha inesperada: NR_COMPETENCIA=%v CD_RECEITA=%v VL_PAGO=%v", row["NR_COMPETENCIA"], row["CD_RECEITA"], row["VL_PAGO"])
+	}
+	if _, ok := row["SQ_DOC"].(SQLDocSequence); !ok {
+		t.Errorf("SQ_DOC deveria ser calculado no banco: %v", row["SQ_DOC"])
+	}
+}
+
+func TestRenderInsertFromRecord(t *testing.T) {
+	record, err := validation.Parse(&extraction.DarmData{
+		Inscricao:      "123456",
+		CodigoReceita:  "2623",
+		ValorPrincipal: "1.234,56",
+		ValorTotal:     "1.234,56",
+		DataVencimento: "15/12/2024",
+		Exercicio:      "2025",
+		NumeroGuia:     "123456789",
+	}, validation.Options{})
+	if err != nil {
+		t.Fatalf("Parse falhou: %v", err)
+	}
+
+	sql, err := RenderInsert(RowFromRecord(record), InsertOptions{})
+	if err != nil {
+		t.Fatalf("RenderInsert falhou: %v", err)
+	}
+	for _, expected := range []string{"INTO FarrDarmsPagos", "123456", "123456789", "1234.56", "2024-12-15"} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("SQL deveria conter %q:\n%s", expected, sql)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	rows := testRows(t, 3)
+	opts := ScriptOptions{Insert: InsertOptions{Conflict: ConflictIgnore}, UseTransaction: true}
+
+	script, err := Generate(rows, opts)
+	if err != nil {
+		t.Fatalf("Generate falhou: %v", err)
+	}
+	expected, err := renderConsolidatedScript(rows, opts)
+	if err != nil {
+		t.Fatalf("renderConsolidatedScript falhou: %v", err)
+	}
+	if string(script) != expected {
+		t.Errorf("Generate difere do script consolidado:\n%s", script)
+	}
+
+	opts.Insert.Conflict = "merge"
+	if _, err := Generate(rows, opts); err == nil {
+		t.Error("estratégia inválida deveria ser rejeitada")
+	}
+}
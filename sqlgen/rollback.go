@@ -0,0 +1,104 @@
+package sqlgen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// rollbackColumns identificam exatamente uma linha inserida: chave do lote, NR_GUIA e SQ_DOC
+var rollbackColumns = append(append([]string{}, conflictKeyColumns...), "SQ_DOC")
+
+// KeyColumns retorna as colunas que identificam exatamente uma linha inserida
+// (chave do lote, NR_GUIA e SQ_DOC), as mesmas usadas pelo rollback
+func KeyColumns() []string {
+	return append([]string{}, rollbackColumns...)
+}
+
+// integerLiteralRegex reconhece literais inteiros (SQ_DOC precisa ser um valor fixo)
+var integerLiteralRegex = regexp.MustCompile(`^-?\d+$`)
+
+// RollbackKeysFromRows extrai as chaves de rollback das linhas geradas
+func RollbackKeysFromRows(rows []DarmRow) []ParsedRow {
+	sqlUtils := NewSQLUtils()
+	keys := make([]ParsedRow, 0, len(rows))
+	for _, row := range rows {
+		key := make(ParsedRow, len(rollbackColumns))
+		for _, column := range rollbackColumns {
+			key[column] = sqlUtils.FormatSQLValue(row[column])
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// RollbackKeysFromScript extrai as chaves de rollback de um INSERT_TODOS_DARMs.sql
+func RollbackKeysFromScript(script string) ([]ParsedRow, error) {
+	rows, err := ParseFarrDarmsPagos(script)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []ParsedRow{}
+	for _, row := range rows {
+		key := make(ParsedRow, len(rollbackColumns))
+		for _, column := range rollbackColumns {
+			value, ok := row[column]
+			if !ok {
+				return nil, fmt.Errorf("coluna %s ausente no INSERT", column)
+			}
+			key[column] = value
+		}
+		if !integerLiteralRegex.MatchString(key["SQ_DOC"]) {
+			return nil, fmt.Errorf("guia %s: SQ_DOC não é um valor fixo (%s), não é possível gerar rollback exato", key["NR_GUIA"], key["SQ_DOC"])
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("nenhuma linha de FarrDarmsPagos encontrada no script")
+	}
+	return keys, nil
+}
+
+// RenderRollbackScript gera os DELETEs que desfazem exatamente as linhas inseridas
+func RenderRollbackScript(keys []ParsedRow, opts ScriptOptions) string {
+	d := opts.Insert.EffectiveDialect()
+	sections := []string{d.UseSchema(DefaultSchema)}
+
+	if opts.Comments {
+		sections = append(sections, fmt.Sprintf("-- Rollback de %d registro(s) de INSERT_TODOS_DARMs.sql", len(keys)))
+	}
+	if begin := d.BeginTransaction(); opts.UseTransaction && begin != "" {
+		sections = append(sections, begin)
+	}
+
+	deletes := make([]string, 0, len(keys))
+	for _, key := range keys {
+		conditions := make([]string, 0, len(rollbackColumns))
+		for _, column := range rollbackColumns {
+			conditions = append(conditions, fmt.Sprintf("%s = %s", d.QuoteIdentifier(column), key[column]))
+		}
+		deletes = append(deletes, fmt.Sprintf("DELETE FROM %s\nWHERE %s;", d.QuoteIdentifier(farrDarmsPagosTable), strings.Join(conditions, " AND ")))
+	}
+	sections = append(sections, strings.Join(deletes, "\n"))
+
+	if opts.UseTransaction {
+		sections = append(sections, "COMMIT;")
+	}
+
+	return strings.Join(sections, "\n\n") + "\n"
+}
+
+// WriteRollbackScript grava o script de rollback ao lado do script de INSERT
+func WriteRollbackScript(path string, keys []ParsedRow, opts ScriptOptions) error {
+	if err := os.WriteFile(path, []byte(RenderRollbackScript(keys, opts)), 0644); err != nil {
+		return fmt.Errorf("erro ao gerar script de rollback: %v", err)
+	}
+	logrus.Infof("↩️ Script de rollback gerado: %s (%d registros)", filepath.Base(path), len(keys))
+	return nil
+}
@@ -0,0 +1,120 @@
+package sqlgen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gerador-query-darm-go/internal/darmtest"
+	"gerador-query-darm-go/validation"
+)
+
+func TestParseInsertScriptRoundTrip(t *testing.T) {
+	rows := testRows(t, 4)
+	rows[1]["NR_INSCRICAO"] = "O'Connor, (filial)"
+
+	for _, strategy := range []ConflictStrategy{ConflictInsert, ConflictIgnore, ConflictUpdate, ConflictNotExists} {
+		script, err := renderConsolidatedScript(rows, ScriptOptions{
+			Insert:         InsertOptions{Conflict: strategy},
+			BatchSize:      3,
+			UseTransaction: true,
+			Comments:       true,
+		})
+		if err != nil {
+			t.Fatalf("%s: renderConsolidatedScript falhou: %v", strategy, err)
+		}
+
+		inserts, err := parseInsertScript(script)
+		if err != nil {
+			t.Fatalf("%s: parseInsertScript falhou: %v", strategy, err)
+		}
+
+		parsed := []ParsedRow{}
+		for _, insert := range inserts {
+			parsed = append(parsed, insert.Rows...)
+		}
+		if len(parsed) != len(rows) {
+			t.Fatalf("%s: esperadas %d linhas, obtidas %d", strategy, len(rows), len(parsed))
+		}
+
+		for i, row := range rows {
+			values, _ := row.SQLValues()
+			for j, column := range farrDarmsPagosColumns {
+				if parsed[i][column] != values[j] {
+					t.Errorf("%s: linha %d coluna %s = %q, esperado %q", strategy, i, column, parsed[i][column], values[j])
+				}
+			}
+		}
+	}
+}
+
+func TestRollbackFromScriptMatchesRows(t *testing.T) {
+	rows := testRows(t, 5)
+	opts := ScriptOptions{Insert: InsertOptions{Conflict: ConflictIgnore}, BatchSize: 2, UseTransaction: true}
+
+	script, err := renderConsolidatedScript(rows, opts)
+	if err != nil {
+		t.Fatalf("renderConsolidatedScript falhou: %v", err)
+	}
+
+	keys, err := RollbackKeysFromScript(script)
+	if err != nil {
+		t.Fatalf("RollbackKeysFromScript falhou: %v", err)
+	}
+	if !reflect.DeepEqual(keys, RollbackKeysFromRows(rows)) {
+		t.Errorf("chaves do script diferem das chaves das linhas:\n%v\n%v", keys, RollbackKeysFromRows(rows))
+	}
+
+	rollback := RenderRollbackScript(keys, opts)
+	if n := strings.Count(rollback, "DELETE FROM FarrDarmsPagos"); n != 5 {
+		t.Errorf("esperados 5 DELETEs, obtidos %d", n)
+	}
+	expected := "WHERE AA_EXERCICIO = 2025 AND CD_BANCO = 70 AND NR_BDA = 37 AND NR_COMPLEMENTO = 0 AND NR_LOTE_NSA = 730 AND TP_LOTE_D = 1 AND NR_GUIA = 3 AND SQ_DOC = 3000;"
+	if !strings.Contains(rollback, expected) {
+		t.Errorf("rollback deveria conter %q:\n%s", expected, rollback)
+	}
+	if !strings.Contains(rollback, "START TRANSACTION;") || !strings.HasSuffix(rollback, "COMMIT;\n") {
+		t.Errorf("rollback deveria respeitar use_transaction:\n%s", rollback)
+	}
+}
+
+func TestRollbackRejectsDynamicSQDoc(t *testing.T) {
+	record, err := validation.Parse(darmtest.DarmData("0007.pdf"), validation.Options{})
+	if err != nil {
+		t.Fatalf("Parse falhou: %v", err)
+	}
+	sql, err := RenderInsert(RowFromRecord(record), InsertOptions{})
+	if err != nil {
+		t.Fatalf("RenderInsert falhou: %v", err)
+	}
+	if _, err := RollbackKeysFromScript(sql); err == nil || !strings.Contains(err.Error(), "SQ_DOC") {
+		t.Errorf("SQ_DOC dinâmico deveria ser rejeitado, obtido %v", err)
+	}
+}
+
+func TestLiteralValue(t *testing.T) {
+	date := time.Date(2024, 12, 15, 0, 0, 0, 0, time.UTC)
+	for _, d := range []Dialect{MySQLDialect{}, PostgresDialect{}, SQLServerDialect{}, OracleDialect{}} {
+		value, null, ok := LiteralValue(d.FormatValue(date))
+		if !ok || null || !strings.HasPrefix(value, "2024-12-15") {
+			t.Errorf("%s: data interpretada como %q (null=%v, ok=%v)", d.Name(), value, null, ok)
+		}
+		if _, _, ok := LiteralValue(d.FormatValue(SQLNow{})); ok {
+			t.Errorf("%s: data/hora atual não é literal", d.Name())
+		}
+		if _, _, ok := LiteralValue(d.FormatValue(SQLDocSequence{Guia: 7})); ok {
+			t.Errorf("%s: SQ_DOC dinâmico não é literal", d.Name())
+		}
+	}
+
+	cases := map[string]string{"'O''Connor'": "O'Connor", "1234.56": "1234.56", "-7": "-7"}
+	for literal, expected := range cases {
+		if value, null, ok := LiteralValue(literal); !ok || null || value != expected {
+			t.Errorf("%s: obtido %q, esperado %q", literal, value, expected)
+		}
+	}
+	if _, null, ok := LiteralValue("NULL"); !ok || !null {
+		t.Error("NULL deveria ser nulo")
+	}
+}
@@ -0,0 +1,350 @@
+package sqlgen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Regex para reconhecer os INSERTs gerados pelo processador
+var (
+	insertPrefixRegex  = regexp.MustCompile(`(?is)^INSERT\s+(?:IGNORE\s+)?INTO\s+([\w.` + "`" + `"\[\]]+)\s*\(`)
+	valuesKeywordRegex = regexp.MustCompile(`(?is)^\s*VALUES\s*`)
+	selectKeywordRegex = regexp.MustCompile(`(?is)^\s*SELECT\s+`)
+	selectEndRegex     = regexp.MustCompile(`(?is)\s+(?:FROM\s+DUAL|WHERE\s+NOT\s+EXISTS)\b`)
+	mergePrefixRegex   = regexp.MustCompile(`(?is)^MERGE\s+INTO\s+([\w.` + "`" + `"\[\]]+)\s+\w+\s+USING\s*`)
+	columnAliasRegex   = regexp.MustCompile(`(?is)^(.*?)\s+AS\s+([\w` + "`" + `"\[\]]+)$`)
+)
+
+// Regex dos literais gerados pelos dialetos
+var (
+	stringLiteralRegex    = regexp.MustCompile(`(?s)^'((?:[^']|'')*)'$`)
+	timestampLiteralRegex = regexp.MustCompile(`(?is)^TIMESTAMP\s+'([^']*)'$`)
+	toDateLiteralRegex    = regexp.MustCompile(`(?is)^TO_DATE\(\s*'([^']*)'\s*,\s*'[^']*'\s*\)$`)
+	numberLiteralRegex    = regexp.MustCompile(`^-?\d+(?:\.\d+)?$`)
+)
+
+// ParsedRow é uma linha lida de um INSERT (coluna → literal SQL sem alteração)
+type ParsedRow map[string]string
+
+// ParsedInsert é um comando INSERT lido de um script
+type ParsedInsert struct {
+	Table   string
+	Columns []string
+	Rows    []ParsedRow
+}
+
+// splitSQLStatements divide um script em comandos terminados por ';',
+// ignorando comentários de linha e respeitando strings e parênteses
+func splitSQLStatements(script string) []string {
+	statements := []string{}
+	var current strings.Builder
+	inString := false
+	depth := 0
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+
+		if inString {
+			current.WriteByte(c)
+			if c == '\'' {
+				if i+1 < len(script) && script[i+1] == '\'' {
+					current.WriteByte(script[i+1])
+					i++
+				} else {
+					inString = false
+				}
+			}
+			continue
+		}
+
+		switch {
+		case c == '-' && i+1 < len(script) && script[i+1] == '-':
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+			current.WriteByte('\n')
+		case c == '\'':
+			inString = true
+			current.WriteByte(c)
+		case c == '(':
+			depth++
+			current.WriteByte(c)
+		case c == ')':
+			depth--
+			current.WriteByte(c)
+		case c == ';' && depth == 0:
+			if stmt := strings.TrimSpace(current.String()); stmt != "" {
+				statements = append(statements, stmt)
+			}
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	if stmt := strings.TrimSpace(current.String()); stmt != "" {
+		statements = append(statements, stmt)
+	}
+	return statements
+}
+
+// readParenthesized lê o conteúdo entre o '(' em s[0] e o ')' correspondente,
+// retornando o conteúdo e o restante do texto
+func readParenthesized(s string) (string, string, error) {
+	if s == "" || s[0] != '(' {
+		return "", "", fmt.Errorf("esperado '(' em %q", truncate(s, 20))
+	}
+
+	depth := 0
+	inString := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			if c == '\'' {
+				if i+1 < len(s) && s[i+1] == '\'' {
+					i++
+				} else {
+					inString = false
+				}
+			}
+			continue
+		}
+		switch c {
+		case '\'':
+			inString = true
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[1:i], s[i+1:], nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("parêntese não fechado em %q", truncate(s, 20))
+}
+
+// splitSQLList divide uma lista SQL nas vírgulas de nível zero
+func splitSQLList(s string) []string {
+	items := []string{}
+	var current strings.Builder
+	inString := false
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			current.WriteByte(c)
+			if c == '\'' {
+				if i+1 < len(s) && s[i+1] == '\'' {
+					current.WriteByte(s[i+1])
+					i++
+				} else {
+					inString = false
+				}
+			}
+			continue
+		}
+		switch {
+		case c == '\'':
+			inString = true
+			current.WriteByte(c)
+		case c == '(':
+			depth++
+			current.WriteByte(c)
+		case c == ')':
+			depth--
+			current.WriteByte(c)
+		case c == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	items = append(items, strings.TrimSpace(current.String()))
+	return items
+}
+
+// truncate limita o texto usado em mensagens de erro
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n] + "..."
+	}
+	return s
+}
+
+// newParsedRow associa os valores às colunas, exigindo a mesma quantidade
+func newParsedRow(columns, values []string) (ParsedRow, error) {
+	if len(values) != len(columns) {
+		return nil, fmt.Errorf("linha com %d valores, esperadas %d colunas", len(values), len(columns))
+	}
+	row := make(ParsedRow, len(columns))
+	for i, column := range columns {
+		row[column] = values[i]
+	}
+	return row, nil
+}
+
+// parseMergeStatement interpreta o MERGE ... USING (SELECT valor AS coluna, ...)
+// gerado para a estratégia update nos dialetos sem ON DUPLICATE KEY/ON CONFLICT
+func parseMergeStatement(stmt string) (*ParsedInsert, error) {
+	match := mergePrefixRegex.FindStringSubmatchIndex(stmt)
+	if match == nil {
+		return nil, fmt.Errorf("MERGE sem USING")
+	}
+
+	source, _, err := readParenthesized(stmt[match[1]:])
+	if err != nil {
+		return nil, err
+	}
+	if !selectKeywordRegex.MatchString(source) {
+		return nil, fmt.Errorf("MERGE sem USING (SELECT ...)")
+	}
+	source = selectKeywordRegex.ReplaceAllString(source, "")
+	if loc := selectEndRegex.FindStringIndex(source); loc != nil {
+		source = source[:loc[0]]
+	}
+
+	insert := &ParsedInsert{Table: stmt[match[2]:match[3]]}
+	values := []string{}
+	for _, item := range splitSQLList(source) {
+		alias := columnAliasRegex.FindStringSubmatch(item)
+		if alias == nil {
+			return nil, fmt.Errorf("valor sem alias de coluna no MERGE: %q", truncate(item, 20))
+		}
+		values = append(values, strings.TrimSpace(alias[1]))
+		insert.Columns = append(insert.Columns, unquoteIdentifier(alias[2]))
+	}
+
+	row, err := newParsedRow(insert.Columns, values)
+	if err != nil {
+		return nil, err
+	}
+	insert.Rows = append(insert.Rows, row)
+	return insert, nil
+}
+
+// parseInsertStatement interpreta um INSERT ... VALUES, um INSERT ... SELECT
+// (com ou sem FROM DUAL) ou um MERGE gerado pelo processador
+func parseInsertStatement(stmt string) (*ParsedInsert, error) {
+	if strings.HasPrefix(strings.ToUpper(stmt), "MERGE") {
+		return parseMergeStatement(stmt)
+	}
+
+	match := insertPrefixRegex.FindStringSubmatchIndex(stmt)
+	if match == nil {
+		return nil, nil
+	}
+
+	insert := &ParsedInsert{Table: stmt[match[2]:match[3]]}
+	columnList, rest, err := readParenthesized(stmt[match[1]-1:])
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range splitSQLList(columnList) {
+		insert.Columns = append(insert.Columns, unquoteIdentifier(column))
+	}
+
+	switch {
+	case valuesKeywordRegex.MatchString(rest):
+		rest = valuesKeywordRegex.ReplaceAllString(rest, "")
+		for {
+			rest = strings.TrimSpace(rest)
+			var tuple string
+			tuple, rest, err = readParenthesized(rest)
+			if err != nil {
+				return nil, err
+			}
+			row, err := newParsedRow(insert.Columns, splitSQLList(tuple))
+			if err != nil {
+				return nil, fmt.Errorf("linha %d: %v", len(insert.Rows)+1, err)
+			}
+			insert.Rows = append(insert.Rows, row)
+
+			rest = strings.TrimSpace(rest)
+			if !strings.HasPrefix(rest, ",") {
+				break
+			}
+			rest = rest[1:]
+		}
+	case selectKeywordRegex.MatchString(rest):
+		rest = selectKeywordRegex.ReplaceAllString(rest, "")
+		loc := selectEndRegex.FindStringIndex(rest)
+		if loc == nil {
+			return nil, fmt.Errorf("INSERT ... SELECT sem FROM DUAL ou WHERE NOT EXISTS")
+		}
+		row, err := newParsedRow(insert.Columns, splitSQLList(rest[:loc[0]]))
+		if err != nil {
+			return nil, err
+		}
+		insert.Rows = append(insert.Rows, row)
+	default:
+		return nil, fmt.Errorf("INSERT sem VALUES ou SELECT")
+	}
+
+	return insert, nil
+}
+
+// unquoteIdentifier remove os delimitadores de identificador de qualquer
+// dialeto (`nome`, "nome" ou [nome])
+func unquoteIdentifier(name string) string {
+	return strings.Trim(name, "`\"[]")
+}
+
+// parseInsertScript lê todos os INSERTs de um script SQL gerado pelo processador
+func parseInsertScript(script string) ([]*ParsedInsert, error) {
+	inserts := []*ParsedInsert{}
+	for i, stmt := range splitSQLStatements(script) {
+		insert, err := parseInsertStatement(stmt)
+		if err != nil {
+			return nil, fmt.Errorf("comando %d: %v", i+1, err)
+		}
+		if insert != nil {
+			inserts = append(inserts, insert)
+		}
+	}
+	return inserts, nil
+}
+
+// ParseFarrDarmsPagos lê as linhas de FarrDarmsPagos dos INSERTs (ou MERGEs) de
+// um script gerado pelo processador, ignorando comandos de outras tabelas
+func ParseFarrDarmsPagos(script string) ([]ParsedRow, error) {
+	inserts, err := parseInsertScript(script)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []ParsedRow{}
+	for _, insert := range inserts {
+		if strings.EqualFold(unquoteIdentifier(insert.Table), farrDarmsPagosTable) {
+			rows = append(rows, insert.Rows...)
+		}
+	}
+	return rows, nil
+}
+
+// LiteralValue converte um literal SQL de qualquer dialeto no valor sem
+// formatação: strings sem aspas, datas de TIMESTAMP '...' e TO_DATE('...', ...)
+// e números como escritos. NULL retorna null = true; expressões calculadas no
+// banco (NOW(), SQ_DOC dinâmico) não são literais e retornam ok = false.
+func LiteralValue(literal string) (value string, null bool, ok bool) {
+	literal = strings.TrimSpace(literal)
+	switch {
+	case strings.EqualFold(literal, "NULL"):
+		return "", true, true
+	case numberLiteralRegex.MatchString(literal):
+		return literal, false, true
+	}
+	if match := stringLiteralRegex.FindStringSubmatch(literal); match != nil {
+		return strings.ReplaceAll(match[1], "''", "'"), false, true
+	}
+	if match := timestampLiteralRegex.FindStringSubmatch(literal); match != nil {
+		return match[1], false, true
+	}
+	if match := toDateLiteralRegex.FindStringSubmatch(literal); match != nil {
+		return match[1], false, true
+	}
+	return "", false, false
+}
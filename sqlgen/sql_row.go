@@ -0,0 +1,434 @@
+// Package sqlgen gera os scripts SQL de FarrDarmsPagos (INSERT, verificação,
+// rollback e carga em massa) para os dialetos suportados.
+package sqlgen
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gerador-query-darm-go/validation"
+)
+
+// farrDarmsPagosTable é o nome da tabela dos DARMs pagos
+const farrDarmsPagosTable = "FarrDarmsPagos"
+
+// farrDarmsPagosColumns lista as colunas de FarrDarmsPagos na ordem do INSERT
+var farrDarmsPagosColumns = []string{
+	"id", "AA_EXERCICIO", "CD_BANCO", "NR_BDA", "NR_COMPLEMENTO", "NR_LOTE_NSA", "TP_LOTE_D",
+	"SQ_DOC", "CD_RECEITA", "CD_USU_ALT", "CD_USU_INCL", "DT_ALT", "DT_INCL", "DT_VENCTO",
+	"DT_PAGTO", "NR_INSCRICAO", "NR_GUIA", "NR_COMPETENCIA", "NR_CODIGO_BARRAS",
+	"NR_LOTE_IPTU", "ST_DOC_D", "TP_IMPOSTO", "VL_PAGO", "VL_RECEITA", "VL_PRINCIPAL",
+	"VL_MORA", "VL_MULTA", "VL_MULTAF_TCDL", "VL_MULTAP_TSD", "VL_INSU_TIP", "VL_JUROS",
+	"processado", "criticaProcessamento",
+}
+
+// Columns retorna as colunas de FarrDarmsPagos na ordem do INSERT
+func Columns() []string {
+	return append([]string{}, farrDarmsPagosColumns...)
+}
+
+// Agrupamentos usados para quebrar as listas de colunas e de valores em linhas
+var (
+	columnLineGroups = []int{7, 7, 5, 6, 6, 2}
+	valueLineGroups  = []int{7, 5, 3, 4, 6, 6, 2}
+)
+
+// conflictKeyColumns identifica uma guia em FarrDarmsPagos: colunas do lote mais NR_GUIA
+var conflictKeyColumns = []string{
+	"AA_EXERCICIO", "CD_BANCO", "NR_BDA", "NR_COMPLEMENTO", "NR_LOTE_NSA", "TP_LOTE_D", "NR_GUIA",
+}
+
+// defaultUpdateColumns são as colunas atualizadas por ON DUPLICATE KEY UPDATE
+// quando sql.update_columns não é informado
+var defaultUpdateColumns = []string{
+	"CD_RECEITA", "DT_VENCTO", "NR_INSCRICAO", "NR_CODIGO_BARRAS", "VL_PAGO", "VL_RECEITA", "VL_PRINCIPAL",
+}
+
+// ConflictStrategy define o tratamento de guias já existentes no banco
+type ConflictStrategy string
+
+const (
+	// ConflictInsert gera INSERT simples (duplicatas resultam em erro no banco)
+	ConflictInsert ConflictStrategy = "insert"
+	// ConflictIgnore gera INSERT IGNORE
+	ConflictIgnore ConflictStrategy = "ignore"
+	// ConflictUpdate gera INSERT ... ON DUPLICATE KEY UPDATE das colunas selecionadas
+	ConflictUpdate ConflictStrategy = "update"
+	// ConflictNotExists gera INSERT ... SELECT ... WHERE NOT EXISTS pela chave do lote + NR_GUIA
+	ConflictNotExists ConflictStrategy = "not_exists"
+)
+
+// ParseConflictStrategy valida o nome de uma estratégia de conflito
+func ParseConflictStrategy(name string) (ConflictStrategy, error) {
+	switch strategy := ConflictStrategy(strings.ToLower(strings.TrimSpace(name))); strategy {
+	case ConflictInsert, ConflictIgnore, ConflictUpdate, ConflictNotExists:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("estratégia de conflito inválida: %q (use insert, ignore, update ou not_exists)", name)
+}
+
+// Description descreve a estratégia para o relatório
+func (cs ConflictStrategy) Description() string {
+	switch cs {
+	case ConflictIgnore:
+		return "INSERT IGNORE (proteção automática contra duplicatas)"
+	case ConflictUpdate:
+		return "INSERT ... ON DUPLICATE KEY UPDATE (duplicatas são atualizadas)"
+	case ConflictNotExists:
+		return "INSERT ... WHERE NOT EXISTS (guias já existentes no lote são ignoradas)"
+	default:
+		return "INSERT simples (sem proteção contra duplicatas)"
+	}
+}
+
+// InsertOptions controla a forma dos INSERTs gerados
+type InsertOptions struct {
+	// Conflict é a estratégia para guias existentes (vazio = ConflictInsert)
+	Conflict      ConflictStrategy
+	UpdateColumns []string
+	// Dialect é o banco de destino (nil = MySQL)
+	Dialect Dialect
+}
+
+// EffectiveDialect retorna o dialeto dos INSERTs, MySQL por padrão
+func (o InsertOptions) EffectiveDialect() Dialect {
+	if o.Dialect == nil {
+		return MySQLDialect{}
+	}
+	return o.Dialect
+}
+
+// updateColumns retorna as colunas atualizadas em caso de duplicata
+func (o InsertOptions) updateColumns() []string {
+	if len(o.UpdateColumns) > 0 {
+		return o.UpdateColumns
+	}
+	return defaultUpdateColumns
+}
+
+// Validate verifica se as colunas de atualização existem em FarrDarmsPagos e
+// não fazem parte da chave da guia (o MERGE do Oracle e do SQL Server não
+// pode atualizar colunas usadas no ON; o Oracle recusa com ORA-38104)
+func (o InsertOptions) Validate() error {
+	if o.Conflict != "" {
+		if _, err := ParseConflictStrategy(string(o.Conflict)); err != nil {
+			return err
+		}
+	}
+
+	known := make(map[string]bool, len(farrDarmsPagosColumns))
+	for _, column := range farrDarmsPagosColumns {
+		known[column] = true
+	}
+	key := make(map[string]bool, len(conflictKeyColumns))
+	for _, column := range conflictKeyColumns {
+		key[column] = true
+	}
+	for _, column := range o.UpdateColumns {
+		if !known[column] {
+			return fmt.Errorf("coluna de atualização desconhecida: %s", column)
+		}
+		if key[column] {
+			return fmt.Errorf("coluna de atualização %s faz parte da chave da guia (%s) e não pode ser atualizada", column, strings.Join(conflictKeyColumns, ", "))
+		}
+	}
+	return nil
+}
+
+// loteColumns são as colunas do lote de arrecadação em FarrDarmsPagos
+var loteColumns = []string{"CD_BANCO", "NR_BDA", "NR_COMPLEMENTO", "NR_LOTE_NSA", "TP_LOTE_D"}
+
+// LoteColumns retorna as colunas do lote de arrecadação (as de SetLote)
+func LoteColumns() []string {
+	return append([]string{}, loteColumns...)
+}
+
+// LoteArrecadacao identifica o lote de arrecadação de FarrDarmsPagos
+type LoteArrecadacao struct {
+	CodigoBanco int
+	NumeroBDA   int
+	Complemento int
+	NSA         int
+	Tipo        int
+}
+
+// defaultLote é o lote utilizado para os DARMs extraídos de PDF
+var defaultLote = LoteArrecadacao{
+	CodigoBanco: 70,
+	NumeroBDA:   37,
+	Complemento: 0,
+	NSA:         730,
+	Tipo:        1,
+}
+
+// DefaultLote retorna o lote utilizado para os DARMs extraídos de PDF, sem
+// arquivo de retorno do banco
+func DefaultLote() LoteArrecadacao {
+	return defaultLote
+}
+
+// DarmRow representa uma linha de FarrDarmsPagos (coluna → valor tipado).
+// Os valores aceitos são os tratados por SQLUtils.FormatSQLValue.
+type DarmRow map[string]interface{}
+
+// SetLote substitui as colunas do lote de arrecadação da linha (ex.: pelo
+// lote do arquivo de retorno do banco)
+func (r DarmRow) SetLote(lote LoteArrecadacao) {
+	r["CD_BANCO"] = lote.CodigoBanco
+	r["NR_BDA"] = lote.NumeroBDA
+	r["NR_COMPLEMENTO"] = lote.Complemento
+	r["NR_LOTE_NSA"] = lote.NSA
+	r["TP_LOTE_D"] = lote.Tipo
+}
+
+// SetPagamento substitui o valor e a data de pagamento da linha pelos
+// informados pelo banco (por padrão, VL_PAGO é o valor total do DARM e
+// DT_PAGTO é a data/hora da execução do script)
+func (r DarmRow) SetPagamento(valor validation.Money, data time.Time) {
+	r["VL_PAGO"] = valor
+	r["DT_PAGTO"] = data
+}
+
+// AssignSQDocs fixa o SQ_DOC de cada linha, calculado a partir da guia, do
+// instante da geração e da posição da linha, e retorna os valores atribuídos
+func AssignSQDocs(rows []DarmRow, now time.Time) []int {
+	timestamp := now.UnixNano() / int64(time.Millisecond)
+	sqDocs := make([]int, 0, len(rows))
+	for index, row := range rows {
+		guia, _ := row["NR_GUIA"].(int)
+		sqDoc := ((guia % 1000) * 1000) + (int(timestamp) % 1000) + index
+		row["SQ_DOC"] = sqDoc
+		sqDocs = append(sqDocs, sqDoc)
+	}
+	return sqDocs
+}
+
+// Clone retorna uma cópia da linha
+func (r DarmRow) Clone() DarmRow {
+	clone := make(DarmRow, len(r))
+	for column, value := range r {
+		clone[column] = value
+	}
+	return clone
+}
+
+// SQLValues formata os valores da linha na ordem de farrDarmsPagosColumns (MySQL).
+// Retorna erro se a linha não tiver exatamente uma entrada por coluna.
+func (r DarmRow) SQLValues() ([]string, error) {
+	return r.FormatValues(MySQLDialect{})
+}
+
+// FormatValues formata os valores da linha na ordem das colunas de insertColumns.
+// Retorna erro se a linha não tiver exatamente uma entrada por coluna.
+func (r DarmRow) FormatValues(d Dialect) ([]string, error) {
+	if len(r) != len(farrDarmsPagosColumns) {
+		return nil, fmt.Errorf("linha com %d valores, esperadas %d colunas", len(r), len(farrDarmsPagosColumns))
+	}
+
+	for _, column := range farrDarmsPagosColumns {
+		if _, ok := r[column]; !ok {
+			return nil, fmt.Errorf("coluna %s ausente na linha", column)
+		}
+	}
+
+	columns, _, _ := insertColumns(d)
+	values := make([]string, 0, len(columns))
+	for _, column := range columns {
+		values = append(values, d.FormatValue(r[column]))
+	}
+
+	return values, nil
+}
+
+// insertColumns retorna as colunas do INSERT no dialeto e os agrupamentos de
+// linhas de colunas e de valores. Sem id, o primeiro grupo perde uma coluna.
+func insertColumns(d Dialect) ([]string, []int, []int) {
+	if d.IncludeNullID() {
+		return farrDarmsPagosColumns, columnLineGroups, valueLineGroups
+	}
+
+	shrink := func(groups []int) []int {
+		shrunk := append([]int{}, groups...)
+		shrunk[0]--
+		return shrunk
+	}
+	return farrDarmsPagosColumns[1:], shrink(columnLineGroups), shrink(valueLineGroups)
+}
+
+// formatGroups junta itens separados por vírgula, quebrando a linha ao fim de cada grupo
+func formatGroups(items []string, groups []int, indent string) string {
+	lines := []string{}
+	start := 0
+	for _, size := range groups {
+		if start >= len(items) {
+			break
+		}
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		lines = append(lines, indent+strings.Join(items[start:end], ", "))
+		start = end
+	}
+	if start < len(items) {
+		lines = append(lines, indent+strings.Join(items[start:], ", "))
+	}
+	return strings.Join(lines, ",\n")
+}
+
+// insertHeader retorna o início do INSERT com a lista de colunas
+func insertHeader(opts InsertOptions) string {
+	verb := "INSERT INTO"
+	if opts.Conflict == ConflictIgnore {
+		if ignoreVerb, _, ok := opts.EffectiveDialect().IgnoreClause(); ok {
+			verb = ignoreVerb
+		}
+	}
+	d := opts.EffectiveDialect()
+	columns, columnGroups, _ := insertColumns(d)
+	return fmt.Sprintf("%s %s (\n%s\n)", verb, d.QuoteIdentifier(farrDarmsPagosTable), formatGroups(quoteIdentifiers(d, columns), columnGroups, "    "))
+}
+
+// conflictClause retorna o sufixo do INSERT que trata duplicatas, se aplicável
+func conflictClause(opts InsertOptions) string {
+	d := opts.EffectiveDialect()
+	switch opts.Conflict {
+	case ConflictIgnore:
+		_, suffix, _ := d.IgnoreClause()
+		return suffix
+	case ConflictUpdate:
+		clause, _ := d.UpsertClause(conflictKeyColumns, opts.updateColumns())
+		return clause
+	}
+	return ""
+}
+
+// rendersPerRow indica se a estratégia gera um comando por linha no dialeto:
+// NOT EXISTS (também usado para ignore sem suporte nativo) e MERGE
+func rendersPerRow(opts InsertOptions) bool {
+	d := opts.EffectiveDialect()
+	switch opts.Conflict {
+	case ConflictNotExists:
+		return true
+	case ConflictIgnore:
+		_, _, ok := d.IgnoreClause()
+		return !ok
+	case ConflictUpdate:
+		_, ok := d.UpsertClause(conflictKeyColumns, opts.updateColumns())
+		return !ok
+	}
+	return false
+}
+
+// keyConditions monta as condições de igualdade da chave da guia
+func keyConditions(row DarmRow, d Dialect) []string {
+	conditions := []string{}
+	for _, column := range conflictKeyColumns {
+		conditions = append(conditions, fmt.Sprintf("%s = %s", d.QuoteIdentifier(column), d.FormatValue(row[column])))
+	}
+	return conditions
+}
+
+// renderNotExistsInsert gera INSERT ... SELECT protegido por NOT EXISTS na chave da guia
+func renderNotExistsInsert(row DarmRow, values []string, opts InsertOptions) string {
+	d := opts.EffectiveDialect()
+	_, _, valueGroups := insertColumns(d)
+	notExistsOpts := opts
+	notExistsOpts.Conflict = ConflictNotExists
+
+	from := ""
+	if selectFrom := strings.TrimSpace(d.SelectFrom()); selectFrom != "" {
+		from = selectFrom + "\n"
+	}
+
+	return fmt.Sprintf("%s\nSELECT\n%s\n%sWHERE NOT EXISTS (\n    SELECT 1 FROM %s\n    WHERE %s\n);",
+		insertHeader(notExistsOpts),
+		formatGroups(values, valueGroups, "    "),
+		from,
+		d.QuoteIdentifier(farrDarmsPagosTable),
+		strings.Join(keyConditions(row, d), "\n    AND "))
+}
+
+// renderMergeInsert gera MERGE que atualiza a guia existente ou insere a linha
+// (estratégia update nos dialetos sem ON DUPLICATE KEY/ON CONFLICT)
+func renderMergeInsert(values []string, opts InsertOptions) string {
+	d := opts.EffectiveDialect()
+	columns, columnGroups, valueGroups := insertColumns(d)
+	columns = quoteIdentifiers(d, columns)
+
+	sources := make([]string, 0, len(columns))
+	targets := make([]string, 0, len(columns))
+	for i, column := range columns {
+		sources = append(sources, fmt.Sprintf("%s AS %s", values[i], column))
+		targets = append(targets, "origem."+column)
+	}
+
+	on := []string{}
+	for _, column := range quoteIdentifiers(d, conflictKeyColumns) {
+		on = append(on, fmt.Sprintf("destino.%s = origem.%s", column, column))
+	}
+	assignments := []string{}
+	for _, column := range quoteIdentifiers(d, opts.updateColumns()) {
+		assignments = append(assignments, fmt.Sprintf("    destino.%s = origem.%s", column, column))
+	}
+
+	return fmt.Sprintf("MERGE INTO %s destino\nUSING (\n    SELECT\n%s%s\n) origem\nON (%s)\nWHEN MATCHED THEN UPDATE SET\n%s\nWHEN NOT MATCHED THEN INSERT (\n%s\n) VALUES (\n%s\n);",
+		d.QuoteIdentifier(farrDarmsPagosTable),
+		formatGroups(sources, valueGroups, "        "),
+		d.SelectFrom(),
+		strings.Join(on, "\n    AND "),
+		strings.Join(assignments, ",\n"),
+		formatGroups(columns, columnGroups, "    "),
+		formatGroups(targets, valueGroups, "    "))
+}
+
+// renderRowStatement gera o comando próprio de uma linha (NOT EXISTS ou MERGE)
+func renderRowStatement(row DarmRow, values []string, opts InsertOptions) string {
+	if opts.Conflict == ConflictUpdate {
+		return renderMergeInsert(values, opts)
+	}
+	return renderNotExistsInsert(row, values, opts)
+}
+
+// RenderInsert gera o INSERT de uma linha no formato dos arquivos individuais
+func RenderInsert(row DarmRow, opts InsertOptions) (string, error) {
+	values, err := row.FormatValues(opts.EffectiveDialect())
+	if err != nil {
+		return "", err
+	}
+
+	if rendersPerRow(opts) {
+		return renderRowStatement(row, values, opts), nil
+	}
+
+	_, _, valueGroups := insertColumns(opts.EffectiveDialect())
+	return fmt.Sprintf("%s VALUES (\n%s\n)%s;", insertHeader(opts), formatGroups(values, valueGroups, "    "), conflictClause(opts)), nil
+}
+
+// renderMultiInsert gera um INSERT multi-linha no formato do arquivo único.
+// Com NOT EXISTS ou MERGE, cada linha é um comando próprio.
+func renderMultiInsert(rows []DarmRow, opts InsertOptions) (string, error) {
+	_, _, valueGroups := insertColumns(opts.EffectiveDialect())
+	perRow := rendersPerRow(opts)
+
+	tuples := make([]string, 0, len(rows))
+	for i, row := range rows {
+		values, err := row.FormatValues(opts.EffectiveDialect())
+		if err != nil {
+			return "", fmt.Errorf("linha %d: %v", i+1, err)
+		}
+
+		if perRow {
+			tuples = append(tuples, renderRowStatement(row, values, opts))
+			continue
+		}
+		tuples = append(tuples, fmt.Sprintf("    (\n%s\n    )", formatGroups(values, valueGroups, "        ")))
+	}
+
+	if perRow {
+		return strings.Join(tuples, "\n\n"), nil
+	}
+
+	return fmt.Sprintf("%s VALUES\n%s%s;", insertHeader(opts), strings.Join(tuples, ",\n"), conflictClause(opts)), nil
+}
@@ -0,0 +1,157 @@
+package sqlgen
+
+import (
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/validation"
+)
+
+func testRow(t *testing.T) DarmRow {
+	t.Helper()
+	record, err := validation.Parse(&extraction.DarmData{
+		Inscricao:      "123,456",
+		CodigoReceita:  "2623",
+		ValorPrincipal: "1.234,56",
+		DataVencimento: "15/12/2024",
+		Exercicio:      "2025",
+		NumeroGuia:     "123",
+	}, validation.Options{})
+	if err != nil {
+		t.Fatalf("Parse falhou: %v", err)
+	}
+	return RowFromRecord(record)
+}
+
+func TestDarmRowArity(t *testing.T) {
+	row := testRow(t)
+	if _, err := row.SQLValues(); err != nil {
+		t.Fatalf("linha completa não deveria falhar: %v", err)
+	}
+
+	missing := row.Clone()
+	delete(missing, "VL_JUROS")
+	if _, err := RenderInsert(missing, InsertOptions{Conflict: ConflictInsert}); err == nil {
+		t.Error("linha sem coluna deveria falhar")
+	}
+
+	extra := row.Clone()
+	extra["COLUNA_EXTRA"] = 1
+	if _, err := renderMultiInsert([]DarmRow{row, extra}, InsertOptions{Conflict: ConflictInsert}); err == nil || !strings.Contains(err.Error(), "linha 2") {
+		t.Errorf("linha com coluna extra deveria falhar indicando a linha, obtido %v", err)
+	}
+
+	renamed := row.Clone()
+	delete(renamed, "VL_JUROS")
+	renamed["VL_JURO"] = validation.Money(0)
+	if _, err := renamed.SQLValues(); err == nil || !strings.Contains(err.Error(), "VL_JUROS") {
+		t.Errorf("coluna ausente deveria ser informada, obtido %v", err)
+	}
+}
+
+func TestDarmRowSetLote(t *testing.T) {
+	row := testRow(t)
+	if row["CD_BANCO"] != DefaultLote().CodigoBanco || row["NR_LOTE_NSA"] != DefaultLote().NSA {
+		t.Fatalf("linha deveria usar o lote padrão: %v %v", row["CD_BANCO"], row["NR_LOTE_NSA"])
+	}
+
+	row.SetLote(LoteArrecadacao{CodigoBanco: 104, NumeroBDA: 12, NSA: 731, Tipo: 1})
+	values, err := row.SQLValues()
+	if err != nil {
+		t.Fatalf("SQLValues falhou: %v", err)
+	}
+	if strings.Join(values[1:7], ",") != "2025,104,12,0,731,1" {
+		t.Errorf("colunas do lote inesperadas: %v", values[1:7])
+	}
+}
+
+func TestRenderMultiInsertKeepsCommaValues(t *testing.T) {
+	row := testRow(t)
+	row["SQ_DOC"] = 123456
+
+	sql, err := renderMultiInsert([]DarmRow{row, row}, InsertOptions{Conflict: ConflictInsert})
+	if err != nil {
+		t.Fatalf("renderMultiInsert falhou: %v", err)
+	}
+
+	if strings.Count(sql, "'123,456', 123, ") != 2 {
+		t.Errorf("valores com vírgula deveriam ser preservados:\n%s", sql)
+	}
+	if !strings.Contains(sql, "NULL, 2025, 70, 37, 0, 730, 1,\n        123456, 2623,") {
+		t.Errorf("SQ_DOC calculado deveria ocupar a 8ª coluna:\n%s", sql)
+	}
+}
+
+func TestConflictStrategies(t *testing.T) {
+	row := testRow(t)
+	row["SQ_DOC"] = 123456
+
+	tests := []struct {
+		opts     InsertOptions
+		contains []string
+		absent   []string
+	}{
+		{
+			opts:     InsertOptions{Conflict: ConflictInsert},
+			contains: []string{"INSERT INTO FarrDarmsPagos (", ") VALUES"},
+			absent:   []string{"IGNORE", "ON DUPLICATE", "NOT EXISTS"},
+		},
+		{
+			opts:     InsertOptions{Conflict: ConflictIgnore},
+			contains: []string{"INSERT IGNORE INTO FarrDarmsPagos ("},
+			absent:   []string{"ON DUPLICATE", "NOT EXISTS"},
+		},
+		{
+			opts:     InsertOptions{Conflict: ConflictUpdate, UpdateColumns: []string{"VL_PAGO", "DT_VENCTO"}},
+			contains: []string{"ON DUPLICATE KEY UPDATE\n    VL_PAGO = VALUES(VL_PAGO),\n    DT_VENCTO = VALUES(DT_VENCTO);"},
+			absent:   []string{"IGNORE", "VL_PRINCIPAL = VALUES"},
+		},
+		{
+			opts: InsertOptions{Conflict: ConflictNotExists},
+			contains: []string{
+				"SELECT\n    NULL, 2025",
+				"FROM DUAL\nWHERE NOT EXISTS (",
+				"WHERE AA_EXERCICIO = 2025\n    AND CD_BANCO = 70\n    AND NR_BDA = 37\n    AND NR_COMPLEMENTO = 0\n    AND NR_LOTE_NSA = 730\n    AND TP_LOTE_D = 1\n    AND NR_GUIA = 123\n);",
+			},
+			absent: []string{"VALUES", "IGNORE"},
+		},
+	}
+
+	for _, test := range tests {
+		single, err := RenderInsert(row, test.opts)
+		if err != nil {
+			t.Fatalf("%s: RenderInsert falhou: %v", test.opts.Conflict, err)
+		}
+		multi, err := renderMultiInsert([]DarmRow{row, row}, test.opts)
+		if err != nil {
+			t.Fatalf("%s: renderMultiInsert falhou: %v", test.opts.Conflict, err)
+		}
+
+		for _, sql := range []string{single, multi} {
+			for _, expected := range test.contains {
+				if !strings.Contains(sql, expected) {
+					t.Errorf("%s: SQL deveria conter %q:\n%s", test.opts.Conflict, expected, sql)
+				}
+			}
+			for _, unexpected := range test.absent {
+				if strings.Contains(sql, unexpected) {
+					t.Errorf("%s: SQL não deveria conter %q:\n%s", test.opts.Conflict, unexpected, sql)
+				}
+			}
+		}
+	}
+
+	if err := (InsertOptions{Conflict: ConflictUpdate, UpdateColumns: []string{"NAO_EXISTE"}}).Validate(); err == nil {
+		t.Error("coluna de atualização desconhecida deveria ser rejeitada")
+	}
+	for _, column := range []string{"NR_GUIA", "AA_EXERCICIO", "CD_BANCO"} {
+		opts := InsertOptions{Conflict: ConflictUpdate, UpdateColumns: []string{"VL_PAGO", column}, Dialect: OracleDialect{}}
+		if err := opts.Validate(); err == nil || !strings.Contains(err.Error(), "chave da guia") {
+			t.Errorf("coluna da chave %s deveria ser rejeitada em update_columns: %v", column, err)
+		}
+	}
+	if _, err := ParseConflictStrategy("replace"); err == nil {
+		t.Error("estratégia desconhecida deveria ser rejeitada")
+	}
+}
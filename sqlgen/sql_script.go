@@ -0,0 +1,138 @@
+package sqlgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ScriptOptions controla a montagem do script consolidado INSERT_TODOS_DARMs.sql
+type ScriptOptions struct {
+	Insert InsertOptions
+	// BatchSize é o número máximo de linhas por INSERT (0 = todas em um único INSERT)
+	BatchSize int
+	// UseTransaction envolve os INSERTs em START TRANSACTION/COMMIT
+	UseTransaction bool
+	// Comments inclui comentários explicativos; desligado mantém o formato Control-M
+	Comments bool
+}
+
+// EffectiveBatchSize retorna o tamanho efetivo dos lotes, respeitando o limite de
+// linhas por INSERT do dialeto
+func (o ScriptOptions) EffectiveBatchSize() int {
+	limit := o.Insert.EffectiveDialect().MaxRowsPerInsert()
+	if limit > 0 && (o.BatchSize <= 0 || o.BatchSize > limit) {
+		return limit
+	}
+	return o.BatchSize
+}
+
+// SplitBatches divide as linhas em lotes de no máximo size linhas
+func SplitBatches(rows []DarmRow, size int) [][]DarmRow {
+	if size <= 0 || size >= len(rows) {
+		return [][]DarmRow{rows}
+	}
+
+	batches := [][]DarmRow{}
+	for start := 0; start < len(rows); start += size {
+		end := start + size
+		if end > len(rows) {
+			end = len(rows)
+		}
+		batches = append(batches, rows[start:end])
+	}
+	return batches
+}
+
+// guiasCondition monta a condição que seleciona as guias das linhas,
+// agrupando-as pela chave do lote (colunas de conflictKeyColumns exceto NR_GUIA)
+func guiasCondition(rows []DarmRow, d Dialect) string {
+	loteColumns := conflictKeyColumns[:len(conflictKeyColumns)-1]
+
+	order := []string{}
+	guiasByLote := map[string][]string{}
+	for _, row := range rows {
+		conditions := []string{}
+		for _, column := range loteColumns {
+			conditions = append(conditions, fmt.Sprintf("%s = %s", d.QuoteIdentifier(column), d.FormatValue(row[column])))
+		}
+		key := strings.Join(conditions, " AND ")
+		if _, ok := guiasByLote[key]; !ok {
+			order = append(order, key)
+		}
+		guiasByLote[key] = append(guiasByLote[key], d.FormatValue(row["NR_GUIA"]))
+	}
+
+	groups := []string{}
+	for _, key := range order {
+		groups = append(groups, fmt.Sprintf("(%s AND %s IN (%s))", key, d.QuoteIdentifier("NR_GUIA"), strings.Join(guiasByLote[key], ", ")))
+	}
+	return strings.Join(groups, "\n    OR ")
+}
+
+// renderCountQuery gera a consulta de contagem das guias do script
+func renderCountQuery(rows []DarmRow, alias string, d Dialect) string {
+	return fmt.Sprintf("SELECT COUNT(*) AS %s FROM %s\nWHERE %s;", alias, d.QuoteIdentifier(farrDarmsPagosTable), guiasCondition(rows, d))
+}
+
+// RenderCheckQuery gera a consulta que lista, em um único comando, quais guias
+// das linhas já existem no banco (guia, SQ_DOC e data de inclusão)
+func RenderCheckQuery(rows []DarmRow, d Dialect) string {
+	guia, sqDoc, dtIncl := d.QuoteIdentifier("NR_GUIA"), d.QuoteIdentifier("SQ_DOC"), d.QuoteIdentifier("DT_INCL")
+	return fmt.Sprintf("%s\n\nSELECT %s, %s, %s FROM %s\nWHERE %s\nORDER BY %s, %s;\n",
+		d.UseSchema(DefaultSchema), guia, sqDoc, dtIncl, d.QuoteIdentifier(farrDarmsPagosTable), guiasCondition(rows, d), guia, sqDoc)
+}
+
+// renderConsolidatedScript gera o script consolidado: contagem prévia, INSERTs
+// em lotes (opcionalmente em transação) e contagem posterior
+func renderConsolidatedScript(rows []DarmRow, opts ScriptOptions) (string, error) {
+	d := opts.Insert.EffectiveDialect()
+	batches := SplitBatches(rows, opts.EffectiveBatchSize())
+	sections := []string{d.UseSchema(DefaultSchema)}
+
+	comment := func(format string, args ...interface{}) string {
+		if !opts.Comments {
+			return ""
+		}
+		return "-- " + fmt.Sprintf(format, args...) + "\n"
+	}
+
+	sections = append(sections, comment("Guias do lote já existentes antes da inserção")+renderCountQuery(rows, "total_antes", d))
+
+	// Sem comando de início (Oracle), a transação é implícita e termina no COMMIT
+	if begin := d.BeginTransaction(); opts.UseTransaction && begin != "" {
+		sections = append(sections, comment("Em caso de erro o COMMIT não é executado e a transação é desfeita")+begin)
+	}
+
+	for i, batch := range batches {
+		insert, err := renderMultiInsert(batch, opts.Insert)
+		if err != nil {
+			return "", fmt.Errorf("lote %d: %v", i+1, err)
+		}
+		sections = append(sections, comment("Lote %d de %d (%d registros)", i+1, len(batches), len(batch))+insert)
+	}
+
+	if opts.UseTransaction {
+		sections = append(sections, "COMMIT;")
+	}
+
+	sections = append(sections, comment("Guias do lote existentes após a inserção (esperado: %d)", len(rows))+renderCountQuery(rows, "total_depois", d))
+
+	return strings.Join(sections, "\n\n") + "\n", nil
+}
+
+// Generate gera o script consolidado (INSERT_TODOS_DARMs.sql) das linhas.
+// Linhas com SQ_DOC dinâmico (SQLDocSequence) não podem ser desfeitas pelo
+// rollback: atribua valores fixos antes de gerar o script de produção.
+func Generate(rows []DarmRow, opts ScriptOptions) ([]byte, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("nenhuma linha para gerar o script")
+	}
+	if err := opts.Insert.Validate(); err != nil {
+		return nil, err
+	}
+	script, err := renderConsolidatedScript(rows, opts)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(script), nil
+}
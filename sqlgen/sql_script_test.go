@@ -0,0 +1,110 @@
+package sqlgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func testRows(t *testing.T, n int) []DarmRow {
+	t.Helper()
+	base := testRow(t)
+	rows := make([]DarmRow, 0, n)
+	for i := 1; i <= n; i++ {
+		row := base.Clone()
+		row["NR_GUIA"] = i
+		row["SQ_DOC"] = i * 1000
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestRenderConsolidatedScriptBatches(t *testing.T) {
+	rows := testRows(t, 5)
+
+	script, err := renderConsolidatedScript(rows, ScriptOptions{
+		Insert:         InsertOptions{Conflict: ConflictIgnore},
+		BatchSize:      2,
+		UseTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("renderConsolidatedScript falhou: %v", err)
+	}
+
+	if n := strings.Count(script, "INSERT IGNORE INTO FarrDarmsPagos"); n != 3 {
+		t.Errorf("esperados 3 lotes, obtidos %d:\n%s", n, script)
+	}
+	if strings.Contains(script, "--") {
+		t.Error("script sem comentários não deveria conter --")
+	}
+
+	order := []string{"use silfae;", "AS total_antes", "START TRANSACTION;", "INSERT IGNORE", "COMMIT;", "AS total_depois"}
+	last := -1
+	for _, part := range order {
+		index := strings.Index(script, part)
+		if index <= last {
+			t.Fatalf("%q fora de ordem no script:\n%s", part, script)
+		}
+		last = index
+	}
+
+	condition := "(AA_EXERCICIO = 2025 AND CD_BANCO = 70 AND NR_BDA = 37 AND NR_COMPLEMENTO = 0 AND NR_LOTE_NSA = 730 AND TP_LOTE_D = 1 AND NR_GUIA IN (1, 2, 3, 4, 5))"
+	if strings.Count(script, condition) != 2 {
+		t.Errorf("contagens antes/depois deveriam filtrar pelo lote e guias:\n%s", script)
+	}
+}
+
+func TestRenderConsolidatedScriptOptions(t *testing.T) {
+	rows := testRows(t, 3)
+
+	script, err := renderConsolidatedScript(rows, ScriptOptions{
+		Insert:   InsertOptions{Conflict: ConflictInsert},
+		Comments: true,
+	})
+	if err != nil {
+		t.Fatalf("renderConsolidatedScript falhou: %v", err)
+	}
+
+	if strings.Contains(script, "START TRANSACTION") || strings.Contains(script, "COMMIT") {
+		t.Error("script sem transação não deveria conter START TRANSACTION/COMMIT")
+	}
+	if n := strings.Count(script, "INSERT INTO FarrDarmsPagos"); n != 1 {
+		t.Errorf("batch_size 0 deveria gerar um único INSERT, obtidos %d", n)
+	}
+	if !strings.Contains(script, "-- Lote 1 de 1 (3 registros)") {
+		t.Errorf("script com comentários deveria descrever os lotes:\n%s", script)
+	}
+
+	groups := SplitBatches(testRows(t, 7), 3)
+	if len(groups) != 3 || len(groups[2]) != 1 {
+		t.Errorf("SplitBatches(7, 3) deveria gerar lotes 3/3/1, obtido %d lotes", len(groups))
+	}
+}
+
+func TestGenerateDefaults(t *testing.T) {
+	// Opções zeradas: INSERT simples, sem transação, em um único comando
+	script, err := Generate(testRows(t, 2), ScriptOptions{})
+	if err != nil {
+		t.Fatalf("Generate com opções zeradas falhou: %v", err)
+	}
+	if n := strings.Count(string(script), "INSERT INTO FarrDarmsPagos"); n != 1 {
+		t.Errorf("opções zeradas deveriam gerar um INSERT simples, obtidos %d:\n%s", n, script)
+	}
+
+	if _, err := Generate(nil, ScriptOptions{}); err == nil {
+		t.Error("Generate sem linhas deveria falhar")
+	}
+}
+
+func TestRenderCheckQuery(t *testing.T) {
+	rows := testRows(t, 3)
+	rows[2]["AA_EXERCICIO"] = 2024
+
+	query := RenderCheckQuery(rows, MySQLDialect{})
+	expected := "SELECT NR_GUIA, SQ_DOC, DT_INCL FROM FarrDarmsPagos\n" +
+		"WHERE (AA_EXERCICIO = 2025 AND CD_BANCO = 70 AND NR_BDA = 37 AND NR_COMPLEMENTO = 0 AND NR_LOTE_NSA = 730 AND TP_LOTE_D = 1 AND NR_GUIA IN (1, 2))\n" +
+		"    OR (AA_EXERCICIO = 2024 AND CD_BANCO = 70 AND NR_BDA = 37 AND NR_COMPLEMENTO = 0 AND NR_LOTE_NSA = 730 AND TP_LOTE_D = 1 AND NR_GUIA IN (3))\n" +
+		"ORDER BY NR_GUIA, SQ_DOC;"
+	if !strings.Contains(query, expected) {
+		t.Errorf("consulta inesperada:\n%s", query)
+	}
+}
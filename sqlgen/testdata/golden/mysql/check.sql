@@ -0,0 +1,5 @@
+use silfae;
+
+SELECT NR_GUIA, SQ_DOC, DT_INCL FROM FarrDarmsPagos
+WHERE (AA_EXERCICIO = 2025 AND CD_BANCO = 70 AND NR_BDA = 37 AND NR_COMPLEMENTO = 0 AND NR_LOTE_NSA = 730 AND TP_LOTE_D = 1 AND NR_GUIA IN (1, 2, 3))
+ORDER BY NR_GUIA, SQ_DOC;
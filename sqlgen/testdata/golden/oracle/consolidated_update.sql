@@ -0,0 +1,143 @@
+ALTER SESSION SET CURRENT_SCHEMA = silfae;
+
+-- Guias do lote já existentes antes da inserção
+SELECT COUNT(*) AS total_antes FROM "FarrDarmsPagos"
+WHERE ("AA_EXERCICIO" = 2025 AND "CD_BANCO" = 70 AND "NR_BDA" = 37 AND "NR_COMPLEMENTO" = 0 AND "NR_LOTE_NSA" = 730 AND "TP_LOTE_D" = 1 AND "NR_GUIA" IN (1, 2, 3));
+
+-- Lote 1 de 3 (1 registros)
+MERGE INTO "FarrDarmsPagos" destino
+USING (
+    SELECT
+        2025 AS "AA_EXERCICIO", 70 AS "CD_BANCO", 37 AS "NR_BDA", 0 AS "NR_COMPLEMENTO", 730 AS "NR_LOTE_NSA", 1 AS "TP_LOTE_D",
+        1000 AS "SQ_DOC", 2623 AS "CD_RECEITA", NULL AS "CD_USU_ALT", 'FARR' AS "CD_USU_INCL", NULL AS "DT_ALT",
+        SYSDATE AS "DT_INCL", TO_DATE('2024-12-15 00:00:00', 'YYYY-MM-DD HH24:MI:SS') AS "DT_VENCTO", SYSDATE AS "DT_PAGTO",
+        '123,456' AS "NR_INSCRICAO", 1 AS "NR_GUIA", 2025 AS "NR_COMPETENCIA", NULL AS "NR_CODIGO_BARRAS",
+        NULL AS "NR_LOTE_IPTU", '13' AS "ST_DOC_D", NULL AS "TP_IMPOSTO", 1234.56 AS "VL_PAGO", 1234.56 AS "VL_RECEITA", 1234.56 AS "VL_PRINCIPAL",
+        0.00 AS "VL_MORA", 0.00 AS "VL_MULTA", NULL AS "VL_MULTAF_TCDL", NULL AS "VL_MULTAP_TSD", NULL AS "VL_INSU_TIP", 0.00 AS "VL_JUROS",
+        0 AS "processado", NULL AS "criticaProcessamento" FROM DUAL
+) origem
+ON (destino."AA_EXERCICIO" = origem."AA_EXERCICIO"
+    AND destino."CD_BANCO" = origem."CD_BANCO"
+    AND destino."NR_BDA" = origem."NR_BDA"
+    AND destino."NR_COMPLEMENTO" = origem."NR_COMPLEMENTO"
+    AND destino."NR_LOTE_NSA" = origem."NR_LOTE_NSA"
+    AND destino."TP_LOTE_D" = origem."TP_LOTE_D"
+    AND destino."NR_GUIA" = origem."NR_GUIA")
+WHEN MATCHED THEN UPDATE SET
+    destino."CD_RECEITA" = origem."CD_RECEITA",
+    destino."DT_VENCTO" = origem."DT_VENCTO",
+    destino."NR_INSCRICAO" = origem."NR_INSCRICAO",
+    destino."NR_CODIGO_BARRAS" = origem."NR_CODIGO_BARRAS",
+    destino."VL_PAGO" = origem."VL_PAGO",
+    destino."VL_RECEITA" = origem."VL_RECEITA",
+    destino."VL_PRINCIPAL" = origem."VL_PRINCIPAL"
+WHEN NOT MATCHED THEN INSERT (
+    "AA_EXERCICIO", "CD_BANCO", "NR_BDA", "NR_COMPLEMENTO", "NR_LOTE_NSA", "TP_LOTE_D",
+    "SQ_DOC", "CD_RECEITA", "CD_USU_ALT", "CD_USU_INCL", "DT_ALT", "DT_INCL", "DT_VENCTO",
+    "DT_PAGTO", "NR_INSCRICAO", "NR_GUIA", "NR_COMPETENCIA", "NR_CODIGO_BARRAS",
+    "NR_LOTE_IPTU", "ST_DOC_D", "TP_IMPOSTO", "VL_PAGO", "VL_RECEITA", "VL_PRINCIPAL",
+    "VL_MORA", "VL_MULTA", "VL_MULTAF_TCDL", "VL_MULTAP_TSD", "VL_INSU_TIP", "VL_JUROS",
+    "processado", "criticaProcessamento"
+) VALUES (
+    origem."AA_EXERCICIO", origem."CD_BANCO", origem."NR_BDA", origem."NR_COMPLEMENTO", origem."NR_LOTE_NSA", origem."TP_LOTE_D",
+    origem."SQ_DOC", origem."CD_RECEITA", origem."CD_USU_ALT", origem."CD_USU_INCL", origem."DT_ALT",
+    origem."DT_INCL", origem."DT_VENCTO", origem."DT_PAGTO",
+    origem."NR_INSCRICAO", origem."NR_GUIA", origem."NR_COMPETENCIA", origem."NR_CODIGO_BARRAS",
+    origem."NR_LOTE_IPTU", origem."ST_DOC_D", origem."TP_IMPOSTO", origem."VL_PAGO", origem."VL_RECEITA", origem."VL_PRINCIPAL",
+    origem."VL_MORA", origem."VL_MULTA", origem."VL_MULTAF_TCDL", origem."VL_MULTAP_TSD", origem."VL_INSU_TIP", origem."VL_JUROS",
+    origem."processado", origem."criticaProcessamento"
+);
+
+-- Lote 2 de 3 (1 registros)
+MERGE INTO "FarrDarmsPagos" destino
+USING (
+    SELECT
+        2025 AS "AA_EXERCICIO", 70 AS "CD_BANCO", 37 AS "NR_BDA", 0 AS "NR_COMPLEMENTO", 730 AS "NR_LOTE_NSA", 1 AS "TP_LOTE_D",
+        2000 AS "SQ_DOC", 2623 AS "CD_RECEITA", NULL AS "CD_USU_ALT", 'FARR' AS "CD_USU_INCL", NULL AS "DT_ALT",
+        SYSDATE AS "DT_INCL", TO_DATE('2024-12-15 00:00:00', 'YYYY-MM-DD HH24:MI:SS') AS "DT_VENCTO", SYSDATE AS "DT_PAGTO",
+        '123,456' AS "NR_INSCRICAO", 2 AS "NR_GUIA", 2025 AS "NR_COMPETENCIA", NULL AS "NR_CODIGO_BARRAS",
+        NULL AS "NR_LOTE_IPTU", '13' AS "ST_DOC_D", NULL AS "TP_IMPOSTO", 1234.56 AS "VL_PAGO", 1234.56 AS "VL_RECEITA", 1234.56 AS "VL_PRINCIPAL",
+        0.00 AS "VL_MORA", 0.00 AS "VL_MULTA", NULL AS "VL_MULTAF_TCDL", NULL AS "VL_MULTAP_TSD", NULL AS "VL_INSU_TIP", 0.00 AS "VL_JUROS",
+        0 AS "processado", NULL AS "criticaProcessamento" FROM DUAL
+) origem
+ON (destino."AA_EXERCICIO" = origem."AA_EXERCICIO"
+    AND destino."CD_BANCO" = origem."CD_BANCO"
+    AND destino."NR_BDA" = origem."NR_BDA"
+    AND destino."NR_COMPLEMENTO" = origem."NR_COMPLEMENTO"
+    AND destino."NR_LOTE_NSA" = origem."NR_LOTE_NSA"
+    AND destino."TP_LOTE_D" = origem."TP_LOTE_D"
+    AND destino."NR_GUIA" = origem."NR_GUIA")
+WHEN MATCHED THEN UPDATE SET
+    destino."CD_RECEITA" = origem."CD_RECEITA",
+    destino."DT_VENCTO" = origem."DT_VENCTO",
+    destino."NR_INSCRICAO" = origem."NR_INSCRICAO",
+    destino."NR_CODIGO_BARRAS" = origem."NR_CODIGO_BARRAS",
+    destino."VL_PAGO" = origem."VL_PAGO",
+    destino."VL_RECEITA" = origem."VL_RECEITA",
+    destino."VL_PRINCIPAL" = origem."VL_PRINCIPAL"
+WHEN NOT MATCHED THEN INSERT (
+    "AA_EXERCICIO", "CD_BANCO", "NR_BDA", "NR_COMPLEMENTO", "NR_LOTE_NSA", "TP_LOTE_D",
+    "SQ_DOC", "CD_RECEITA", "CD_USU_ALT", "CD_USU_INCL", "DT_ALT", "DT_INCL", "DT_VENCTO",
+    "DT_PAGTO", "NR_INSCRICAO", "NR_GUIA", "NR_COMPETENCIA", "NR_CODIGO_BARRAS",
+    "NR_LOTE_IPTU", "ST_DOC_D", "TP_IMPOSTO", "VL_PAGO", "VL_RECEITA", "VL_PRINCIPAL",
+    "VL_MORA", "VL_MULTA", "VL_MULTAF_TCDL", "VL_MULTAP_TSD", "VL_INSU_TIP", "VL_JUROS",
+    "processado", "criticaProcessamento"
+) VALUES (
+    origem."AA_EXERCICIO", origem."CD_BANCO", origem."NR_BDA", origem."NR_COMPLEMENTO", origem."NR_LOTE_NSA", origem."TP_LOTE_D",
+    origem."SQ_DOC", origem."CD_RECEITA", origem."CD_USU_ALT", origem."CD_USU_INCL", origem."DT_ALT",
+    origem."DT_INCL", origem."DT_VENCTO", origem."DT_PAGTO",
+    origem."NR_INSCRICAO", origem."NR_GUIA", origem."NR_COMPETENCIA", origem."NR_CODIGO_BARRAS",
+    origem."NR_LOTE_IPTU", origem."ST_DOC_D", origem."TP_IMPOSTO", origem."VL_PAGO", origem."VL_RECEITA", origem."VL_PRINCIPAL",
+    origem."VL_MORA", origem."VL_MULTA", origem."VL_MULTAF_TCDL", origem."VL_MULTAP_TSD", origem."VL_INSU_TIP", origem."VL_JUROS",
+    origem."processado", origem."criticaProcessamento"
+);
+
+-- Lote 3 de 3 (1 registros)
+MERGE INTO "FarrDarmsPagos" destino
+USING (
+    SELECT
+        2025 AS "AA_EXERCICIO", 70 AS "CD_BANCO", 37 AS "NR_BDA", 0 AS "NR_COMPLEMENTO", 730 AS "NR_LOTE_NSA", 1 AS "TP_LOTE_D",
+        3000 AS "SQ_DOC", 2623 AS "CD_RECEITA", NULL AS "CD_USU_ALT", 'FARR' AS "CD_USU_INCL", NULL AS "DT_ALT",
+        SYSDATE AS "DT_INCL", TO_DATE('2024-12-15 00:00:00', 'YYYY-MM-DD HH24:MI:SS') AS "DT_VENCTO", SYSDATE AS "DT_PAGTO",
+        '123,456' AS "NR_INSCRICAO", 3 AS "NR_GUIA", 2025 AS "NR_COMPETENCIA", NULL AS "NR_CODIGO_BARRAS",
+        NULL AS "NR_LOTE_IPTU", '13' AS "ST_DOC_D", NULL AS "TP_IMPOSTO", 1234.56 AS "VL_PAGO", 1234.56 AS "VL_RECEITA", 1234.56 AS "VL_PRINCIPAL",
+        0.00 AS "VL_MORA", 0.00 AS "VL_MULTA", NULL AS "VL_MULTAF_TCDL", NULL AS "VL_MULTAP_TSD", NULL AS "VL_INSU_TIP", 0.00 AS "VL_JUROS",
+        0 AS "processado", NULL AS "criticaProcessamento" FROM DUAL
+) origem
+ON (destino."AA_EXERCICIO" = origem."AA_EXERCICIO"
+    AND destino."CD_BANCO" = origem."CD_BANCO"
+    AND destino."NR_BDA" = origem."NR_BDA"
+    AND destino."NR_COMPLEMENTO" = origem."NR_COMPLEMENTO"
+    AND destino."NR_LOTE_NSA" = origem."NR_LOTE_NSA"
+    AND destino."TP_LOTE_D" = origem."TP_LOTE_D"
+    AND destino."NR_GUIA" = origem."NR_GUIA")
+WHEN MATCHED THEN UPDATE SET
+    destino."CD_RECEITA" = origem."CD_RECEITA",
+    destino."DT_VENCTO" = origem."DT_VENCTO",
+    destino."NR_INSCRICAO" = origem."NR_INSCRICAO",
+    destino."NR_CODIGO_BARRAS" = origem."NR_CODIGO_BARRAS",
+    destino."VL_PAGO" = origem."VL_PAGO",
+    destino."VL_RECEITA" = origem."VL_RECEITA",
+    destino."VL_PRINCIPAL" = origem."VL_PRINCIPAL"
+WHEN NOT MATCHED THEN INSERT (
+    "AA_EXERCICIO", "CD_BANCO", "NR_BDA", "NR_COMPLEMENTO", "NR_LOTE_NSA", "TP_LOTE_D",
+    "SQ_DOC", "CD_RECEITA", "CD_USU_ALT", "CD_USU_INCL", "DT_ALT", "DT_INCL", "DT_VENCTO",
+    "DT_PAGTO", "NR_INSCRICAO", "NR_GUIA", "NR_COMPETENCIA", "NR_CODIGO_BARRAS",
+    "NR_LOTE_IPTU", "ST_DOC_D", "TP_IMPOSTO", "VL_PAGO", "VL_RECEITA", "VL_PRINCIPAL",
+    "VL_MORA", "VL_MULTA", "VL_MULTAF_TCDL", "VL_MULTAP_TSD", "VL_INSU_TIP", "VL_JUROS",
+    "processado", "criticaProcessamento"
+) VALUES (
+    origem."AA_EXERCICIO", origem."CD_BANCO", origem."NR_BDA", origem."NR_COMPLEMENTO", origem."NR_LOTE_NSA", origem."TP_LOTE_D",
+    origem."SQ_DOC", origem."CD_RECEITA", origem."CD_USU_ALT", origem."CD_USU_INCL", origem."DT_ALT",
+    origem."DT_INCL", origem."DT_VENCTO", origem."DT_PAGTO",
+    origem."NR_INSCRICAO", origem."NR_GUIA", origem."NR_COMPETENCIA", origem."NR_CODIGO_BARRAS",
+    origem."NR_LOTE_IPTU", origem."ST_DOC_D", origem."TP_IMPOSTO", origem."VL_PAGO", origem."VL_RECEITA", origem."VL_PRINCIPAL",
+    origem."VL_MORA", origem."VL_MULTA", origem."VL_MULTAF_TCDL", origem."VL_MULTAP_TSD", origem."VL_INSU_TIP", origem."VL_JUROS",
+    origem."processado", origem."criticaProcessamento"
+);
+
+COMMIT;
+
+-- Guias do lote existentes após a inserção (esperado: 3)
+SELECT COUNT(*) AS total_depois FROM "FarrDarmsPagos"
+WHERE ("AA_EXERCICIO" = 2025 AND "CD_BANCO" = 70 AND "NR_BDA" = 37 AND "NR_COMPLEMENTO" = 0 AND "NR_LOTE_NSA" = 730 AND "TP_LOTE_D" = 1 AND "NR_GUIA" IN (1, 2, 3));
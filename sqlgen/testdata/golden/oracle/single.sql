@@ -0,0 +1,29 @@
+ALTER SESSION SET CURRENT_SCHEMA = silfae;
+
+INSERT INTO "FarrDarmsPagos" (
+    "AA_EXERCICIO", "CD_BANCO", "NR_BDA", "NR_COMPLEMENTO", "NR_LOTE_NSA", "TP_LOTE_D",
+    "SQ_DOC", "CD_RECEITA", "CD_USU_ALT", "CD_USU_INCL", "DT_ALT", "DT_INCL", "DT_VENCTO",
+    "DT_PAGTO", "NR_INSCRICAO", "NR_GUIA", "NR_COMPETENCIA", "NR_CODIGO_BARRAS",
+    "NR_LOTE_IPTU", "ST_DOC_D", "TP_IMPOSTO", "VL_PAGO", "VL_RECEITA", "VL_PRINCIPAL",
+    "VL_MORA", "VL_MULTA", "VL_MULTAF_TCDL", "VL_MULTAP_TSD", "VL_INSU_TIP", "VL_JUROS",
+    "processado", "criticaProcessamento"
+)
+SELECT
+    2025, 70, 37, 0, 730, 1,
+    MOD((MOD(1, 1000) * 1000) + MOD(TRUNC((CAST(SYS_EXTRACT_UTC(SYSTIMESTAMP) AS DATE) - DATE '1970-01-01') * 86400), 1000), 1000000), 2623, NULL, 'FARR', NULL,
+    SYSDATE, TO_DATE('2024-12-15 00:00:00', 'YYYY-MM-DD HH24:MI:SS'), SYSDATE,
+    '123,456', 1, 2025, NULL,
+    NULL, '13', NULL, 1234.56, 1234.56, 1234.56,
+    0.00, 0.00, NULL, NULL, NULL, 0.00,
+    0, NULL
+FROM DUAL
+WHERE NOT EXISTS (
+    SELECT 1 FROM "FarrDarmsPagos"
+    WHERE "AA_EXERCICIO" = 2025
+    AND "CD_BANCO" = 70
+    AND "NR_BDA" = 37
+    AND "NR_COMPLEMENTO" = 0
+    AND "NR_LOTE_NSA" = 730
+    AND "TP_LOTE_D" = 1
+    AND "NR_GUIA" = 1
+);
\ No newline at end of file
@@ -0,0 +1,12 @@
+USE silfae;
+
+BEGIN TRANSACTION;
+
+DELETE FROM [FarrDarmsPagos]
+WHERE [AA_EXERCICIO] = 2025 AND [CD_BANCO] = 70 AND [NR_BDA] = 37 AND [NR_COMPLEMENTO] = 0 AND [NR_LOTE_NSA] = 730 AND [TP_LOTE_D] = 1 AND [NR_GUIA] = 1 AND [SQ_DOC] = 1000;
+DELETE FROM [FarrDarmsPagos]
+WHERE [AA_EXERCICIO] = 2025 AND [CD_BANCO] = 70 AND [NR_BDA] = 37 AND [NR_COMPLEMENTO] = 0 AND [NR_LOTE_NSA] = 730 AND [TP_LOTE_D] = 1 AND [NR_GUIA] = 2 AND [SQ_DOC] = 2000;
+DELETE FROM [FarrDarmsPagos]
+WHERE [AA_EXERCICIO] = 2025 AND [CD_BANCO] = 70 AND [NR_BDA] = 37 AND [NR_COMPLEMENTO] = 0 AND [NR_LOTE_NSA] = 730 AND [TP_LOTE_D] = 1 AND [NR_GUIA] = 3 AND [SQ_DOC] = 3000;
+
+COMMIT;
@@ -0,0 +1,84 @@
+package sqlgen
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gerador-query-darm-go/validation"
+)
+
+// SQLRaw é uma expressão SQL inserida sem aspas (ex.: NOW())
+type SQLRaw string
+
+// SQLNow é a data/hora atual do banco (NOW() no MySQL)
+type SQLNow struct{}
+
+// SQLDocSequence é o SQ_DOC calculado no banco a partir da guia e do relógio
+type SQLDocSequence struct {
+	Guia int
+}
+
+// SQLUtils contém utilitários para SQL
+type SQLUtils struct{}
+
+// NewSQLUtils cria nova instância de SQLUtils
+func NewSQLUtils() *SQLUtils {
+	return &SQLUtils{}
+}
+
+// EscapeString escapa string para SQL
+func (su *SQLUtils) EscapeString(s string) string {
+	// Substituir aspas simples por duas aspas simples
+	return strings.ReplaceAll(s, "'", "''")
+}
+
+// QuoteString coloca string entre aspas simples
+func (su *SQLUtils) QuoteString(s string) string {
+	return "'" + su.EscapeString(s) + "'"
+}
+
+// FormatSQLValue formata valor para SQL
+func (su *SQLUtils) FormatSQLValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return "NULL"
+		}
+		return su.QuoteString(v)
+	case int, int32, int64:
+		return fmt.Sprintf("%v", v)
+	case float32:
+		return validation.MoneyFromFloat(float64(v)).Decimal()
+	case float64:
+		return validation.MoneyFromFloat(v).Decimal()
+	case validation.Money:
+		return v.Decimal()
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case SQLRaw:
+		return string(v)
+	case SQLNow:
+		return "NOW()"
+	case SQLDocSequence:
+		return fmt.Sprintf("(((%d %% 1000) * 1000) + (UNIX_TIMESTAMP() %% 1000)) %% 1000000", v.Guia)
+	case time.Time:
+		return su.QuoteString(v.Format("2006-01-02 15:04:05"))
+	case nil:
+		return "NULL"
+	default:
+		return su.QuoteString(fmt.Sprintf("%v", v))
+	}
+}
+
+// GeneratePlaceholders gera placeholders para SQL
+func (su *SQLUtils) GeneratePlaceholders(count int) string {
+	placeholders := make([]string, count)
+	for i := 0; i < count; i++ {
+		placeholders[i] = "?"
+	}
+	return strings.Join(placeholders, ", ")
+}
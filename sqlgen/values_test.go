@@ -0,0 +1,53 @@
+package sqlgen
+
+import (
+	"testing"
+
+	"gerador-query-darm-go/validation"
+)
+
+// TestSQLUtils testa utilitários SQL
+func TestSQLUtils(t *testing.T) {
+	sqlUtils := NewSQLUtils()
+
+	// Test EscapeString
+	if sqlUtils.EscapeString("O'Connor") != "O''Connor" {
+		t.Error("EscapeString falhou")
+	}
+
+	// Test QuoteString
+	if sqlUtils.QuoteString("test") != "'test'" {
+		t.Error("QuoteString falhou")
+	}
+
+	// Test FormatSQLValue
+	if sqlUtils.FormatSQLValue("test") != "'test'" {
+		t.Error("FormatSQLValue falhou para string")
+	}
+
+	if sqlUtils.FormatSQLValue(123) != "123" {
+		t.Error("FormatSQLValue falhou para int")
+	}
+
+	if sqlUtils.FormatSQLValue(123.45) != "123.45" {
+		t.Error("FormatSQLValue falhou para float")
+	}
+
+	if sqlUtils.FormatSQLValue(true) != "1" {
+		t.Error("FormatSQLValue falhou para bool")
+	}
+
+	if sqlUtils.FormatSQLValue(nil) != "NULL" {
+		t.Error("FormatSQLValue falhou para nil")
+	}
+
+	if sqlUtils.FormatSQLValue(validation.Money(901406)) != "9014.06" {
+		t.Error("FormatSQLValue falhou para Money")
+	}
+
+	// Test GeneratePlaceholders
+	placeholders := sqlUtils.GeneratePlaceholders(3)
+	if placeholders != "?, ?, ?" {
+		t.Errorf("GeneratePlaceholders retornou %s, esperado '?, ?, ?'", placeholders)
+	}
+}
@@ -0,0 +1,274 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/pix"
+)
+
+// Valores assumidos quando o campo não é encontrado no PDF
+const (
+	defaultCodigoReceita = "2585"
+	defaultExercicio     = 2025
+)
+
+var (
+	// receitaRegex aceita o código com DV separado por hífen (262-3) ou concatenado (2623)
+	receitaRegex = regexp.MustCompile(`^(\d{1,4})-?(\d)$`)
+	// competenciaRegex aceita o período no formato MM/YYYY
+	competenciaRegex = regexp.MustCompile(`^(\d{2})/(\d{4})$`)
+	// cleanDigitsRegex remove os caracteres não numéricos
+	cleanDigitsRegex = regexp.MustCompile(`\D`)
+)
+
+// CodigoReceita é o código de receita do DARM com o dígito verificador
+type CodigoReceita struct {
+	Codigo string `json:"codigo"`
+	DV     string `json:"dv"`
+}
+
+// ParseCodigoReceita converte "262-3" ou "2623" (último dígito = DV)
+func ParseCodigoReceita(value string) (CodigoReceita, error) {
+	matches := receitaRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return CodigoReceita{}, fmt.Errorf("código de receita inválido: %q", value)
+	}
+	return CodigoReceita{Codigo: matches[1], DV: matches[2]}, nil
+}
+
+// Numero retorna o código com DV como gravado em CD_RECEITA (ex.: 2623)
+func (r CodigoReceita) Numero() int {
+	numero, _ := strconv.Atoi(r.Codigo + r.DV)
+	return numero
+}
+
+// String formata o código como no DARM (ex.: 262-3)
+func (r CodigoReceita) String() string {
+	return r.Codigo + "-" + r.DV
+}
+
+// Competencia é o período de referência (mês/ano) do DARM
+type Competencia struct {
+	Mes int
+	Ano int
+}
+
+// ParseCompetencia converte o período no formato MM/YYYY
+func ParseCompetencia(value string) (Competencia, error) {
+	matches := competenciaRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return Competencia{}, fmt.Errorf("competência inválida: %q", value)
+	}
+	mes, _ := strconv.Atoi(matches[1])
+	ano, _ := strconv.Atoi(matches[2])
+	if mes < 1 || mes > 12 {
+		return Competencia{}, fmt.Errorf("competência inválida: %q", value)
+	}
+	return Competencia{Mes: mes, Ano: ano}, nil
+}
+
+// String formata o período como MM/YYYY
+func (c Competencia) String() string {
+	return fmt.Sprintf("%02d/%04d", c.Mes, c.Ano)
+}
+
+// MarshalJSON grava o período como "MM/YYYY"
+func (c Competencia) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+// UnmarshalJSON lê o período no formato "MM/YYYY"
+func (c *Competencia) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	parsed, err := ParseCompetencia(text)
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// DarmRecord é o DARM convertido e validado: os geradores de SQL, relatório e
+// exportações usam apenas estes campos tipados. Os textos extraídos do PDF
+// ficam em Raw para auditoria.
+type DarmRecord struct {
+	Raw            *extraction.DarmData `json:"-"`
+	Inscricao      string               `json:"inscricao"`
+	CodigoBarras   string               `json:"codigoBarras"`
+	Receita        CodigoReceita        `json:"receita"`
+	ValorPrincipal Money                `json:"valorPrincipal"`
+	ValorTotal     Money                `json:"valorTotal"`
+	Vencimento     *time.Time           `json:"vencimento,omitempty"`
+	Exercicio      int                  `json:"exercicio"`
+	Guia           int                  `json:"guia"`
+	Competencia    *Competencia         `json:"competencia,omitempty"`
+	// Pix é o BR Code da guia, conferido com VALOR TOTAL e o número da guia
+	Pix           *pix.BRCode    `json:"pix,omitempty"`
+	Substituicoes []Substituicao `json:"-"`
+}
+
+// Options controla a conversão dos dados extraídos
+type Options struct {
+	// Strict rejeita o DARM quando algum valor padrão seria aplicado
+	Strict bool
+}
+
+// Parse converte e valida os dados extraídos do PDF. Os valores padrão
+// aplicados por falta de dado válido são registrados como substituições;
+// no modo strict qualquer substituição rejeita o DARM.
+func Parse(darmData *extraction.DarmData, opts Options) (*DarmRecord, error) {
+	record := &DarmRecord{Raw: darmData, Inscricao: darmData.Inscricao, Substituicoes: []Substituicao{}}
+	substituir := func(campo, valor, motivo string) {
+		record.Substituicoes = append(record.Substituicoes, Substituicao{Campo: campo, Valor: valor, Motivo: motivo})
+	}
+
+	// Converter data de vencimento do formato DD/MM/YYYY
+	if darmData.DataVencimento == "" {
+		substituir("DT_VENCTO", "NULL", "data de vencimento não encontrada no PDF")
+	} else if date, err := NewDateUtils().ParseDateBR(darmData.DataVencimento); err == nil {
+		record.Vencimento = &date
+	} else {
+		substituir("DT_VENCTO", "NULL", fmt.Sprintf("data de vencimento inválida: %q", darmData.DataVencimento))
+	}
+
+	// Competência MM/YYYY (ausente ou inválida, NR_COMPETENCIA usa o ano corrente)
+	if darmData.Competencia == "" {
+		substituir("NR_COMPETENCIA", strconv.Itoa(time.Now().Year()), "competência não encontrada no PDF")
+	} else if competencia, err := ParseCompetencia(darmData.Competencia); err == nil {
+		record.Competencia = &competencia
+	} else {
+		substituir("NR_COMPETENCIA", strconv.Itoa(time.Now().Year()), err.Error())
+	}
+
+	// Processar valores monetários
+	valorPrincipal, err := parseMonetaryValueStrict(darmData.ValorPrincipal)
+	if err != nil {
+		valorPrincipal = 0
+		substituir("VL_PRINCIPAL", valorPrincipal.Decimal(), "valor principal: "+err.Error())
+	}
+	valorTotal, err := parseMonetaryValueStrict(darmData.ValorTotal)
+	if err != nil {
+		valorTotal = valorPrincipal
+		substituir("VL_PAGO", valorPrincipal.Decimal(), "valor total: "+err.Error()+"; usado VL_PRINCIPAL")
+	} else if valorTotal == 0 {
+		valorTotal = valorPrincipal
+		substituir("VL_PAGO", valorPrincipal.Decimal(), "valor total zerado; usado VL_PRINCIPAL")
+	}
+	record.ValorPrincipal, record.ValorTotal = valorPrincipal, valorTotal
+
+	// Limitar código de barras a 48 dígitos e remover caracteres não numéricos
+	record.CodigoBarras = cleanDigitsRegex.ReplaceAllString(darmData.CodigoBarras, "")
+	if len(record.CodigoBarras) > 48 {
+		record.CodigoBarras = record.CodigoBarras[:48]
+	}
+
+	// Usar código de receita do PDF ou valor padrão
+	if darmData.CodigoReceita == "" {
+		substituir("CD_RECEITA", defaultCodigoReceita, "código de receita não encontrado no PDF")
+	}
+	if record.Receita, err = ParseCodigoReceita(getDefaultValue(darmData.CodigoReceita, defaultCodigoReceita)); err != nil {
+		return nil, err
+	}
+
+	if darmData.Exercicio == "" {
+		substituir("AA_EXERCICIO", strconv.Itoa(defaultExercicio), "exercício não encontrado no PDF")
+		record.Exercicio = defaultExercicio
+	} else if record.Exercicio, err = strconv.Atoi(darmData.Exercicio); err != nil {
+		return nil, fmt.Errorf("exercício inválido: %q", darmData.Exercicio)
+	}
+
+	if removeLeadingZeros(darmData.NumeroGuia) == "" {
+		substituir("NR_GUIA", "0", "número da guia não encontrado no PDF")
+	}
+	if record.Guia, err = strconv.Atoi(getDefaultValue(removeLeadingZeros(darmData.NumeroGuia), "0")); err != nil {
+		return nil, fmt.Errorf("número da guia inválido: %q", darmData.NumeroGuia)
+	}
+
+	if darmData.PixCopiaECola != "" {
+		if code, motivo := checkPix(darmData.PixCopiaECola, record); motivo != "" {
+			substituir("PIX", "NULL", motivo)
+		} else {
+			record.Pix = code
+		}
+	}
+
+	if opts.Strict {
+		if err := strictError(record.Substituicoes); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+// checkPix interpreta o BR Code e o confere com o VALOR TOTAL e o número da
+// guia (o txid dos DARMs termina com o número da guia, completado com zeros
+// à esquerda). Retorna o motivo da recusa quando o Pix não corresponde à guia.
+func checkPix(payload string, record *DarmRecord) (*pix.BRCode, string) {
+	code, err := pix.Parse(payload)
+	if err != nil {
+		return nil, err.Error()
+	}
+	if centavos, ok := code.Centavos(); ok && Money(centavos) != record.ValorTotal {
+		return nil, fmt.Sprintf("valor do Pix R$ %s difere do VALOR TOTAL R$ %s", Money(centavos), record.ValorTotal)
+	}
+	// "***" é o txid de QR Codes estáticos sem identificador
+	if guia := guiaCompleta(record); record.Guia > 0 && code.TxID != "" && code.TxID != "***" && txidGuia(code.TxID) != guia {
+		return nil, fmt.Sprintf("txid do Pix %q não termina com o número da guia %s", code.TxID, guia)
+	}
+	return code, ""
+}
+
+// guiaCompleta retorna o número completo da guia impresso no PDF. NR_GUIA
+// guarda só os 3 primeiros dígitos; o número completo vale enquanto for
+// coerente com eles (uma correção manual de numeroGuia o invalida).
+func guiaCompleta(record *DarmRecord) string {
+	guia := strconv.Itoa(record.Guia)
+	if record.Raw == nil {
+		return guia
+	}
+	if completo := removeLeadingZeros(record.Raw.NumeroGuiaCompleto); strings.HasPrefix(completo, guia) {
+		return completo
+	}
+	return guia
+}
+
+// txidGuia retorna os dígitos finais do txid sem os zeros à esquerda, ex.:
+// "DARM0000001" → "1"; dígitos da guia em outra posição não contam
+func txidGuia(txid string) string {
+	inicio := len(txid)
+	for inicio > 0 && txid[inicio-1] >= '0' && txid[inicio-1] <= '9' {
+		inicio--
+	}
+	return removeLeadingZeros(txid[inicio:])
+}
+
+// removeLeadingZeros remove zeros à esquerda apenas se houver zeros
+func removeLeadingZeros(value string) string {
+	return strings.TrimLeft(value, "0")
+}
+
+// parseMonetaryValueStrict converte valor monetário para centavos, retornando
+// erro quando o valor está ausente, inválido ou ambíguo
+func parseMonetaryValueStrict(value string) (Money, error) {
+	if value == "" {
+		return 0, fmt.Errorf("valor não encontrado no PDF")
+	}
+	return ParseMoneyBR(value)
+}
+
+// getDefaultValue retorna valor padrão se o valor estiver vazio
+func getDefaultValue(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
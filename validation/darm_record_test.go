@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gerador-query-darm-go/internal/darmtest"
+)
+
+func TestParseCodigoReceita(t *testing.T) {
+	for input, expected := range map[string]string{"262-3": "262-3", "2623": "262-3", "2585": "258-5", "11": "1-1"} {
+		receita, err := ParseCodigoReceita(input)
+		if err != nil {
+			t.Errorf("ParseCodigoReceita(%q) falhou: %v", input, err)
+			continue
+		}
+		if receita.String() != expected {
+			t.Errorf("ParseCodigoReceita(%q) = %s, esperado %s", input, receita, expected)
+		}
+	}
+	if receita, _ := ParseCodigoReceita("262-3"); receita.Numero() != 2623 {
+		t.Errorf("CD_RECEITA de 262-3 deveria ser 2623, obtido %d", receita.Numero())
+	}
+	for _, input := range []string{"", "abc", "1", "123456", "262-34"} {
+		if _, err := ParseCodigoReceita(input); err == nil {
+			t.Errorf("ParseCodigoReceita(%q) deveria falhar", input)
+		}
+	}
+}
+
+func TestParseDarmRecord(t *testing.T) {
+	data := darmtest.DarmData("0042.pdf")
+	data.Competencia = "11/2024"
+	data.CodigoBarras = "8160.0000 0123-4"
+
+	record, err := Parse(data, Options{})
+	if err != nil {
+		t.Fatalf("Parse falhou: %v", err)
+	}
+	if record.Raw != data || record.Guia != 42 || record.Exercicio != 2025 || record.ValorTotal != 123456 {
+		t.Errorf("registro inesperado: %+v", record)
+	}
+	if record.Vencimento == nil || !record.Vencimento.Equal(time.Date(2024, 12, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("vencimento inesperado: %v", record.Vencimento)
+	}
+	if record.Competencia == nil || *record.Competencia != (Competencia{Mes: 11, Ano: 2024}) {
+		t.Errorf("competência inesperada: %v", record.Competencia)
+	}
+	if record.CodigoBarras != "8160000001234" {
+		t.Errorf("código de barras deveria conter apenas dígitos: %q", record.CodigoBarras)
+	}
+
+	content, err := json.Marshal(record)
+	if err != nil {
+		t.Fatalf("json.Marshal falhou: %v", err)
+	}
+	var decoded DarmRecord
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal falhou: %v (%s)", err, content)
+	}
+	if decoded.ValorPrincipal != record.ValorPrincipal || *decoded.Competencia != *record.Competencia || decoded.Receita != record.Receita {
+		t.Errorf("registro JSON não preserva os campos: %s", content)
+	}
+
+	data.Competencia = "13/2024"
+	if record, _ := Parse(data, Options{}); len(record.Substituicoes) != 1 || record.Substituicoes[0].Campo != "NR_COMPETENCIA" {
+		t.Errorf("competência inválida deveria gerar aviso: %v", record.Substituicoes)
+	}
+
+	data.Exercicio = "20x5"
+	if _, err := Parse(data, Options{}); err == nil {
+		t.Error("exercício não numérico deveria ser rejeitado")
+	}
+}
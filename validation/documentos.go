@@ -0,0 +1,198 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gerador-query-darm-go/extraction"
+)
+
+var (
+	// darfReceitaRegex aceita o código de receita federal de 4 dígitos
+	darfReceitaRegex = regexp.MustCompile(`^\d{4}$`)
+	// gnreReceitaRegex aceita o código de receita da GNRE de 6 dígitos
+	gnreReceitaRegex = regexp.MustCompile(`^\d{6}$`)
+)
+
+// ufs são as unidades federativas aceitas como UF favorecida da GNRE
+var ufs = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true, "ES": true, "GO": true,
+	"MA": true, "MT": true, "MS": true, "MG": true, "PA": true, "PB": true, "PR": true, "PE": true, "PI": true,
+	"RJ": true, "RN": true, "RS": true, "RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+// ParseCNPJCPF remove a formatação do CNPJ (14 dígitos) ou CPF (11 dígitos) e
+// confere os dígitos verificadores
+func ParseCNPJCPF(value string) (string, error) {
+	digits := cleanDigitsRegex.ReplaceAllString(value, "")
+	var pesos []int
+	switch len(digits) {
+	case 11:
+		pesos = []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}
+	case 14:
+		pesos = []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	default:
+		return "", fmt.Errorf("CNPJ/CPF inválido: %q", value)
+	}
+	if strings.Count(digits, digits[:1]) == len(digits) {
+		return "", fmt.Errorf("CNPJ/CPF inválido: %q", value)
+	}
+
+	// O primeiro DV usa os pesos sem o primeiro termo; o segundo, todos. O
+	// módulo 11 é o mesmo para CPF e CNPJ (restos 0 e 1 dão DV zero).
+	base := len(digits) - 2
+	for dv := 0; dv < 2; dv++ {
+		soma := 0
+		for i, peso := range pesos[1-dv:] {
+			soma += int(digits[i]-'0') * peso
+		}
+		if int(digits[base+dv]-'0') != soma*10%11%10 {
+			return "", fmt.Errorf("CNPJ/CPF com dígito verificador inválido: %q", value)
+		}
+	}
+	return digits, nil
+}
+
+// parseValorOpcional converte um valor monetário que pode estar ausente (zero)
+func parseValorOpcional(campo, value string) (Money, error) {
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := ParseMoneyBR(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", campo, err)
+	}
+	return parsed, nil
+}
+
+// parseVencimentoOpcional converte a data de vencimento DD/MM/YYYY, se houver
+func parseVencimentoOpcional(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := NewDateUtils().ParseDateBR(value)
+	if err != nil {
+		return nil, fmt.Errorf("data de vencimento inválida: %q", value)
+	}
+	return &date, nil
+}
+
+// DarfRecord é o DARF convertido e validado, exportado em DARFs.csv
+type DarfRecord struct {
+	Raw              *extraction.DarfData `json:"-"`
+	PeriodoApuracao  time.Time            `json:"periodoApuracao"`
+	CNPJ             string               `json:"cnpj"`
+	CodigoReceita    string               `json:"codigoReceita"`
+	NumeroReferencia string               `json:"numeroReferencia,omitempty"`
+	Vencimento       *time.Time           `json:"vencimento,omitempty"`
+	ValorPrincipal   Money                `json:"valorPrincipal"`
+	ValorMulta       Money                `json:"valorMulta"`
+	ValorJuros       Money                `json:"valorJuros"`
+	ValorTotal       Money                `json:"valorTotal"`
+}
+
+// ParseDarf converte e valida os dados extraídos de um DARF. Diferente do
+// DARM, não há valores padrão: qualquer campo obrigatório inválido rejeita o
+// documento, e o valor total deve ser a soma de principal, multa e juros.
+func ParseDarf(data *extraction.DarfData) (*DarfRecord, error) {
+	record := &DarfRecord{Raw: data, NumeroReferencia: data.NumeroReferencia}
+
+	periodo, err := NewDateUtils().ParseDateBR(data.PeriodoApuracao)
+	if err != nil {
+		return nil, fmt.Errorf("período de apuração inválido: %q", data.PeriodoApuracao)
+	}
+	record.PeriodoApuracao = periodo
+
+	if record.CNPJ, err = ParseCNPJCPF(data.CNPJ); err != nil {
+		return nil, err
+	}
+	if !darfReceitaRegex.MatchString(data.CodigoReceita) {
+		return nil, fmt.Errorf("código da receita do DARF inválido: %q", data.CodigoReceita)
+	}
+	record.CodigoReceita = data.CodigoReceita
+
+	if record.Vencimento, err = parseVencimentoOpcional(data.DataVencimento); err != nil {
+		return nil, err
+	}
+
+	if record.ValorPrincipal, err = parseMonetaryValueStrict(data.ValorPrincipal); err != nil {
+		return nil, fmt.Errorf("valor do principal: %v", err)
+	}
+	if record.ValorMulta, err = parseValorOpcional("valor da multa", data.ValorMulta); err != nil {
+		return nil, err
+	}
+	if record.ValorJuros, err = parseValorOpcional("valor dos juros", data.ValorJuros); err != nil {
+		return nil, err
+	}
+	if record.ValorTotal, err = parseMonetaryValueStrict(data.ValorTotal); err != nil {
+		return nil, fmt.Errorf("valor total: %v", err)
+	}
+	if soma := record.ValorPrincipal.Add(record.ValorMulta).Add(record.ValorJuros); soma != record.ValorTotal {
+		return nil, fmt.Errorf("valor total R$ %s difere de principal + multa + juros (R$ %s)", record.ValorTotal, soma)
+	}
+	return record, nil
+}
+
+// GnreRecord é a GNRE convertida e validada, exportada em GNREs.csv
+type GnreRecord struct {
+	Raw             *extraction.GnreData `json:"-"`
+	UFFavorecida    string               `json:"ufFavorecida"`
+	CodigoReceita   string               `json:"codigoReceita"`
+	CNPJ            string               `json:"cnpj,omitempty"`
+	DocumentoOrigem string               `json:"documentoOrigem,omitempty"`
+	Referencia      *Competencia         `json:"referencia,omitempty"`
+	Vencimento      *time.Time           `json:"vencimento,omitempty"`
+	ValorPrincipal  Money                `json:"valorPrincipal"`
+	ValorTotal      Money                `json:"valorTotal"`
+	NumeroControle  string               `json:"numeroControle,omitempty"`
+}
+
+// ParseGnre converte e valida os dados extraídos de uma GNRE. O CNPJ/CPF do
+// emitente é opcional (o contribuinte pode constar só pela inscrição
+// estadual), mas é conferido quando presente.
+func ParseGnre(data *extraction.GnreData) (*GnreRecord, error) {
+	record := &GnreRecord{
+		Raw:             data,
+		DocumentoOrigem: data.DocumentoOrigem,
+		NumeroControle:  data.NumeroControle,
+	}
+
+	if !ufs[data.UFFavorecida] {
+		return nil, fmt.Errorf("UF favorecida inválida: %q", data.UFFavorecida)
+	}
+	record.UFFavorecida = data.UFFavorecida
+	if !gnreReceitaRegex.MatchString(data.CodigoReceita) {
+		return nil, fmt.Errorf("código da receita da GNRE inválido: %q", data.CodigoReceita)
+	}
+	record.CodigoReceita = data.CodigoReceita
+
+	var err error
+	if data.CNPJ != "" {
+		if record.CNPJ, err = ParseCNPJCPF(data.CNPJ); err != nil {
+			return nil, err
+		}
+	}
+	if data.PeriodoReferencia != "" {
+		referencia, err := ParseCompetencia(data.PeriodoReferencia)
+		if err != nil {
+			return nil, fmt.Errorf("período de referência: %v", err)
+		}
+		record.Referencia = &referencia
+	}
+	if record.Vencimento, err = parseVencimentoOpcional(data.DataVencimento); err != nil {
+		return nil, err
+	}
+
+	if record.ValorPrincipal, err = parseMonetaryValueStrict(data.ValorPrincipal); err != nil {
+		return nil, fmt.Errorf("valor principal: %v", err)
+	}
+	if record.ValorTotal, err = parseMonetaryValueStrict(data.ValorTotal); err != nil {
+		return nil, fmt.Errorf("total a recolher: %v", err)
+	}
+	if record.ValorTotal < record.ValorPrincipal {
+		return nil, fmt.Errorf("total a recolher R$ %s menor que o valor principal R$ %s", record.ValorTotal, record.ValorPrincipal)
+	}
+	return record, nil
+}
@@ -0,0 +1,103 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/extraction"
+)
+
+func TestParseCNPJCPF(t *testing.T) {
+	valid := map[string]string{
+		"11.222.333/0001-81": "11222333000181",
+		"11222333000181":     "11222333000181",
+		"529.982.247-25":     "52998224725",
+	}
+	for value, expected := range valid {
+		if digits, err := ParseCNPJCPF(value); err != nil || digits != expected {
+			t.Errorf("ParseCNPJCPF(%q) = %q, %v; esperado %q", value, digits, err, expected)
+		}
+	}
+
+	for _, value := range []string{"", "11.222.333/0001-82", "529.982.247-26", "111.111.111-11", "1234567890"} {
+		if _, err := ParseCNPJCPF(value); err == nil {
+			t.Errorf("ParseCNPJCPF(%q) deveria falhar", value)
+		}
+	}
+}
+
+// darfData retorna um DARF extraído válido
+func darfData() *extraction.DarfData {
+	return &extraction.DarfData{
+		PeriodoApuracao: "31/12/2024",
+		CNPJ:            "11.222.333/0001-81",
+		CodigoReceita:   "2089",
+		DataVencimento:  "31/01/2025",
+		ValorPrincipal:  "1.000,00",
+		ValorMulta:      "20,00",
+		ValorJuros:      "10,50",
+		ValorTotal:      "1.030,50",
+	}
+}
+
+func TestParseDarf(t *testing.T) {
+	record, err := ParseDarf(darfData())
+	if err != nil {
+		t.Fatalf("ParseDarf falhou: %v", err)
+	}
+	if record.CNPJ != "11222333000181" || record.ValorTotal != 103050 || record.PeriodoApuracao.Format("2006-01-02") != "2024-12-31" || record.Vencimento == nil {
+		t.Errorf("DARF inesperado: %+v", record)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(d *extraction.DarfData)
+		erro   string
+	}{
+		{"período", func(d *extraction.DarfData) { d.PeriodoApuracao = "" }, "período de apuração"},
+		{"CNPJ", func(d *extraction.DarfData) { d.CNPJ = "11.222.333/0001-80" }, "dígito verificador"},
+		{"receita", func(d *extraction.DarfData) { d.CodigoReceita = "100099" }, "código da receita"},
+		{"juros", func(d *extraction.DarfData) { d.ValorJuros = "abc" }, "valor dos juros"},
+		{"soma", func(d *extraction.DarfData) { d.ValorMulta = "" }, "difere de principal + multa + juros"},
+	}
+	for _, tt := range tests {
+		data := darfData()
+		tt.modify(data)
+		if _, err := ParseDarf(data); err == nil || !strings.Contains(err.Error(), tt.erro) {
+			t.Errorf("%s: erro %v, esperado %q", tt.name, err, tt.erro)
+		}
+	}
+}
+
+func TestParseGnre(t *testing.T) {
+	data := &extraction.GnreData{
+		UFFavorecida:      "SP",
+		CodigoReceita:     "100099",
+		PeriodoReferencia: "12/2024",
+		DataVencimento:    "10/01/2025",
+		ValorPrincipal:    "500,00",
+		ValorTotal:        "512,30",
+		NumeroControle:    "2025010100000017",
+	}
+	record, err := ParseGnre(data)
+	if err != nil {
+		t.Fatalf("ParseGnre falhou: %v", err)
+	}
+	if record.CNPJ != "" || record.Referencia.String() != "12/2024" || record.ValorTotal != 51230 {
+		t.Errorf("GNRE inesperada: %+v", record)
+	}
+
+	invalid := map[string]func(d extraction.GnreData) extraction.GnreData{
+		"UF":         func(d extraction.GnreData) extraction.GnreData { d.UFFavorecida = "XX"; return d },
+		"receita":    func(d extraction.GnreData) extraction.GnreData { d.CodigoReceita = "2089"; return d },
+		"CNPJ":       func(d extraction.GnreData) extraction.GnreData { d.CNPJ = "11.222.333/0001-80"; return d },
+		"referência": func(d extraction.GnreData) extraction.GnreData { d.PeriodoReferencia = "13/2024"; return d },
+		"total":      func(d extraction.GnreData) extraction.GnreData { d.ValorTotal = "400,00"; return d },
+	}
+	for name, modify := range invalid {
+		modified := modify(*data)
+		if _, err := ParseGnre(&modified); err == nil {
+			t.Errorf("%s: deveria falhar", name)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Money é um valor monetário exato em centavos, sem erro de arredondamento
+// de ponto flutuante em somas e na geração do SQL
+type Money int64
+
+var (
+	// moneyBRRegex aceita milhar com ponto e centavos com vírgula (ex.: 1.234,56)
+	moneyBRRegex = regexp.MustCompile(`^(\d{1,3}(?:\.\d{3})+|\d+),(\d{1,2})$`)
+	// moneyThousandsRegex aceita apenas milhares sem centavos (ex.: 1.234.567)
+	moneyThousandsRegex = regexp.MustCompile(`^\d{1,3}(?:\.\d{3}){2,}$`)
+	// moneyDecimalRegex aceita ponto decimal com até duas casas (ex.: 1234.56)
+	moneyDecimalRegex = regexp.MustCompile(`^(\d+)\.(\d{1,2})$`)
+	// moneyIntegerRegex aceita reais sem separadores (ex.: 1234)
+	moneyIntegerRegex = regexp.MustCompile(`^\d+$`)
+	// moneyAmbiguousRegex é ponto seguido de três dígitos sem vírgula (ex.: 1.234)
+	moneyAmbiguousRegex = regexp.MustCompile(`^\d{1,3}\.\d{3}$`)
+)
+
+// maxMoneyReais é o maior valor em reais representável em centavos
+const maxMoneyReais = math.MaxInt64 / 100
+
+// ParseMoneyBR converte um valor no formato brasileiro (ex.: "R$ 1.234,56",
+// "1234,56", "1234"). Também aceita ponto decimal com até duas casas ("1234.56").
+// Valores ambíguos como "1.234" (milhar ou decimal?) e mais de duas casas
+// decimais são rejeitados.
+func ParseMoneyBR(value string) (Money, error) {
+	clean := strings.Join(strings.Fields(value), "")
+	clean = strings.TrimPrefix(clean, "R$")
+
+	negative := strings.HasPrefix(clean, "-")
+	clean = strings.TrimPrefix(clean, "-")
+	if clean == "" {
+		return 0, fmt.Errorf("valor monetário vazio: %q", value)
+	}
+
+	var reais, fracao string
+	switch {
+	case moneyBRRegex.MatchString(clean):
+		parts := moneyBRRegex.FindStringSubmatch(clean)
+		reais, fracao = strings.ReplaceAll(parts[1], ".", ""), parts[2]
+	case moneyThousandsRegex.MatchString(clean):
+		reais = strings.ReplaceAll(clean, ".", "")
+	case moneyAmbiguousRegex.MatchString(clean):
+		return 0, fmt.Errorf("valor monetário ambíguo: %q (use vírgula nos centavos, ex.: %s,00)", value, clean)
+	case moneyDecimalRegex.MatchString(clean):
+		parts := moneyDecimalRegex.FindStringSubmatch(clean)
+		reais, fracao = parts[1], parts[2]
+	case moneyIntegerRegex.MatchString(clean):
+		reais = clean
+	default:
+		return 0, fmt.Errorf("valor monetário inválido: %q", value)
+	}
+
+	inteiro, err := strconv.ParseInt(reais, 10, 64)
+	if err != nil || inteiro > maxMoneyReais {
+		return 0, fmt.Errorf("valor monetário fora do limite: %q", value)
+	}
+	centavos, _ := strconv.ParseInt((fracao + "00")[:2], 10, 64)
+
+	money := Money(inteiro*100 + centavos)
+	if negative {
+		return -money, nil
+	}
+	return money, nil
+}
+
+// MoneyFromFloat converte um float em centavos, arredondando meio centavo para cima
+func MoneyFromFloat(value float64) Money {
+	return Money(math.Round(value * 100))
+}
+
+// Add soma dois valores
+func (m Money) Add(other Money) Money { return m + other }
+
+// Sub subtrai um valor
+func (m Money) Sub(other Money) Money { return m - other }
+
+// Centavos retorna o valor em centavos
+func (m Money) Centavos() int64 { return int64(m) }
+
+// split separa sinal, reais e centavos do valor absoluto
+func (m Money) split() (sign string, reais, centavos int64) {
+	value := int64(m)
+	if value < 0 {
+		sign = "-"
+		// -MinInt64 não cabe em int64: separar antes de inverter o sinal
+		return sign, -(value / 100), -(value % 100)
+	}
+	return sign, value / 100, value % 100
+}
+
+// Decimal formata com ponto decimal, como no SQL (ex.: 1234.56)
+func (m Money) Decimal() string {
+	sign, reais, centavos := m.split()
+	return fmt.Sprintf("%s%d.%02d", sign, reais, centavos)
+}
+
+// String formata no padrão brasileiro (ex.: 1.234,56)
+func (m Money) String() string {
+	sign, reais, centavos := m.split()
+	inteiro := strconv.FormatInt(reais, 10)
+	for i := len(inteiro) - 3; i > 0; i -= 3 {
+		inteiro = inteiro[:i] + "." + inteiro[i:]
+	}
+	return fmt.Sprintf("%s%s,%02d", sign, inteiro, centavos)
+}
+
+// BRL formata como moeda brasileira (ex.: R$ 1.234,56)
+func (m Money) BRL() string {
+	return "R$ " + m.String()
+}
+
+// MarshalJSON grava o valor como número decimal
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON aceita número decimal (1234.56) ou texto no formato brasileiro
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	parsed, err := ParseMoneyBR(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
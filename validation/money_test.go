@@ -0,0 +1,67 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoneyBR(t *testing.T) {
+	valid := map[string]Money{
+		"R$ 1.234,56":     123456,
+		"1.234,56":        123456,
+		"1234,56":         123456,
+		"1234,5":          123450,
+		"1234.56":         123456,
+		"1234":            123400,
+		"R$ 1.000.000,00": 100000000,
+		"1.234.567":       123456700,
+		"-10,01":          -1001,
+		"R$ 0,00":         0,
+		"9.014,06":        901406,
+	}
+	for input, expected := range valid {
+		got, err := ParseMoneyBR(input)
+		if err != nil {
+			t.Errorf("ParseMoneyBR(%q) falhou: %v", input, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("ParseMoneyBR(%q) = %d, esperado %d", input, got, expected)
+		}
+	}
+
+	for _, input := range []string{"", "R$", "1.234", "12,345", "1,234.56", "12.34.56", "1.23,45", "abc", "99999999999999999999"} {
+		if got, err := ParseMoneyBR(input); err == nil {
+			t.Errorf("ParseMoneyBR(%q) deveria falhar, obtido %d", input, got)
+		}
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	tests := map[Money][2]string{
+		123456:    {"1234.56", "1.234,56"},
+		50:        {"0.50", "0,50"},
+		123456700: {"1234567.00", "1.234.567,00"},
+		-1001:     {"-10.01", "-10,01"},
+		-5:        {"-0.05", "-0,05"},
+	}
+	for money, expected := range tests {
+		if got := money.Decimal(); got != expected[0] {
+			t.Errorf("Money(%d).Decimal() = %q, esperado %q", money, got, expected[0])
+		}
+		if got := money.String(); got != expected[1] {
+			t.Errorf("Money(%d).String() = %q, esperado %q", money, got, expected[1])
+		}
+	}
+
+	if got := Money(10).Add(20).Sub(5); got != 25 {
+		t.Errorf("aritmética inesperada: %d", got)
+	}
+	if got := MoneyFromFloat(0.1 + 0.2); got != 30 {
+		t.Errorf("MoneyFromFloat(0.1 + 0.2) = %d, esperado 30", got)
+	}
+	content, err := json.Marshal(struct{ V Money }{Money(-1001)})
+	if err != nil || string(content) != `{"V":-10.01}` {
+		t.Errorf("MarshalJSON inesperado: %s (%v)", content, err)
+	}
+}
@@ -1,64 +1,11 @@
-package main
+package validation
 
 import (
-	"fmt"
-	"os"
-	"path/filepath"
 	"regexp"
 	"strconv"
-	"strings"
 	"time"
 )
 
-// FileUtils contém utilitários para manipulação de arquivos
-type FileUtils struct{}
-
-// NewFileUtils cria nova instância de FileUtils
-func NewFileUtils() *FileUtils {
-	return &FileUtils{}
-}
-
-// EnsureDir cria diretório se não existir
-func (fu *FileUtils) EnsureDir(path string) error {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return os.MkdirAll(path, 0755)
-	}
-	return nil
-}
-
-// FileExists verifica se arquivo existe
-func (fu *FileUtils) FileExists(path string) bool {
-	_, err := os.Stat(path)
-	return !os.IsNotExist(err)
-}
-
-// GetFileSize retorna tamanho do arquivo
-func (fu *FileUtils) GetFileSize(path string) (int64, error) {
-	info, err := os.Stat(path)
-	if err != nil {
-		return 0, err
-	}
-	return info.Size(), nil
-}
-
-// CopyFile copia arquivo
-func (fu *FileUtils) CopyFile(src, dst string) error {
-	// Ler arquivo fonte
-	data, err := os.ReadFile(src)
-	if err != nil {
-		return err
-	}
-
-	// Criar diretório de destino se não existir
-	dir := filepath.Dir(dst)
-	if err := fu.EnsureDir(dir); err != nil {
-		return err
-	}
-
-	// Escrever arquivo destino
-	return os.WriteFile(dst, data, 0644)
-}
-
 // StringUtils contém utilitários para manipulação de strings
 type StringUtils struct{}
 
@@ -85,12 +32,12 @@ func (su *StringUtils) RemoveAccents(s string) string {
 		'Ç': 'C',
 	}
 
-	result := make([]rune, len(s))
-	for i, r := range s {
+	result := make([]rune, 0, len(s))
+	for _, r := range s {
 		if replacement, exists := accents[r]; exists {
-			result[i] = replacement
+			result = append(result, replacement)
 		} else {
-			result[i] = r
+			result = append(result, r)
 		}
 	}
 	return string(result)
@@ -106,29 +53,14 @@ func (su *StringUtils) CleanString(s string) string {
 	return re.ReplaceAllString(s, "")
 }
 
-// FormatCurrency formata valor como moeda
-func (su *StringUtils) FormatCurrency(value float64) string {
-	return fmt.Sprintf("%.2f", value)
+// FormatCurrency formata valor como moeda no padrão brasileiro (ex.: R$ 1.234,56)
+func (su *StringUtils) FormatCurrency(value Money) string {
+	return value.BRL()
 }
 
-// ParseCurrency parseia string de moeda para float
-func (su *StringUtils) ParseCurrency(s string) (float64, error) {
-	// Remover R$, espaços e pontos de milhares
-	clean := regexp.MustCompile(`[R$\s]`).ReplaceAllString(s, "")
-
-	// Se tem vírgula, tratar como separador decimal brasileiro
-	if strings.Contains(clean, ",") {
-		if strings.Contains(clean, ".") {
-			// Formato brasileiro: 9.014,06
-			clean = strings.ReplaceAll(clean, ".", "")
-			clean = strings.ReplaceAll(clean, ",", ".")
-		} else {
-			// Só vírgula: 9014,06
-			clean = strings.ReplaceAll(clean, ",", ".")
-		}
-	}
-
-	return strconv.ParseFloat(clean, 64)
+// ParseCurrency parseia string de moeda no formato brasileiro para centavos
+func (su *StringUtils) ParseCurrency(s string) (Money, error) {
+	return ParseMoneyBR(s)
 }
 
 // DateUtils contém utilitários para manipulação de datas
@@ -305,55 +237,3 @@ func (vu *ValidationUtils) IsValidDate(dateStr string) bool {
 	_, err := time.Parse("02/01/2006", dateStr)
 	return err == nil
 }
-
-// SQLUtils contém utilitários para SQL
-type SQLUtils struct{}
-
-// NewSQLUtils cria nova instância de SQLUtils
-func NewSQLUtils() *SQLUtils {
-	return &SQLUtils{}
-}
-
-// EscapeString escapa string para SQL
-func (su *SQLUtils) EscapeString(s string) string {
-	// Substituir aspas simples por duas aspas simples
-	return strings.ReplaceAll(s, "'", "''")
-}
-
-// QuoteString coloca string entre aspas simples
-func (su *SQLUtils) QuoteString(s string) string {
-	return "'" + su.EscapeString(s) + "'"
-}
-
-// FormatSQLValue formata valor para SQL
-func (su *SQLUtils) FormatSQLValue(value interface{}) string {
-	switch v := value.(type) {
-	case string:
-		if v == "" {
-			return "NULL"
-		}
-		return su.QuoteString(v)
-	case int, int32, int64:
-		return fmt.Sprintf("%v", v)
-	case float32, float64:
-		return fmt.Sprintf("%.2f", v)
-	case bool:
-		if v {
-			return "1"
-		}
-		return "0"
-	case nil:
-		return "NULL"
-	default:
-		return su.QuoteString(fmt.Sprintf("%v", v))
-	}
-}
-
-// GeneratePlaceholders gera placeholders para SQL
-func (su *SQLUtils) GeneratePlaceholders(count int) string {
-	placeholders := make([]string, count)
-	for i := 0; i < count; i++ {
-		placeholders[i] = "?"
-	}
-	return strings.Join(placeholders, ", ")
-}
@@ -0,0 +1,133 @@
+package validation
+
+import "testing"
+
+// TestUtils testa utilitários
+func TestUtils(t *testing.T) {
+	t.Run("RemoveLeadingZeros", testRemoveLeadingZeros)
+	t.Run("StringUtils", testStringUtils)
+	t.Run("DateUtils", testDateUtils)
+	t.Run("ValidationUtils", testValidationUtils)
+}
+
+// testRemoveLeadingZeros testa remoção de zeros à esquerda
+func testRemoveLeadingZeros(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"00123", "123"},
+		{"123", "123"},
+		// Guia zerada equivale a guia ausente (NR_GUIA = 0 com aviso)
+		{"000", ""},
+		{"0", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		result := removeLeadingZeros(test.input)
+		if result != test.expected {
+			t.Errorf("removeLeadingZeros(%s) = %s, esperado %s", test.input, result, test.expected)
+		}
+	}
+}
+
+// testStringUtils testa utilitários de string
+func testStringUtils(t *testing.T) {
+	stringUtils := NewStringUtils()
+
+	// Test RemoveAccents
+	if stringUtils.RemoveAccents("café") != "cafe" {
+		t.Error("RemoveAccents falhou")
+	}
+
+	if stringUtils.RemoveAccents("São Paulo") != "Sao Paulo" {
+		t.Error("RemoveAccents falhou")
+	}
+
+	// Test CleanString
+	if stringUtils.CleanString("café@#$%") != "cafe" {
+		t.Error("CleanString falhou")
+	}
+
+	// Test FormatCurrency
+	if stringUtils.FormatCurrency(Money(123456)) != "R$ 1.234,56" {
+		t.Error("FormatCurrency falhou")
+	}
+
+	// Test ParseCurrency
+	value, err := stringUtils.ParseCurrency("R$ 1.234,56")
+	if err != nil {
+		t.Errorf("ParseCurrency falhou: %v", err)
+	}
+	if value != Money(123456) {
+		t.Errorf("ParseCurrency retornou %s, esperado 1.234,56", value)
+	}
+}
+
+// testDateUtils testa utilitários de data
+func testDateUtils(t *testing.T) {
+	dateUtils := NewDateUtils()
+
+	// Test ParseDateBR
+	date, err := dateUtils.ParseDateBR("15/12/2024")
+	if err != nil {
+		t.Errorf("ParseDateBR falhou: %v", err)
+	}
+
+	// Test FormatDateBR
+	formatted := dateUtils.FormatDateBR(date)
+	if formatted != "15/12/2024" {
+		t.Errorf("FormatDateBR retornou %s, esperado 15/12/2024", formatted)
+	}
+
+	// Test ConvertDateBRToSQL
+	sqlDate, err := dateUtils.ConvertDateBRToSQL("15/12/2024")
+	if err != nil {
+		t.Errorf("ConvertDateBRToSQL falhou: %v", err)
+	}
+	if sqlDate != "2024-12-15" {
+		t.Errorf("ConvertDateBRToSQL retornou %s, esperado 2024-12-15", sqlDate)
+	}
+}
+
+// testValidationUtils testa utilitários de validação
+func testValidationUtils(t *testing.T) {
+	validationUtils := NewValidationUtils()
+
+	// Test IsValidCPF
+	if !validationUtils.IsValidCPF("123.456.789-09") {
+		t.Error("CPF válido foi rejeitado")
+	}
+
+	if validationUtils.IsValidCPF("123.456.789-10") {
+		t.Error("CPF inválido foi aceito")
+	}
+
+	// Test IsValidCNPJ
+	if !validationUtils.IsValidCNPJ("11.222.333/0001-81") {
+		t.Error("CNPJ válido foi rejeitado")
+	}
+
+	if validationUtils.IsValidCNPJ("11.222.333/0001-82") {
+		t.Error("CNPJ inválido foi aceito")
+	}
+
+	// Test IsValidEmail
+	if !validationUtils.IsValidEmail("test@example.com") {
+		t.Error("Email válido foi rejeitado")
+	}
+
+	if validationUtils.IsValidEmail("invalid-email") {
+		t.Error("Email inválido foi aceito")
+	}
+
+	// Test IsValidDate
+	if !validationUtils.IsValidDate("15/12/2024") {
+		t.Error("Data válida foi rejeitada")
+	}
+
+	if validationUtils.IsValidDate("32/13/2024") {
+		t.Error("Data inválida foi aceita")
+	}
+}
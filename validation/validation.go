@@ -0,0 +1,56 @@
+// Package validation converte os textos extraídos do DARM em valores tipados
+// (Money, CodigoReceita, Competencia) e monta o DarmRecord validado.
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Modos de validação (validation.mode)
+const (
+	// ModeLenient aplica os valores padrão e registra avisos na linha
+	ModeLenient = "lenient"
+	// ModeStrict rejeita o PDF quando algum valor padrão seria aplicado
+	ModeStrict = "strict"
+)
+
+// Substituicao é um valor assumido na linha por falta de dado válido no PDF
+type Substituicao struct {
+	Campo  string `json:"campo"`
+	Valor  string `json:"valor"`
+	Motivo string `json:"motivo"`
+}
+
+// String descreve a substituição como "CAMPO = valor (motivo)"
+func (s Substituicao) String() string {
+	return fmt.Sprintf("%s = %s (%s)", s.Campo, s.Valor, s.Motivo)
+}
+
+// ParseMode valida o modo (vazio = lenient)
+func ParseMode(mode string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", ModeLenient:
+		return ModeLenient, nil
+	case ModeStrict:
+		return ModeStrict, nil
+	}
+	return "", fmt.Errorf("modo de validação inválido: %q (use lenient ou strict)", mode)
+}
+
+// strictError converte as substituições em erro de validação do modo estrito
+func strictError(substituicoes []Substituicao) error {
+	if len(substituicoes) == 0 {
+		return nil
+	}
+	return fmt.Errorf("validação estrita: %s", DescribeSubstituicoes(substituicoes))
+}
+
+// DescribeSubstituicoes resume as substituições em uma linha (relatórios e CSV)
+func DescribeSubstituicoes(substituicoes []Substituicao) string {
+	descricoes := make([]string, len(substituicoes))
+	for i, s := range substituicoes {
+		descricoes[i] = s.String()
+	}
+	return strings.Join(descricoes, "; ")
+}
@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/extraction"
+	"gerador-query-darm-go/internal/darmtest"
+)
+
+func TestParseDarmRecordSubstituicoes(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(d *extraction.DarmData)
+		campos  string
+		motivos []string
+	}{
+		{"completo", func(d *extraction.DarmData) {}, "", nil},
+		{"receita ausente", func(d *extraction.DarmData) { d.CodigoReceita = "" }, "CD_RECEITA", []string{"não encontrado"}},
+		{"exercício ausente", func(d *extraction.DarmData) { d.Exercicio = "" }, "AA_EXERCICIO", nil},
+		{"guia zerada", func(d *extraction.DarmData) { d.NumeroGuia = "000" }, "NR_GUIA", nil},
+		{"valor inválido", func(d *extraction.DarmData) { d.ValorPrincipal = "abc" }, "VL_PRINCIPAL", []string{`"abc"`}},
+		{"total ausente", func(d *extraction.DarmData) { d.ValorTotal = "" }, "VL_PAGO", []string{"usado VL_PRINCIPAL"}},
+		{"competência ausente", func(d *extraction.DarmData) { d.Competencia = "" }, "NR_COMPETENCIA", []string{"competência não encontrada"}},
+		{"vencimento inválido", func(d *extraction.DarmData) { d.DataVencimento = "31/02/2024" }, "DT_VENCTO", []string{"inválida"}},
+		{"pix conferido", func(d *extraction.DarmData) {
+			d.PixCopiaECola = darmtest.PixBRCode("pix@rio.rj.gov.br", "1234.56", "DARM0000001")
+		}, "", nil},
+		{"pix valor livre", func(d *extraction.DarmData) { d.PixCopiaECola = darmtest.PixBRCode("pix@rio.rj.gov.br", "", "***") }, "", nil},
+		{"pix valor diferente", func(d *extraction.DarmData) {
+			d.PixCopiaECola = darmtest.PixBRCode("pix@rio.rj.gov.br", "1000.00", "DARM0000001")
+		},
+			"PIX", []string{"R$ 1.000,00 difere do VALOR TOTAL R$ 1.234,56"}},
+		{"pix de outra guia", func(d *extraction.DarmData) {
+			d.PixCopiaECola = darmtest.PixBRCode("pix@rio.rj.gov.br", "1234.56", "DARM42")
+		},
+			"PIX", []string{"número da guia 1"}},
+		{"pix com a guia fora do final", func(d *extraction.DarmData) {
+			d.PixCopiaECola = darmtest.PixBRCode("pix@rio.rj.gov.br", "1234.56", "DARM2025100")
+		},
+			"PIX", []string{`"DARM2025100" não termina com o número da guia 1`}},
+		{"pix com a guia seguida de letras", func(d *extraction.DarmData) {
+			d.PixCopiaECola = darmtest.PixBRCode("pix@rio.rj.gov.br", "1234.56", "DARM0000001X")
+		},
+			"PIX", []string{"número da guia 1"}},
+		{"pix de guia com mais de 3 dígitos", func(d *extraction.DarmData) {
+			d.NumeroGuia, d.NumeroGuiaCompleto = "123", "123456789"
+			d.PixCopiaECola = darmtest.PixBRCode("pix@rio.rj.gov.br", "1234.56", "DARM123456789")
+		}, "", nil},
+		{"pix com só o início da guia", func(d *extraction.DarmData) {
+			d.NumeroGuia, d.NumeroGuiaCompleto = "123", "123456789"
+			d.PixCopiaECola = darmtest.PixBRCode("pix@rio.rj.gov.br", "1234.56", "DARM0000123")
+		},
+			"PIX", []string{"número da guia 123456789"}},
+		{"pix com guia corrigida manualmente", func(d *extraction.DarmData) {
+			d.NumeroGuia, d.NumeroGuiaCompleto = "42", "123456789"
+			d.PixCopiaECola = darmtest.PixBRCode("pix@rio.rj.gov.br", "1234.56", "DARM0000042")
+		}, "", nil},
+		{"pix com CRC inválido", func(d *extraction.DarmData) {
+			payload := darmtest.PixBRCode("pix@rio.rj.gov.br", "1234.56", "DARM0000001")
+			d.PixCopiaECola = payload[:len(payload)-4] + "0000"
+		}, "PIX", []string{"CRC inválido"}},
+	}
+
+	for _, tt := range tests {
+		data := darmtest.DarmData("0001.pdf")
+		tt.modify(data)
+
+		record, err := Parse(data, Options{})
+		if err != nil {
+			t.Fatalf("%s: Parse falhou: %v", tt.name, err)
+		}
+		substituicoes := record.Substituicoes
+		campos := []string{}
+		for _, s := range substituicoes {
+			campos = append(campos, s.Campo)
+		}
+		if strings.Join(campos, ",") != tt.campos {
+			t.Errorf("%s: substituições = %v, esperado %q", tt.name, substituicoes, tt.campos)
+		}
+		for _, motivo := range tt.motivos {
+			if !strings.Contains(DescribeSubstituicoes(substituicoes), motivo) {
+				t.Errorf("%s: motivo deveria conter %q: %v", tt.name, motivo, substituicoes)
+			}
+		}
+		if (record.Pix != nil) != (data.PixCopiaECola != "" && tt.campos == "") {
+			t.Errorf("%s: Pix conferido inesperado: %+v", tt.name, record.Pix)
+		}
+	}
+}
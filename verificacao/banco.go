@@ -0,0 +1,206 @@
+package verificacao
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gerador-query-darm-go/sqlgen"
+)
+
+// queryBatchSize é o número máximo de guias por SELECT no banco
+const queryBatchSize = 500
+
+// nullValues são as representações de NULL nos dumps (mysql --batch, SELECT ... INTO OUTFILE)
+var nullValues = map[string]bool{"NULL": true, `\N`: true}
+
+// ReadCSV lê um dump de FarrDarmsPagos em CSV (separado por ponto e vírgula,
+// vírgula ou tabulação, detectado pelo cabeçalho) com os nomes das colunas no
+// cabeçalho; colunas desconhecidas são ignoradas
+func ReadCSV(path string) ([]Linha, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir dump de FarrDarmsPagos: %v", err)
+	}
+	defer file.Close()
+
+	linhas, err := ParseCSV(file, filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler %s: %v", filepath.Base(path), err)
+	}
+	return linhas, nil
+}
+
+// ParseCSV lê um dump de FarrDarmsPagos em CSV; origem identifica as linhas
+func ParseCSV(r io.Reader, origem string) ([]Linha, error) {
+	buffered := bufio.NewReader(r)
+	header, err := buffered.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	firstLine := string(header)
+	if i := strings.IndexByte(firstLine, '\n'); i >= 0 {
+		firstLine = firstLine[:i]
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = ','
+	for _, separator := range []rune{';', '\t'} {
+		if strings.ContainsRune(firstLine, separator) {
+			reader.Comma = separator
+			break
+		}
+	}
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("arquivo vazio")
+	}
+
+	known := map[string]string{}
+	for _, column := range sqlgen.Columns() {
+		known[strings.ToLower(column)] = column
+	}
+	columns := make([]string, len(records[0]))
+	guiaFound := false
+	for i, name := range records[0] {
+		name = strings.Trim(strings.TrimPrefix(strings.TrimSpace(name), "\ufeff"), "`\"")
+		columns[i] = known[strings.ToLower(name)]
+		guiaFound = guiaFound || columns[i] == "NR_GUIA"
+	}
+	if !guiaFound {
+		return nil, fmt.Errorf("coluna NR_GUIA ausente no cabeçalho")
+	}
+
+	linhas := make([]Linha, 0, len(records)-1)
+	for n, record := range records[1:] {
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		valores := map[string]string{}
+		for i, value := range record {
+			if i >= len(columns) || columns[i] == "" {
+				continue
+			}
+			if nullValues[strings.TrimSpace(value)] {
+				value = ""
+			}
+			valores[columns[i]] = value
+		}
+		linha, err := newLinha(fmt.Sprintf("%s:%d", origem, n+2), valores)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: %v", n+2, err)
+		}
+		linhas = append(linhas, linha)
+	}
+	return linhas, nil
+}
+
+// ReadDatabase lê de FarrDarmsPagos as linhas das guias informadas nos lotes
+// de arrecadação informados (ver Lotes), em consultas de até queryBatchSize
+// guias; sem lotes, as guias são lidas de todos os lotes
+func ReadDatabase(ctx context.Context, db *sql.DB, guias []int, lotes []map[string]string) ([]Linha, error) {
+	columns := []string{}
+	for _, column := range sqlgen.Columns() {
+		if !ignoredColumns[column] {
+			columns = append(columns, column)
+		}
+	}
+	loteCondition, loteArgs := lotesCondition(lotes)
+
+	linhas := []Linha{}
+	for start := 0; start < len(guias); start += queryBatchSize {
+		end := start + queryBatchSize
+		if end > len(guias) {
+			end = len(guias)
+		}
+		args := make([]interface{}, 0, end-start+len(loteArgs))
+		for _, guia := range guias[start:end] {
+			args = append(args, guia)
+		}
+		query := fmt.Sprintf("SELECT %s FROM FarrDarmsPagos WHERE NR_GUIA IN (%s)%s ORDER BY NR_GUIA",
+			strings.Join(columns, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", "), loteCondition)
+		args = append(args, loteArgs...)
+
+		lidas, err := queryLinhas(ctx, db, query, columns, args)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao consultar FarrDarmsPagos: %v", err)
+		}
+		linhas = append(linhas, lidas...)
+	}
+	return linhas, nil
+}
+
+// lotesCondition monta a restrição aos lotes de arrecadação, ex.:
+// " AND ((CD_BANCO = ? AND NR_BDA = ? ...) OR (...))", e seus argumentos
+func lotesCondition(lotes []map[string]string) (string, []interface{}) {
+	alternatives := []string{}
+	args := []interface{}{}
+	for _, lote := range lotes {
+		conditions := []string{}
+		for _, column := range sqlgen.LoteColumns() {
+			value, ok := lote[column]
+			if !ok {
+				continue
+			}
+			conditions = append(conditions, column+" = ?")
+			if n, err := strconv.Atoi(value); err == nil {
+				args = append(args, n)
+			} else {
+				args = append(args, value)
+			}
+		}
+		if len(conditions) == 0 {
+			// Lote sem valores conhecidos: não há como restringir a consulta
+			return "", nil
+		}
+		alternatives = append(alternatives, "("+strings.Join(conditions, " AND ")+")")
+	}
+	if len(alternatives) == 0 {
+		return "", nil
+	}
+	return " AND (" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// queryLinhas executa a consulta e converte cada linha do resultado
+func queryLinhas(ctx context.Context, db *sql.DB, query string, columns []string, args []interface{}) ([]Linha, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	linhas := []Linha{}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		valores := make(map[string]string, len(columns))
+		for i, column := range columns {
+			valores[column] = values[i].String
+		}
+		linha, err := newLinha("FarrDarmsPagos", valores)
+		if err != nil {
+			return nil, err
+		}
+		linhas = append(linhas, linha)
+	}
+	return linhas, rows.Err()
+}
@@ -0,0 +1,121 @@
+package verificacao
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakeDriver responde às consultas com as linhas de fakeRows, registrando as consultas
+type fakeDriver struct {
+	queries []string
+	args    [][]driver.Value
+}
+
+var fakeRows = map[int64][]string{
+	1: {"2025", "70", "37", "0", "730", "1", "1000", "2623", "FARR", "2025-01-10 08:00:00", "2024-12-15", "2025-01-10 08:00:00",
+		"90000001", "1", "2025", "", "", "13", "", "1234.5600", "1234.5600", "1234.5600", "0.0000", "0.0000", "", "", "", "0.0000"},
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{c.d, query}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return nil, fmt.Errorf("sem transações") }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return strings.Count(s.query, "?") }
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("somente consultas")
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.queries = append(s.d.queries, s.query)
+	s.d.args = append(s.d.args, args)
+	rows := &fakeResult{columns: strings.Split(strings.TrimPrefix(strings.Split(s.query, " FROM ")[0], "SELECT "), ", ")}
+	// Apenas os argumentos de NR_GUIA IN (...) selecionam linhas
+	in := strings.SplitN(strings.SplitN(s.query, "IN (", 2)[1], ")", 2)[0]
+	for _, arg := range args[:strings.Count(in, "?")] {
+		if row, ok := fakeRows[arg.(int64)]; ok {
+			rows.rows = append(rows.rows, row)
+		}
+	}
+	return rows, nil
+}
+
+type fakeResult struct {
+	columns []string
+	rows    [][]string
+}
+
+func (r *fakeResult) Columns() []string { return r.columns }
+func (r *fakeResult) Close() error      { return nil }
+func (r *fakeResult) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	for i, value := range r.rows[0] {
+		if value == "" {
+			dest[i] = nil
+		} else {
+			dest[i] = []byte(value)
+		}
+	}
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestReadDatabase(t *testing.T) {
+	fake := &fakeDriver{}
+	sql.Register("verificacao-fake", fake)
+	db, err := sql.Open("verificacao-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	guias := make([]int, queryBatchSize+1)
+	for i := range guias {
+		guias[i] = i + 1
+	}
+	lotes := []map[string]string{
+		{"CD_BANCO": "70", "NR_BDA": "37", "NR_COMPLEMENTO": "0", "NR_LOTE_NSA": "730", "TP_LOTE_D": "1"},
+		{"CD_BANCO": "1", "NR_BDA": "5", "NR_COMPLEMENTO": "0", "NR_LOTE_NSA": "12", "TP_LOTE_D": "1"},
+	}
+	linhas, err := ReadDatabase(context.Background(), db, guias, lotes)
+	if err != nil {
+		t.Fatalf("ReadDatabase falhou: %v", err)
+	}
+
+	// Guias consultadas em lotes de queryBatchSize
+	if len(fake.queries) != 2 || len(fake.args[0]) != queryBatchSize+10 || len(fake.args[1]) != 1+10 {
+		t.Errorf("consultas inesperadas: %d", len(fake.queries))
+	}
+	// Restritas aos lotes dos scripts: a mesma guia se repete em outros lotes
+	lote := " AND ((CD_BANCO = ? AND NR_BDA = ? AND NR_COMPLEMENTO = ? AND NR_LOTE_NSA = ? AND TP_LOTE_D = ?) OR (CD_BANCO = ? AND NR_BDA = ? AND NR_COMPLEMENTO = ? AND NR_LOTE_NSA = ? AND TP_LOTE_D = ?)) ORDER BY"
+	if !strings.Contains(fake.queries[1], lote) || fake.args[1][1] != int64(70) || fake.args[1][9] != int64(12) {
+		t.Errorf("consulta sem restrição de lote: %s %v", fake.queries[1], fake.args[1])
+	}
+	if strings.Contains(fake.queries[0], "processado") || !strings.HasPrefix(fake.queries[0], "SELECT AA_EXERCICIO, CD_BANCO") {
+		t.Errorf("colunas inesperadas: %s", fake.queries[0])
+	}
+
+	if len(linhas) != 1 || linhas[0].Guia != 1 {
+		t.Fatalf("linhas inesperadas: %+v", linhas)
+	}
+	valores := linhas[0].Valores
+	if valores["VL_PAGO"] != "1234.56" || valores["DT_VENCTO"] != "2024-12-15 00:00:00" || valores["NR_CODIGO_BARRAS"] != "" {
+		t.Errorf("valores inesperados: %+v", valores)
+	}
+}
@@ -0,0 +1,102 @@
+package verificacao
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"gerador-query-darm-go/output"
+)
+
+// RelatorioBase é o nome (sem extensão) do relatório de verificação
+const RelatorioBase = "RELATORIO_VERIFICACAO"
+
+// statusOrder é a ordem dos status no resumo do relatório
+var statusOrder = []string{StatusOK, StatusDivergente, StatusAusente, StatusExtra}
+
+// statusLabel descreve o status para o relatório
+func statusLabel(status string) string {
+	switch status {
+	case StatusOK:
+		return "✅ Conferidas"
+	case StatusDivergente:
+		return "⚠️ Com colunas divergentes"
+	case StatusAusente:
+		return "❌ Ausentes no banco"
+	case StatusExtra:
+		return "➕ A mais no banco"
+	}
+	return status
+}
+
+// RenderMarkdown gera RELATORIO_VERIFICACAO.md
+func RenderMarkdown(v *Verificacao) string {
+	var b strings.Builder
+	escape := strings.NewReplacer("|", "\\|", "\n", " ").Replace
+
+	fmt.Fprintf(&b, "# RELATÓRIO DE VERIFICAÇÃO DA CARGA\n\n## Data/Hora: %s\n\n", v.GeradoEm.Format("02/01/2006 15:04:05"))
+	b.WriteString("### Resumo:\n")
+	fmt.Fprintf(&b, "- Scripts: %s\n- Banco: %s\n", v.Inserts, v.Fonte)
+	for _, status := range statusOrder {
+		fmt.Fprintf(&b, "- %s: %d\n", statusLabel(status), v.Contagem(status))
+	}
+
+	for _, status := range statusOrder[1:] {
+		if v.Contagem(status) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s:\n| Guia | Arquivo | Chave | Diferenças |\n|---|---|---|---|\n", statusLabel(status))
+		for _, item := range v.Itens {
+			if item.Status != status {
+				continue
+			}
+			diferencas := []string{}
+			for _, d := range item.Diferencas {
+				diferencas = append(diferencas, fmt.Sprintf("%s: %q ≠ %q", d.Coluna, d.Esperado, d.Banco))
+			}
+			fmt.Fprintf(&b, "| %d | %s | %s | %s |\n", item.Guia, escape(item.Arquivo), escape(item.Chave), escape(strings.Join(diferencas, "; ")))
+		}
+	}
+	return b.String()
+}
+
+// RenderCSV gera RELATORIO_VERIFICACAO.csv, uma linha por diferença de coluna
+// (itens sem diferenças ocupam uma linha com as colunas de diferença vazias)
+func RenderCSV(v *Verificacao) ([]byte, error) {
+	var b strings.Builder
+	writer := csv.NewWriter(&b)
+	writer.Comma = ';'
+	if err := writer.Write([]string{"status", "guia", "arquivo", "chave", "coluna", "esperado", "banco"}); err != nil {
+		return nil, err
+	}
+	for _, item := range v.Itens {
+		diferencas := item.Diferencas
+		if len(diferencas) == 0 {
+			diferencas = []Diferenca{{}}
+		}
+		for _, d := range diferencas {
+			line := []string{item.Status, strconv.Itoa(item.Guia), item.Arquivo, item.Chave, d.Coluna, d.Esperado, d.Banco}
+			if err := writer.Write(line); err != nil {
+				return nil, err
+			}
+		}
+	}
+	writer.Flush()
+	return []byte(b.String()), writer.Error()
+}
+
+// WriteRelatorio grava o relatório de verificação em Markdown, JSON e CSV
+func WriteRelatorio(dir string, v *Verificacao) error {
+	err := output.WriteFormatos(dir, RelatorioBase, "relatório de verificação",
+		output.MarkdownFormato(RenderMarkdown(v)), output.JSONFormato(v), output.CSVFormato(func() ([]byte, error) { return RenderCSV(v) }))
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("📋 Relatório gerado: %s.md/.json/.csv (%d conferida(s), %d divergente(s), %d ausente(s), %d a mais)",
+		RelatorioBase, v.Contagem(StatusOK), v.Contagem(StatusDivergente), v.Contagem(StatusAusente), v.Contagem(StatusExtra))
+	return nil
+}
@@ -0,0 +1,379 @@
+// Package verificacao confere, depois da carga, se as linhas de FarrDarmsPagos
+// no banco correspondem às geradas nos scripts de inserts: linhas ausentes,
+// linhas a mais das mesmas guias e diferenças de colunas (valores, datas,
+// código de barras) por guia.
+package verificacao
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gerador-query-darm-go/sqlgen"
+)
+
+// Status de verificação de cada linha
+const (
+	// StatusOK indica linha encontrada no banco com os mesmos valores
+	StatusOK = "ok"
+	// StatusDivergente indica linha encontrada no banco com colunas diferentes
+	StatusDivergente = "divergente"
+	// StatusAusente indica linha gerada que não está no banco
+	StatusAusente = "ausente"
+	// StatusExtra indica linha do banco de uma guia gerada sem linha correspondente nos scripts
+	StatusExtra = "extra"
+)
+
+// ConsolidatedFile é o script único, que tem precedência sobre os arquivos por guia
+const ConsolidatedFile = "INSERT_TODOS_DARMs.sql"
+
+// perGuiaPattern reconhece os scripts individuais por guia
+const perGuiaPattern = "INSERT_DARM_PAGO_*.sql"
+
+// ignoredColumns são mantidas pelo sistema após a carga (alteração,
+// processamento) ou geradas pelo banco, e não são comparadas
+var ignoredColumns = map[string]bool{
+	"id":                   true,
+	"CD_USU_ALT":           true,
+	"DT_ALT":               true,
+	"processado":           true,
+	"criticaProcessamento": true,
+}
+
+// decimalRegex reconhece valores decimais, normalizados sem zeros à direita
+var decimalRegex = regexp.MustCompile(`^-?\d+\.\d+$`)
+
+// dateLayouts são os formatos de data aceitos dos scripts e do banco
+var dateLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+	"02/01/2006 15:04:05",
+	"02/01/2006",
+}
+
+// Linha é uma linha de FarrDarmsPagos com os valores normalizados para
+// comparação: NULL como vazio, datas como AAAA-MM-DD HH:MM:SS e decimais sem
+// zeros à direita. Colunas calculadas no banco (NOW(), SQ_DOC dinâmico) e
+// ignoradas não constam de Valores.
+type Linha struct {
+	// Origem é o script (ou a fonte do banco) de onde a linha foi lida
+	Origem  string            `json:"origem"`
+	Guia    int               `json:"guia"`
+	Valores map[string]string `json:"valores"`
+}
+
+// Chave descreve as colunas que identificam a linha
+func (l Linha) Chave() string {
+	parts := []string{}
+	for _, column := range sqlgen.KeyColumns() {
+		if value, ok := l.Valores[column]; ok {
+			parts = append(parts, column+"="+value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Lote descreve as colunas do lote de arrecadação da linha
+func (l Linha) Lote() string {
+	parts := []string{}
+	for _, column := range sqlgen.LoteColumns() {
+		parts = append(parts, column+"="+l.Valores[column])
+	}
+	return strings.Join(parts, " ")
+}
+
+// Lotes retorna os lotes de arrecadação das linhas geradas (coluna → valor,
+// apenas as colunas com valor conhecido no script), sem repetição e em ordem
+func Lotes(linhas []Linha) []map[string]string {
+	seen := map[string]bool{}
+	keys := []string{}
+	lotes := map[string]map[string]string{}
+	for _, linha := range linhas {
+		key := linha.Lote()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		lote := map[string]string{}
+		for _, column := range sqlgen.LoteColumns() {
+			if value, ok := linha.Valores[column]; ok {
+				lote[column] = value
+			}
+		}
+		keys = append(keys, key)
+		lotes[key] = lote
+	}
+	sort.Strings(keys)
+
+	result := make([]map[string]string, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, lotes[key])
+	}
+	return result
+}
+
+// normalizar converte o valor de uma coluna para comparação
+func normalizar(column, value string) string {
+	value = strings.TrimSpace(value)
+	switch {
+	case strings.HasPrefix(column, "DT_"):
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t.Format("2006-01-02 15:04:05")
+			}
+		}
+	case strings.HasPrefix(column, "VL_") && decimalRegex.MatchString(value):
+		value = strings.TrimRight(strings.TrimRight(value, "0"), ".")
+		if value == "" || value == "-" || value == "-0" {
+			return "0"
+		}
+	}
+	return value
+}
+
+// newLinha monta a linha a partir dos valores lidos (sem aspas; nulos vazios),
+// exigindo NR_GUIA numérico
+func newLinha(origem string, valores map[string]string) (Linha, error) {
+	linha := Linha{Origem: origem, Valores: map[string]string{}}
+	for column, value := range valores {
+		if !ignoredColumns[column] {
+			linha.Valores[column] = normalizar(column, value)
+		}
+	}
+	guia, err := strconv.Atoi(linha.Valores["NR_GUIA"])
+	if err != nil {
+		return Linha{}, fmt.Errorf("NR_GUIA inválido: %q", valores["NR_GUIA"])
+	}
+	linha.Guia = guia
+	return linha, nil
+}
+
+// ParseScript lê as linhas de FarrDarmsPagos de um script gerado
+func ParseScript(origem, script string) ([]Linha, error) {
+	rows, err := sqlgen.ParseFarrDarmsPagos(script)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar %s: %v", origem, err)
+	}
+
+	linhas := make([]Linha, 0, len(rows))
+	for i, row := range rows {
+		valores := map[string]string{}
+		for column, literal := range row {
+			// Expressões calculadas no banco não têm valor conhecido no script
+			if value, _, ok := sqlgen.LiteralValue(literal); ok {
+				valores[column] = value
+			}
+		}
+		linha, err := newLinha(origem, valores)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao interpretar %s: linha %d: %v", origem, i+1, err)
+		}
+		linhas = append(linhas, linha)
+	}
+	return linhas, nil
+}
+
+// ReadInserts lê as linhas geradas no diretório de inserts: todas as do
+// INSERT_TODOS_DARMs.sql e, dos INSERT_DARM_PAGO_*.sql, as das guias que não
+// estão no script único
+func ReadInserts(dir string) ([]Linha, error) {
+	linhas := []Linha{}
+	guias := map[int]bool{}
+
+	content, err := os.ReadFile(filepath.Join(dir, ConsolidatedFile))
+	switch {
+	case err == nil:
+		consolidadas, err := ParseScript(ConsolidatedFile, string(content))
+		if err != nil {
+			return nil, err
+		}
+		for _, linha := range consolidadas {
+			guias[linha.Guia] = true
+		}
+		linhas = append(linhas, consolidadas...)
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("erro ao ler %s: %v", ConsolidatedFile, err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, perGuiaPattern))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar scripts por guia: %v", err)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler %s: %v", filepath.Base(path), err)
+		}
+		individuais, err := ParseScript(filepath.Base(path), string(content))
+		if err != nil {
+			return nil, err
+		}
+		for _, linha := range individuais {
+			if !guias[linha.Guia] {
+				linhas = append(linhas, linha)
+			}
+		}
+	}
+
+	if len(linhas) == 0 {
+		return nil, fmt.Errorf("nenhuma linha de FarrDarmsPagos encontrada em %s", dir)
+	}
+	return linhas, nil
+}
+
+// Diferenca é uma coluna com valor diferente entre o script e o banco
+type Diferenca struct {
+	Coluna   string `json:"coluna"`
+	Esperado string `json:"esperado"`
+	Banco    string `json:"banco"`
+}
+
+// Item é o resultado da verificação de uma linha
+type Item struct {
+	Status string `json:"status"`
+	Guia   int    `json:"guia"`
+	// Arquivo é o script da linha gerada (vazio em linhas extras)
+	Arquivo    string      `json:"arquivo,omitempty"`
+	Chave      string      `json:"chave"`
+	Diferencas []Diferenca `json:"diferencas,omitempty"`
+}
+
+// Verificacao é o resultado da comparação entre os scripts e o banco
+type Verificacao struct {
+	GeradoEm time.Time `json:"geradoEm"`
+	// Inserts é o diretório dos scripts e Fonte, o banco ou o CSV lido
+	Inserts string `json:"inserts"`
+	Fonte   string `json:"fonte"`
+	Itens   []Item `json:"itens"`
+}
+
+// Contagem retorna a quantidade de itens com o status informado
+func (v *Verificacao) Contagem(status string) int {
+	count := 0
+	for _, item := range v.Itens {
+		if item.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Consistente indica que todas as linhas geradas estão no banco, iguais, sem linhas extras
+func (v *Verificacao) Consistente() bool {
+	return v.Contagem(StatusOK) == len(v.Itens)
+}
+
+// Guias retorna as guias das linhas geradas, sem repetição e em ordem
+func Guias(linhas []Linha) []int {
+	seen := map[int]bool{}
+	guias := []int{}
+	for _, linha := range linhas {
+		if !seen[linha.Guia] {
+			seen[linha.Guia] = true
+			guias = append(guias, linha.Guia)
+		}
+	}
+	sort.Ints(guias)
+	return guias
+}
+
+// mesmaChave compara as colunas de identificação conhecidas no script
+func mesmaChave(gerada, banco Linha) bool {
+	for _, column := range sqlgen.KeyColumns() {
+		if value, ok := gerada.Valores[column]; ok && value != banco.Valores[column] {
+			return false
+		}
+	}
+	return true
+}
+
+// comparar retorna as colunas do script com valor diferente no banco
+func comparar(gerada, banco Linha) []Diferenca {
+	columns := make([]string, 0, len(gerada.Valores))
+	for column := range gerada.Valores {
+		columns = append(columns, column)
+	}
+	order := map[string]int{}
+	for i, column := range sqlgen.Columns() {
+		order[column] = i
+	}
+	sort.Slice(columns, func(i, j int) bool { return order[columns[i]] < order[columns[j]] })
+
+	diferencas := []Diferenca{}
+	for _, column := range columns {
+		// Colunas fora do dump não são comparadas
+		value, ok := banco.Valores[column]
+		if ok && gerada.Valores[column] != value {
+			diferencas = append(diferencas, Diferenca{Coluna: column, Esperado: gerada.Valores[column], Banco: value})
+		}
+	}
+	return diferencas
+}
+
+// Verificar compara as linhas geradas com as do banco, guia a guia. Cada linha
+// gerada é associada à linha do banco com a mesma chave (lote, guia e SQ_DOC
+// fixo) ou, sem ela, a outra linha ainda livre da mesma guia, cujas colunas
+// diferentes (inclusive as da chave) são listadas. Linhas do banco de guias
+// que não constam dos scripts ou de outros lotes de arrecadação (NR_GUIA se
+// repete entre lotes) não são consideradas.
+func Verificar(geradas, banco []Linha) *Verificacao {
+	v := &Verificacao{GeradoEm: time.Now(), Itens: []Item{}}
+
+	lotes := map[string]bool{}
+	for _, gerada := range geradas {
+		lotes[gerada.Lote()] = true
+	}
+	porGuia := map[int][]int{}
+	for i, linha := range banco {
+		if lotes[linha.Lote()] {
+			porGuia[linha.Guia] = append(porGuia[linha.Guia], i)
+		}
+	}
+	usada := make([]bool, len(banco))
+	associada := make([]int, len(geradas))
+
+	// Primeiro as linhas com a mesma chave, depois as demais da mesma guia
+	for i := range associada {
+		associada[i] = -1
+	}
+	for _, exata := range []bool{true, false} {
+		for i, gerada := range geradas {
+			if associada[i] >= 0 {
+				continue
+			}
+			for _, j := range porGuia[gerada.Guia] {
+				if !usada[j] && (!exata || mesmaChave(gerada, banco[j])) {
+					usada[j], associada[i] = true, j
+					break
+				}
+			}
+		}
+	}
+
+	for i, gerada := range geradas {
+		item := Item{Status: StatusAusente, Guia: gerada.Guia, Arquivo: gerada.Origem, Chave: gerada.Chave()}
+		if j := associada[i]; j >= 0 {
+			item.Status = StatusOK
+			if item.Diferencas = comparar(gerada, banco[j]); len(item.Diferencas) > 0 {
+				item.Status = StatusDivergente
+			}
+		}
+		v.Itens = append(v.Itens, item)
+	}
+
+	for _, guia := range Guias(geradas) {
+		for _, j := range porGuia[guia] {
+			if !usada[j] {
+				v.Itens = append(v.Itens, Item{Status: StatusExtra, Guia: guia, Chave: banco[j].Chave()})
+			}
+		}
+	}
+	return v
+}
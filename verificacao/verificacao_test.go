@@ -0,0 +1,161 @@
+package verificacao
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gerador-query-darm-go/internal/darmtest"
+	"gerador-query-darm-go/sqlgen"
+	"gerador-query-darm-go/validation"
+)
+
+// testRow monta a linha gerada para a guia informada
+func testRow(t *testing.T, guia int) sqlgen.DarmRow {
+	t.Helper()
+	record, err := validation.Parse(darmtest.DarmData(fmt.Sprintf("%07d.pdf", guia)), validation.Options{})
+	if err != nil {
+		t.Fatalf("Parse falhou: %v", err)
+	}
+	return sqlgen.RowFromRecord(record)
+}
+
+// writeInserts grava um diretório de inserts com o script único das guias 1 e
+// 2 (SQ_DOC 1000 e 2000) e os scripts individuais das guias 2 e 3
+func writeInserts(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	rows := []sqlgen.DarmRow{testRow(t, 1), testRow(t, 2)}
+	rows[0]["SQ_DOC"], rows[1]["SQ_DOC"] = 1000, 2000
+
+	script, err := sqlgen.Generate(rows, sqlgen.ScriptOptions{Insert: sqlgen.InsertOptions{Conflict: sqlgen.ConflictIgnore}, UseTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{ConsolidatedFile: string(script)}
+	for _, guia := range []int{2, 3} {
+		insert, err := sqlgen.RenderInsert(testRow(t, guia), sqlgen.InsertOptions{Conflict: sqlgen.ConflictIgnore})
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[fmt.Sprintf("INSERT_DARM_PAGO_%d.sql", guia)] = "use silfae;\n\n" + insert
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// dumpHeader são as colunas do dump de teste (sem NR_LOTE_IPTU e as demais nulas)
+const dumpHeader = "id;AA_EXERCICIO;CD_BANCO;NR_BDA;NR_COMPLEMENTO;NR_LOTE_NSA;TP_LOTE_D;SQ_DOC;CD_RECEITA;CD_USU_INCL;DT_INCL;" +
+	"DT_VENCTO;DT_PAGTO;NR_INSCRICAO;NR_GUIA;NR_COMPETENCIA;NR_CODIGO_BARRAS;ST_DOC_D;VL_PAGO;VL_RECEITA;VL_PRINCIPAL;VL_MORA;VL_MULTA;VL_JUROS;processado\n"
+
+// dumpLine gera uma linha do dump como o banco a exporta (DATE, DECIMAL(15,4))
+func dumpLine(id, guia, sqDoc int, vlPago, codigoBarras string) string {
+	return fmt.Sprintf("%d;2025;70;37;0;730;1;%d;2623;FARR;2025-01-10 08:00:00;2024-12-15;2025-01-10 08:00:00;9%07d;%d;2024;%s;13;%s;1234.5600;1234.5600;0.0000;0.0000;0.0000;1\n",
+		id, sqDoc, guia, guia, codigoBarras, vlPago)
+}
+
+func TestReadInserts(t *testing.T) {
+	linhas, err := ReadInserts(writeInserts(t))
+	if err != nil {
+		t.Fatalf("ReadInserts falhou: %v", err)
+	}
+	if len(linhas) != 3 || Guias(linhas)[2] != 3 {
+		t.Fatalf("linhas inesperadas: %+v", linhas)
+	}
+
+	// O script único tem precedência; do individual só a guia 3, sem SQ_DOC fixo
+	if linhas[1].Origem != ConsolidatedFile || linhas[1].Valores["SQ_DOC"] != "2000" {
+		t.Errorf("guia 2 deveria vir do script único: %+v", linhas[1])
+	}
+	if _, ok := linhas[2].Valores["SQ_DOC"]; ok || linhas[2].Origem != "INSERT_DARM_PAGO_3.sql" {
+		t.Errorf("guia 3 deveria vir do script individual sem SQ_DOC: %+v", linhas[2])
+	}
+	if linhas[0].Valores["DT_VENCTO"] != "2024-12-15 00:00:00" || linhas[0].Valores["VL_PAGO"] != "1234.56" || linhas[0].Valores["NR_INSCRICAO"] != "90000001" {
+		t.Errorf("valores inesperados: %+v", linhas[0].Valores)
+	}
+
+	if _, err := ReadInserts(t.TempDir()); err == nil {
+		t.Error("diretório sem scripts deveria falhar")
+	}
+}
+
+func TestVerificar(t *testing.T) {
+	geradas, err := ReadInserts(writeInserts(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dump := dumpHeader +
+		// Guia 1 conferida (formatos de DATE e DECIMAL do banco)
+		dumpLine(10, 1, 1000, "1234.5600", "NULL") +
+		// Guia 2 com valor pago e código de barras diferentes
+		dumpLine(11, 2, 2000, "1000.0000", "81600000001") +
+		// Guia 1 carregada novamente com outro SQ_DOC
+		dumpLine(12, 1, 1500, "1234.5600", "NULL") +
+		// Guia fora dos scripts: ignorada
+		dumpLine(13, 99, 99000, "1234.5600", "NULL") +
+		// Mesma guia 1 em outro lote de arrecadação: ignorada
+		strings.Replace(dumpLine(14, 1, 900, "10.0000", "NULL"), ";730;", ";731;", 1) +
+		// Mesma guia 3 (ausente no lote dos scripts) em outro lote: não conta como carregada
+		strings.Replace(dumpLine(15, 3, 3000, "1234.5600", "NULL"), ";70;", ";1;", 1)
+	banco, err := ParseCSV(strings.NewReader(dump), "dump.csv")
+	if err != nil {
+		t.Fatalf("ParseCSV falhou: %v", err)
+	}
+
+	v := Verificar(geradas, banco)
+	expected := []struct {
+		status string
+		guia   int
+	}{{StatusOK, 1}, {StatusDivergente, 2}, {StatusAusente, 3}, {StatusExtra, 1}}
+	if len(v.Itens) != len(expected) {
+		t.Fatalf("esperados %d itens, obtidos %d: %+v", len(expected), len(v.Itens), v.Itens)
+	}
+	for i, e := range expected {
+		if v.Itens[i].Status != e.status || v.Itens[i].Guia != e.guia {
+			t.Errorf("item %d inesperado: %+v", i, v.Itens[i])
+		}
+	}
+
+	diferencas := v.Itens[1].Diferencas
+	if len(diferencas) != 2 || diferencas[0].Coluna != "NR_CODIGO_BARRAS" || diferencas[1] != (Diferenca{Coluna: "VL_PAGO", Esperado: "1234.56", Banco: "1000"}) {
+		t.Errorf("diferenças inesperadas: %+v", diferencas)
+	}
+	if !strings.Contains(v.Itens[3].Chave, "SQ_DOC=1500") {
+		t.Errorf("linha extra deveria ser identificada pela chave: %q", v.Itens[3].Chave)
+	}
+	if v.Consistente() {
+		t.Error("verificação com divergências não é consistente")
+	}
+
+	dir := t.TempDir()
+	if err := WriteRelatorio(dir, v); err != nil {
+		t.Fatalf("WriteRelatorio falhou: %v", err)
+	}
+	md, err := os.ReadFile(filepath.Join(dir, RelatorioBase+".md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(md), `VL_PAGO: "1234.56" ≠ "1000"`) || !strings.Contains(string(md), "❌ Ausentes no banco: 1") {
+		t.Errorf("relatório inesperado:\n%s", md)
+	}
+}
+
+func TestParseCSVInvalid(t *testing.T) {
+	invalid := map[string]string{
+		"vazio":     "",
+		"sem guia":  "id,SQ_DOC\n1,1000\n",
+		"guia nula": "NR_GUIA\tSQ_DOC\n\\N\t1000\n",
+	}
+	for name, content := range invalid {
+		if _, err := ParseCSV(strings.NewReader(content), "dump.csv"); err == nil {
+			t.Errorf("%s: deveria falhar", name)
+		}
+	}
+}